@@ -0,0 +1,89 @@
+package hilink
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Error codes returned by the device API (see ErrorCodeMessageMap).
+const (
+	ErrorCodeSystemNotAvailable     = -1
+	ErrorCodeNotSupported           = 100002
+	ErrorCodeUnauthorized           = 100003
+	ErrorCodeSystemBusy             = 100004
+	ErrorCodeUnknown                = 100005
+	ErrorCodeInvalidParameter       = 100006
+	ErrorCodeWriteError             = 100009
+	ErrorCodeInvalidUsername        = 108001
+	ErrorCodeInvalidPassword        = 108002
+	ErrorCodeAlreadyLoggedIn        = 108003
+	ErrorCodeWrongPassword          = 108006
+	ErrorCodeWrongPasswordOrTimeout = 108007
+	ErrorCodeLowBattery             = 110024
+	ErrorCodeNoNetworkResponse      = 111019
+	ErrorCodeNetworkTimeout         = 111020
+	ErrorCodeNetworkNotSupported    = 111022
+	ErrorCodeSmsFull                = 113018
+	ErrorCodeFileExists             = 114001
+	ErrorCodeSDCardInUse            = 114003
+	ErrorCodePathNotFound           = 114004
+	ErrorCodePathTooLong            = 114005
+	ErrorCodeNoPermission           = 114006
+	ErrorCodeWrongWifiPassword      = 117001
+	ErrorCodeWrongWISPrPassword     = 117004
+	ErrorCodeVoiceBusy              = 120001
+	ErrorCodeInvalidToken           = 125001
+	ErrorCodeInvalidSession         = 125002
+	ErrorCodeInvalidSessionToken    = 125003
+)
+
+// APIError is an error returned by the device API, as an <error/> response.
+type APIError struct {
+	// Code is the error code (ie, ErrorCodeNotSupported).
+	Code int
+
+	// Message is the error message returned by the device, or the message
+	// of the code (see ErrorMessage) when none was returned.
+	Message string
+}
+
+// Error satisfies the error interface.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("hilink error %d: %s", e.Code, e.Message)
+}
+
+// Is determines if the target is an APIError with the same code, so that
+// errors can be matched with errors.Is:
+//
+//	if errors.Is(err, &hilink.APIError{Code: hilink.ErrorCodeWrongPassword}) {
+//		// ...
+//	}
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	return ok && t.Code == e.Code
+}
+
+// ErrorCode returns the code of the APIError in the err chain, returning
+// false when err is not an APIError.
+func ErrorCode(err error) (int, bool) {
+	var e *APIError
+	if !errors.As(err, &e) {
+		return 0, false
+	}
+	return e.Code, true
+}
+
+// hilinkError builds the error for an <error/> response returned by the api.
+func hilinkError(code, msg string) error {
+	// grab message if not passed by the api
+	if msg == "" {
+		msg = ErrorMessage(DefaultLanguage, code)
+	}
+
+	n, _ := strconv.Atoi(code)
+	return &APIError{
+		Code:    n,
+		Message: msg,
+	}
+}
@@ -0,0 +1,20 @@
+package hilink
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/sms/send-sms"] = `<error><code>113018</code><message></message></error>`
+
+	_, err := d.client(t).SmsSend("test", "+6281234")
+	if !errors.Is(err, &APIError{Code: ErrorCodeSmsFull}) {
+		t.Fatalf("expected sms full error, got: %v", err)
+	}
+	if exp := "hilink error 113018: SMS storage full"; err.Error() != exp {
+		t.Errorf("expected %q, got: %q", exp, err.Error())
+	}
+}
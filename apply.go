@@ -0,0 +1,248 @@
+package hilink
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// profilesEndpoint is the endpoint of the connection profiles, which is
+// reconciled by profile name rather than by field.
+const profilesEndpoint = "dialup/profiles"
+
+// PlanStep is a step of a Plan, updating a single endpoint.
+type PlanStep struct {
+	// Endpoint is the updated endpoint (ie, "wlan/basic-settings").
+	Endpoint string
+
+	// Changes are the changes made by the step.
+	Changes []SnapshotChange
+
+	req XMLData
+}
+
+// Plan is the set of changes reconciling a device to a desired state (see
+// Client.Plan).
+type Plan struct {
+	Steps []PlanStep
+}
+
+// Changes returns the changes of all steps.
+func (p *Plan) Changes() []SnapshotChange {
+	var changes []SnapshotChange
+	for _, s := range p.Steps {
+		changes = append(changes, s.Changes...)
+	}
+	return changes
+}
+
+// Empty determines if the plan has no changes.
+func (p *Plan) Empty() bool {
+	return len(p.Steps) == 0
+}
+
+// String satisfies the fmt.Stringer interface, listing the changes one per
+// line.
+func (p *Plan) String() string {
+	var lines []string
+	for _, c := range p.Changes() {
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Plan determines the changes needed to reconcile the device to the desired
+// state, for example:
+//
+//	{
+//		"wlan/basic-settings": {"WifiSsid": "home", "WifiHide": "0"},
+//		"dhcp/settings": {"DhcpStartIPAddress": "192.168.8.100"},
+//		"security/upnp": {"UpnpStatus": "0"},
+//		"dialup/profiles": {
+//			"CurrentProfile": "2",
+//			"Profiles": {"Profile": [{"Name": "work", "ApnName": "internet"}]}
+//		}
+//	}
+//
+// The desired state uses the same endpoints and elements as a Snapshot, but
+// only lists the values to be changed. Settings endpoints are updated by
+// posting the current settings with the desired values merged in.
+// Connection profiles are matched by name, adding missing profiles and
+// modifying changed ones; profiles not listed are left in place.
+func (c *Client) Plan(desired Snapshot) (*Plan, error) {
+	endpoints := make([]string, 0, len(desired))
+	for endpoint := range desired {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+
+	p := new(Plan)
+	for _, endpoint := range endpoints {
+		cur, err := c.Do("api/"+endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("plan %s: %w", endpoint, err)
+		}
+
+		want := normalizeXML(map[string]interface{}(desired[endpoint])).(map[string]interface{})
+		if endpoint == profilesEndpoint {
+			p.Steps = append(p.Steps, planProfiles(cur, want)...)
+			continue
+		}
+
+		merged := mergeXML(map[string]interface{}(cur), want)
+		changes := DiffSnapshots(Snapshot{endpoint: cur}, Snapshot{endpoint: XMLData(merged)})
+		if len(changes) != 0 {
+			p.Steps = append(p.Steps, PlanStep{
+				Endpoint: endpoint,
+				Changes:  changes,
+				req:      XMLData(merged),
+			})
+		}
+	}
+
+	return p, nil
+}
+
+// Apply applies the plan steps in order, stopping at the first failed step.
+func (c *Client) Apply(p *Plan) error {
+	for _, s := range p.Steps {
+		if err := checkOK(c.doReqCheckOK("api/"+s.Endpoint, s.req)); err != nil {
+			return fmt.Errorf("apply %s: %w", s.Endpoint, err)
+		}
+	}
+	return nil
+}
+
+// planProfiles plans the connection profile changes.
+func planProfiles(cur XMLData, want map[string]interface{}) []PlanStep {
+	// index current profiles by name
+	existing := make(map[string]map[string]interface{})
+	if ps, ok := cur["Profiles"].(map[string]interface{}); ok {
+		for _, p := range xmlList(ps["Profile"]) {
+			if name, ok := p["Name"].(string); ok {
+				existing[name] = p
+			}
+		}
+	}
+
+	// keep the current default when modifying
+	def, _ := cur["CurrentProfile"].(string)
+
+	var steps []PlanStep
+	if ps, ok := want["Profiles"].(map[string]interface{}); ok {
+		for _, p := range xmlList(ps["Profile"]) {
+			name, _ := p["Name"].(string)
+			key := fmt.Sprintf("%s.Profile[%s]", profilesEndpoint, name)
+
+			// add missing profile
+			old, ok := existing[name]
+			if !ok {
+				profile := newProfileXML(toResource(p))
+				steps = append(steps, PlanStep{
+					Endpoint: profilesEndpoint,
+					Changes:  []SnapshotChange{{Key: key, New: profile}},
+					req:      profileReq("1", "0", profile),
+				})
+				continue
+			}
+
+			// modify changed profile
+			profile := mergeXML(old, p)
+			changes := DiffSnapshots(Snapshot{key: XMLData(old)}, Snapshot{key: XMLData(profile)})
+			if len(changes) != 0 {
+				steps = append(steps, PlanStep{
+					Endpoint: profilesEndpoint,
+					Changes:  changes,
+					req:      profileReq("2", def, profile),
+				})
+			}
+		}
+	}
+
+	// set default profile
+	if idx, ok := want["CurrentProfile"].(string); ok && idx != def {
+		steps = append(steps, PlanStep{
+			Endpoint: profilesEndpoint,
+			Changes: []SnapshotChange{{
+				Key: profilesEndpoint + ".CurrentProfile",
+				Old: def,
+				New: idx,
+			}},
+			req: XMLData{
+				"Delete":     "0",
+				"SetDefault": idx,
+				"Modify":     "0",
+			},
+		})
+	}
+
+	return steps
+}
+
+// xmlList returns v as a list of elements, as a repeated XML element decodes
+// as a slice, but a single one as a map.
+func xmlList(v interface{}) []map[string]interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{x}
+	case []interface{}:
+		var l []map[string]interface{}
+		for _, z := range x {
+			if m, ok := z.(map[string]interface{}); ok {
+				l = append(l, m)
+			}
+		}
+		return l
+	}
+	return nil
+}
+
+// mergeXML returns a copy of cur with the values of want merged in. Nested
+// elements are merged, while lists are replaced.
+func mergeXML(cur, want map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(cur))
+	for k, v := range cur {
+		m[k] = v
+	}
+	for k, v := range want {
+		a, ok1 := m[k].(map[string]interface{})
+		b, ok2 := v.(map[string]interface{})
+		if ok1 && ok2 {
+			m[k] = mergeXML(a, b)
+			continue
+		}
+		m[k] = v
+	}
+	return m
+}
+
+// normalizeXML converts the values of a decoded desired state document (ie,
+// JSON numbers and booleans) to the string values used by the WebUI.
+func normalizeXML(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, z := range x {
+			m[k] = normalizeXML(z)
+		}
+		return m
+	case XMLData:
+		return normalizeXML(map[string]interface{}(x))
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, z := range x {
+			l[i] = normalizeXML(z)
+		}
+		return l
+	case bool:
+		return boolToString(x)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(x)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
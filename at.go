@@ -0,0 +1,26 @@
+package hilink
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ATCommand sends an AT command (ie, "AT^SYSINFOEX") via the HTTP
+// passthrough of firmware that supports it, returning the raw response.
+//
+// The device generally needs to be in debug mode (see DeviceModeSet), and
+// the client must be created with the EnableATCommands option.
+func (c *Client) ATCommand(cmd string) (string, error) {
+	if !c.atcmd {
+		return "", ErrATCommandsDisabled
+	}
+
+	cmd = strings.TrimSpace(cmd)
+	if !strings.HasPrefix(strings.ToUpper(cmd), "AT") || strings.ContainsAny(cmd, "\r\n") {
+		return "", fmt.Errorf("%w %q", ErrInvalidATCommand, cmd)
+	}
+
+	return c.doReqString("api/device/atcommand", SimpleRequestXML(
+		"command", cmd,
+	), "response")
+}
@@ -0,0 +1,195 @@
+package hilink
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clbanning/mxj"
+)
+
+// AuditEntry is an audit journal entry, recording a mutating request sent
+// to the device.
+type AuditEntry struct {
+	// Time is the request time.
+	Time time.Time `json:"time"`
+
+	// Actor is the actor label given with the Audit option.
+	Actor string `json:"actor,omitempty"`
+
+	// Endpoint is the endpoint path (ie, "api/device/control").
+	Endpoint string `json:"endpoint"`
+
+	// Params are the request parameters, with secrets (ie, passwords, PINs
+	// and keys) redacted.
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// Result is the result reported by the device (ie, "OK"), empty when
+	// the request failed.
+	Result string `json:"result,omitempty"`
+
+	// Error is the error encountered, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditSink records audit journal entries.
+type AuditSink interface {
+	Record(AuditEntry)
+}
+
+// AuditFunc is a func satisfying the AuditSink interface.
+type AuditFunc func(AuditEntry)
+
+// Record satisfies the AuditSink interface.
+func (f AuditFunc) Record(e AuditEntry) {
+	f(e)
+}
+
+// auditWriter is an AuditSink writing JSON lines.
+type auditWriter struct {
+	w io.Writer
+	sync.Mutex
+}
+
+// AuditWriter returns an AuditSink writing each entry as a line of JSON to
+// w (ie, a log file opened for appending).
+func AuditWriter(w io.Writer) AuditSink {
+	return &auditWriter{w: w}
+}
+
+// Record satisfies the AuditSink interface.
+func (a *auditWriter) Record(e AuditEntry) {
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	_, _ = a.w.Write(append(buf, '\n'))
+}
+
+// Audit is an option that records every mutating request sent to the
+// device (ie, reboots, setting changes and SMS sent) to the sink, labeled
+// with actor (ie, the user or service sharing access to the device).
+// Read-only requests (ie, SMS and contact listings, and logins) are not
+// recorded. The sink is called while the client is busy, and must not send
+// requests with the client.
+func Audit(sink AuditSink, actor string) Option {
+	return func(c *Client) error {
+		c.audit = sink
+		c.actor = actor
+		return nil
+	}
+}
+
+// auditReadOnly are the endpoints of requests that do not change the device
+// settings or state.
+var auditReadOnly = map[string]bool{
+	"api/pb/group-list":             true,
+	"api/pb/pb-list":                true,
+	"api/sms/sms-list":              true,
+	"api/user/authentication_login": true,
+	"api/user/challenge_login":      true,
+	"api/user/login":                true,
+}
+
+// audited determines if the request to the endpoint path is recorded.
+func (c *Client) audited(path string, v interface{}) bool {
+	return c.audit != nil && v != nil && !auditReadOnly[path]
+}
+
+// auditRedact are the parameter name substrings of secrets.
+var auditRedact = []string{"password", "pwd", "pin", "puk", "key", "token", "secret", "psk"}
+
+// auditRequest records a mutating request to the audit sink.
+func (c *Client) auditRequest(path string, v interface{}, status int, body []byte, err error) {
+	e := AuditEntry{
+		Time:     time.Now(),
+		Actor:    c.actor,
+		Endpoint: path,
+		Params:   auditParams(v),
+	}
+
+	switch {
+	case err != nil:
+		e.Error = err.Error()
+	case status != http.StatusOK:
+		e.Error = ErrBadStatusCode.Error()
+	default:
+		res, err := decodeXML(body, false)
+		if err != nil {
+			e.Error = err.Error()
+			break
+		}
+		if m, ok := res.(mxj.Map); ok {
+			if s, ok := m["response"].(string); ok {
+				e.Result = s
+			}
+		}
+	}
+
+	c.audit.Record(e)
+}
+
+// auditParams returns the redacted request parameters.
+func auditParams(v interface{}) map[string]interface{} {
+	var m map[string]interface{}
+	switch x := v.(type) {
+	case XMLData:
+		m = x
+	case []byte:
+		d, err := mxj.NewMapXml(x)
+		if err != nil {
+			return nil
+		}
+		m, _ = d["request"].(map[string]interface{})
+	}
+	if m == nil {
+		return nil
+	}
+
+	p, _ := redact(m).(map[string]interface{})
+	return p
+}
+
+// redact returns a copy of v, with the values of secret parameters redacted.
+func redact(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			if isSecret(k) {
+				m[k] = "REDACTED"
+				continue
+			}
+			m[k] = redact(v)
+		}
+		return m
+	case XMLData:
+		return redact(map[string]interface{}(x))
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, v := range x {
+			l[i] = redact(v)
+		}
+		return l
+	}
+	return fmt.Sprint(v)
+}
+
+// isSecret determines if the parameter name is that of a secret.
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range auditRedact {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
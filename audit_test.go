@@ -0,0 +1,34 @@
+package hilink
+
+import (
+	"testing"
+)
+
+func TestAuditReadOnly(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/sms/sms-list"] = `<response><Count>0</Count></response>`
+
+	var entries []AuditEntry
+	c := d.client(t)
+	if err := Audit(AuditFunc(func(e AuditEntry) {
+		entries = append(entries, e)
+	}), "test")(c); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// listings are not recorded
+	if _, err := c.SmsList(SmsListOptions{}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no entries, got: %+v", entries)
+	}
+
+	if _, err := c.DeviceReboot(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Endpoint != "api/device/control" || entries[0].Result != "OK" {
+		t.Fatalf("expected reboot entry, got: %+v", entries)
+	}
+}
@@ -0,0 +1,39 @@
+package hilink
+
+import (
+	"testing"
+)
+
+func TestAuthPasswordType(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy AuthStrategy
+		typ      string
+		password string
+	}{
+		{"type3", AuthPasswordType3, "3", "c2VjcmV0"},
+		{"type4", AuthPasswordType4, "4", "YzU3ZDVmOGY5MGIwN2FhMGYyYTY3NTBiZjYwODc1N2E1OTE2YzNmMzVhZmRlNjEwZTViNmM5ODI3YjA5ZjZhNg"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := newFakeDevice()
+			defer d.Close()
+
+			_, err := NewClient(URL(d.URL+"/"), Auth("admin", "secret"), Authentication(test.strategy))
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+
+			req := d.request(t, "/api/user/login")
+			if req["Username"] != "admin" {
+				t.Errorf("expected Username admin, got: %v", req["Username"])
+			}
+			if req["password_type"] != test.typ {
+				t.Errorf("expected password_type %s, got: %v", test.typ, req["password_type"])
+			}
+			if req["Password"] != test.password {
+				t.Errorf("expected Password %s, got: %v", test.password, req["Password"])
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package hilink
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BackupScheduler periodically backs up the device configuration (see
+// DeviceBackupData) to timestamped nvram.bak files, for example:
+//
+//	&BackupScheduler{
+//		Client:   client,
+//		Schedule: "30 3 * * *",
+//		Dir:      "/var/backups/hilink",
+//		Keep:     7,
+//	}
+type BackupScheduler struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Schedule is the cron spec of the backups (see ParseCronSchedule).
+	Schedule string
+
+	// Dir is the directory the backups are saved to.
+	Dir string
+
+	// Create creates the writer for the named backup, used instead of Dir.
+	Create func(name string) (io.WriteCloser, error)
+
+	// Remove removes the named backup, used with Create when pruning.
+	Remove func(name string) error
+
+	// Keep is the number of backups retained, pruning the oldest. Zero
+	// retains all backups.
+	Keep int
+
+	// OnBackup is called with the name of each saved backup.
+	OnBackup func(name string)
+
+	// OnError is called when a backup fails.
+	OnError func(error)
+
+	names []string
+}
+
+// backupPrefix and backupSuffix enclose the backup timestamp in backup file
+// names.
+const (
+	backupPrefix = "nvram-"
+	backupSuffix = ".bak"
+)
+
+// Run runs the scheduler until the context is closed.
+func (b *BackupScheduler) Run(ctx context.Context) error {
+	if b.Client == nil {
+		return ErrNilClient
+	}
+
+	sched, err := ParseCronSchedule(b.Schedule)
+	if err != nil {
+		return err
+	}
+
+	// retrieve previous backups
+	if b.Create == nil {
+		if b.names, err = backupNames(b.Dir); err != nil {
+			return err
+		}
+	}
+
+	for {
+		next := sched.Next(time.Now())
+		if next.IsZero() {
+			return ErrInvalidCronSpec
+		}
+
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		if err := b.Backup(next); err != nil && b.OnError != nil {
+			b.OnError(err)
+		}
+	}
+}
+
+// Backup saves a backup timestamped with t, and prunes the oldest backups.
+func (b *BackupScheduler) Backup(t time.Time) error {
+	data, err := b.Client.DeviceBackupData()
+	if err != nil {
+		return err
+	}
+
+	name := backupPrefix + t.Format("20060102-150405") + backupSuffix
+	w, err := b.create(name)
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	b.names = append(b.names, name)
+	if b.OnBackup != nil {
+		b.OnBackup(name)
+	}
+
+	return b.prune()
+}
+
+// create creates the writer for the named backup.
+func (b *BackupScheduler) create(name string) (io.WriteCloser, error) {
+	if b.Create != nil {
+		return b.Create(name)
+	}
+	return os.Create(filepath.Join(b.Dir, name))
+}
+
+// prune removes the oldest backups exceeding Keep.
+func (b *BackupScheduler) prune() error {
+	if b.Keep <= 0 || len(b.names) <= b.Keep {
+		return nil
+	}
+
+	n := len(b.names) - b.Keep
+	for _, name := range b.names[:n] {
+		var err error
+		switch {
+		case b.Create == nil:
+			err = os.Remove(filepath.Join(b.Dir, name))
+		case b.Remove != nil:
+			err = b.Remove(name)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	b.names = b.names[n:]
+
+	return nil
+}
+
+// backupNames returns the names of the backups in dir, oldest first.
+func backupNames(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, fi := range files {
+		if n := fi.Name(); !fi.IsDir() && strings.HasPrefix(n, backupPrefix) && strings.HasSuffix(n, backupSuffix) {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
@@ -0,0 +1,33 @@
+package hilink
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDeviceBackupData(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/nvram.bak"] = "\x00\x01backup"
+
+	data, err := d.client(t).DeviceBackupData()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !bytes.HasSuffix(data, []byte("\x00\x01backup")) {
+		t.Errorf("expected backup data, got: %q", data)
+	}
+}
+
+func TestDeviceBackupDataError(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/nvram.bak"] = `<error><code>125002</code><message></message></error>`
+
+	_, err := d.client(t).DeviceBackupData()
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 125002 {
+		t.Errorf("expected device error 125002, got: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package hilink
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// LTEBandAll is the LTE band mask allowing all bands.
+const LTEBandAll = "7FFFFFFFFFFFFFFF"
+
+// LTEBandMask returns the hex LTE band mask (as used by ModeSet) of the LTE
+// band numbers (ie, 3 and 7 for "44"). No bands returns LTEBandAll. Band
+// numbers outside 1 to 256 are ignored.
+func LTEBandMask(bands ...int) string {
+	if len(bands) == 0 {
+		return LTEBandAll
+	}
+
+	mask := new(big.Int)
+	for _, b := range bands {
+		if validBand(b) {
+			mask.SetBit(mask, b-1, 1)
+		}
+	}
+	return mask.Text(16)
+}
+
+// validBand determines if b is a valid LTE band number.
+func validBand(b int) bool {
+	return b >= 1 && b <= 256
+}
+
+// LTEBands returns the sorted LTE band numbers of the hex LTE band mask
+// (ie, 3 and 7 for "44").
+func LTEBands(mask string) ([]int, error) {
+	m, ok := new(big.Int).SetString(strings.TrimPrefix(strings.ToLower(mask), "0x"), 16)
+	if !ok || m.Sign() < 0 {
+		return nil, fmt.Errorf("%w mask %q", ErrInvalidBand, mask)
+	}
+
+	var bands []int
+	for i := 0; i < m.BitLen(); i++ {
+		if m.Bit(i) == 1 {
+			bands = append(bands, i+1)
+		}
+	}
+	sort.Ints(bands)
+	return bands, nil
+}
+
+// ModeLTEBands retrieves the LTE bands allowed by the network mode
+// settings.
+func (c *Client) ModeLTEBands() ([]int, error) {
+	var x netModeXML
+	if err := c.doReqXML("api/net/net-mode", nil, &x); err != nil {
+		return nil, err
+	}
+	return LTEBands(x.LTEBand)
+}
+
+// ModeSetLTEBands locks the device to the LTE band numbers (ie, 3 and 7),
+// retaining the other network mode settings. No bands allows all bands.
+func (c *Client) ModeSetLTEBands(bands ...int) (bool, error) {
+	for _, b := range bands {
+		if !validBand(b) {
+			return false, fmt.Errorf("%w %d", ErrInvalidBand, b)
+		}
+	}
+	mask := LTEBandMask(bands...)
+
+	return c.modeModify(func(x *netModeXML) {
+		x.LTEBand = mask
+	})
+}
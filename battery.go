@@ -0,0 +1,236 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Battery is the battery state of E5-series (mobile hotspot) devices.
+type Battery struct {
+	// Percent is the battery charge, in percent.
+	Percent int
+
+	// Charging is the charging flag.
+	Charging bool
+}
+
+// Battery retrieves the battery state of E5-series (mobile hotspot) devices.
+func (c *Client) Battery() (*Battery, error) {
+	s, err := c.Status()
+	if err != nil {
+		return nil, err
+	}
+	return &Battery{
+		Percent:  s.BatteryPercent,
+		Charging: s.BatteryStatus == 1,
+	}, nil
+}
+
+// BatteryWatcher polls the battery state of a device.
+type BatteryWatcher struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Interval is the poll interval. Defaults to 1 minute.
+	Interval time.Duration
+
+	// OnBattery is called when the battery state changes.
+	OnBattery func(Battery)
+
+	// OnError is called when the battery state cannot be retrieved.
+	OnError func(error)
+
+	last *Battery
+}
+
+// Run runs the watcher until the context is closed.
+func (w *BatteryWatcher) Run(ctx context.Context) error {
+	if w.Client == nil {
+		return ErrNilClient
+	}
+
+	interval := w.Interval
+	if interval == 0 {
+		interval = time.Minute
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		b, err := w.Client.Battery()
+		switch {
+		case err != nil && w.OnError != nil:
+			w.OnError(err)
+		case err == nil && (w.last == nil || *b != *w.last):
+			w.last = b
+			if w.OnBattery != nil {
+				w.OnBattery(*b)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// BatteryRule is a battery policy rule: when the battery charge drops below
+// the Below percentage, the Then action is performed. Once charging, or
+// once the charge rises back above the threshold (plus the policy
+// hysteresis), the Restore action, if any, is performed.
+type BatteryRule struct {
+	// Name is the rule name.
+	Name string
+
+	// Below is the battery charge threshold, in percent.
+	Below int
+
+	// Then is the action performed when the charge drops below the
+	// threshold.
+	Then Action
+
+	// Restore is the action performed when the charge recovers.
+	Restore *Action
+}
+
+// BatteryPolicy applies power saving rules to a battery powered device as
+// its battery drains, for example:
+//
+//	restore := ActionScreenTimeout(time.Minute)
+//	policy := &BatteryPolicy{
+//		Client: client,
+//		Rules: []BatteryRule{
+//			{Name: "dim", Below: 30, Then: ActionScreenTimeout(10 * time.Second), Restore: &restore},
+//			{Name: "wifi off", Below: 10, Then: ActionWifiDisable, Restore: &ActionWifiEnable},
+//		},
+//	}
+type BatteryPolicy struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Interval is the battery poll interval. Defaults to 1 minute.
+	Interval time.Duration
+
+	// Rules are the policy rules.
+	Rules []BatteryRule
+
+	// Hysteresis is the margin above the threshold the charge must rise by
+	// before a rule is restored. Defaults to 5 percent.
+	Hysteresis int
+
+	// Logf is the logger (ie, log.Printf) recording performed actions.
+	Logf func(string, ...interface{})
+
+	// OnError is called when an action fails. The action is performed
+	// again with the next battery state.
+	OnError func(error)
+
+	active map[int]bool
+}
+
+// Run runs the policy until the context is closed.
+func (p *BatteryPolicy) Run(ctx context.Context) error {
+	w := &BatteryWatcher{
+		Client:   p.Client,
+		Interval: p.Interval,
+		OnBattery: func(b Battery) {
+			p.Apply(ctx, b)
+		},
+		OnError: func(err error) {
+			p.logf("battery: %v", err)
+		},
+	}
+	return w.Run(ctx)
+}
+
+// Apply applies the rules for the battery state. A rule is active once its
+// action succeeded, and restored once its restore action succeeded. The
+// failed actions are reported to OnError, and the first error is returned.
+func (p *BatteryPolicy) Apply(ctx context.Context, b Battery) error {
+	if p.active == nil {
+		p.active = make(map[int]bool)
+	}
+
+	hysteresis := p.Hysteresis
+	if hysteresis == 0 {
+		hysteresis = 5
+	}
+
+	var first error
+	for i, r := range p.Rules {
+		var err error
+		switch {
+		case !p.active[i] && !b.Charging && b.Percent < r.Below:
+			if err = p.perform(ctx, r, r.Then, b); err == nil {
+				p.active[i] = true
+			}
+
+		case p.active[i] && (b.Charging || b.Percent >= r.Below+hysteresis):
+			if r.Restore != nil {
+				err = p.perform(ctx, r, *r.Restore, b)
+			}
+			if err == nil {
+				p.active[i] = false
+			}
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// perform performs the action of a rule.
+func (p *BatteryPolicy) perform(ctx context.Context, r BatteryRule, a Action, b Battery) error {
+	if err := a.Do(ctx, p.Client); err != nil {
+		p.logf("battery: rule %q (%d%%): %s failed: %v", r.Name, b.Percent, a.Name, err)
+		err = fmt.Errorf("battery rule %q: %s: %w", r.Name, a.Name, err)
+		if p.OnError != nil {
+			p.OnError(err)
+		}
+		return err
+	}
+	p.logf("battery: rule %q (%d%%): performed %s", r.Name, b.Percent, a.Name)
+	return nil
+}
+
+// logf writes to the log.
+func (p *BatteryPolicy) logf(s string, v ...interface{}) {
+	if p.Logf != nil {
+		p.Logf(s, v...)
+	}
+}
+
+// Action values.
+var (
+	// ActionWifiDisable disables the WiFi radio.
+	ActionWifiDisable = Action{
+		Name: "disable wifi",
+		Do: func(ctx context.Context, c *Client) error {
+			return checkOK(c.WifiEnabledSet(false))
+		},
+	}
+
+	// ActionWifiEnable enables the WiFi radio.
+	ActionWifiEnable = Action{
+		Name: "enable wifi",
+		Do: func(ctx context.Context, c *Client) error {
+			return checkOK(c.WifiEnabledSet(true))
+		},
+	}
+)
+
+// ActionScreenTimeout returns the Action setting the screen timeout to d
+// (see ScreenTimeoutSet), ie to dim the screen sooner.
+func ActionScreenTimeout(d time.Duration) Action {
+	return Action{
+		Name: fmt.Sprintf("set screen timeout to %v", d),
+		Do: func(ctx context.Context, c *Client) error {
+			return checkOK(c.ScreenTimeoutSet(d))
+		},
+	}
+}
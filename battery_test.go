@@ -0,0 +1,62 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBatteryPolicyRetry(t *testing.T) {
+	errFailed := errors.New("failed")
+	var fail bool
+	var performed []string
+	action := func(name string) Action {
+		return Action{Name: name, Do: func(context.Context, *Client) error {
+			if fail {
+				return errFailed
+			}
+			performed = append(performed, name)
+			return nil
+		}}
+	}
+	restore := action("restore")
+
+	var reported []error
+	p := &BatteryPolicy{
+		Rules:   []BatteryRule{{Name: "low", Below: 20, Then: action("then"), Restore: &restore}},
+		OnError: func(err error) { reported = append(reported, err) },
+	}
+
+	// a failed action leaves the rule inactive, and is performed again
+	fail = true
+	if err := p.Apply(context.Background(), Battery{Percent: 10}); !errors.Is(err, errFailed) {
+		t.Fatalf("expected failed error, got: %v", err)
+	}
+	if len(reported) != 1 {
+		t.Fatalf("expected 1 reported error, got: %v", reported)
+	}
+	fail = false
+	if err := p.Apply(context.Background(), Battery{Percent: 10}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := p.Apply(context.Background(), Battery{Percent: 10}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// a failed restore leaves the rule active
+	fail = true
+	if err := p.Apply(context.Background(), Battery{Percent: 50}); err == nil {
+		t.Fatal("expected error")
+	}
+	fail = false
+	if err := p.Apply(context.Background(), Battery{Percent: 50}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := p.Apply(context.Background(), Battery{Percent: 50}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if len(performed) != 2 || performed[0] != "then" || performed[1] != "restore" {
+		t.Errorf("expected then and restore performed once, got: %v", performed)
+	}
+}
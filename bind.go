@@ -0,0 +1,75 @@
+package hilink
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// LocalAddr is an option that binds connections to the Hilink device to the
+// local (source) IP address addr.
+//
+// Note that as all Hilink devices default to 192.168.8.1, when multiple
+// devices are attached to the host, the Interface option should be preferred,
+// as the source address alone does not determine the outgoing interface on
+// most operating systems.
+func LocalAddr(addr string) Option {
+	return func(c *Client) error {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return fmt.Errorf("%w %q", ErrInvalidIPAddress, addr)
+		}
+		return transportOption(func(t *http.Transport) {
+			d := newDialer()
+			d.LocalAddr = &net.TCPAddr{IP: ip}
+			t.DialContext = d.DialContext
+		})(c)
+	}
+}
+
+// Interface is an option that binds connections to the Hilink device to the
+// named network interface (ie, "usb0", "eth1"), allowing a single host to
+// manage multiple Hilink devices that share the same address.
+//
+// On Linux, the socket is bound to the interface with SO_BINDTODEVICE (which
+// may require CAP_NET_RAW on older kernels). On other platforms, connections
+// are bound to the first IPv4 address of the interface.
+func Interface(name string) Option {
+	return func(c *Client) error {
+		d, err := interfaceDialer(name)
+		if err != nil {
+			return err
+		}
+		return transportOption(func(t *http.Transport) {
+			t.DialContext = d.DialContext
+		})(c)
+	}
+}
+
+// newDialer creates a net.Dialer with the same settings as used by
+// http.DefaultTransport.
+func newDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+}
+
+// interfaceIPv4 returns the first IPv4 address of the named interface.
+func interfaceIPv4(name string) (net.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs {
+		if n, ok := a.(*net.IPNet); ok && n.IP.To4() != nil {
+			return n.IP, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %s", ErrNoInterfaceAddress, name)
+}
@@ -0,0 +1,30 @@
+//go:build linux
+// +build linux
+
+package hilink
+
+import (
+	"net"
+	"syscall"
+)
+
+// interfaceDialer creates a net.Dialer that binds its sockets to the named
+// network interface.
+func interfaceDialer(name string) (*net.Dialer, error) {
+	if _, err := net.InterfaceByName(name); err != nil {
+		return nil, err
+	}
+
+	d := newDialer()
+	d.Control = func(network, address string, rc syscall.RawConn) error {
+		var err error
+		if cerr := rc.Control(func(fd uintptr) {
+			err = syscall.BindToDevice(int(fd), name)
+		}); cerr != nil {
+			return cerr
+		}
+		return err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,22 @@
+//go:build !linux
+// +build !linux
+
+package hilink
+
+import (
+	"net"
+)
+
+// interfaceDialer creates a net.Dialer that binds its sockets to the first
+// IPv4 address of the named network interface.
+func interfaceDialer(name string) (*net.Dialer, error) {
+	ip, err := interfaceIPv4(name)
+	if err != nil {
+		return nil, err
+	}
+
+	d := newDialer()
+	d.LocalAddr = &net.TCPAddr{IP: ip}
+
+	return d, nil
+}
@@ -0,0 +1,138 @@
+package hilink
+
+import (
+	"errors"
+	"strconv"
+)
+
+// CellInfo is the serving cell information, with the neighbour cells where
+// firmware reports them.
+type CellInfo struct {
+	// Mode is the radio access mode (ie, 7 for LTE).
+	Mode int
+
+	// PLMN is the network PLMN (MCC and MNC), where reported.
+	PLMN string
+
+	// CellID is the cell identity (ECI on LTE), as reported.
+	CellID string
+
+	// ENodeBID and Sector are the eNodeB ID and sector (local cell ID)
+	// parts of LTE cell identities. Zero when the cell identity is not
+	// decimal.
+	ENodeBID int
+	Sector   int
+
+	// PCI is the physical cell ID.
+	PCI int
+
+	// EARFCN is the channel number, as reported (ie, "DL:1300 UL:19300").
+	EARFCN string
+
+	// Band is the LTE band.
+	Band int
+
+	// TAC is the tracking area code (LAC on GSM and UMTS), as reported.
+	TAC string
+
+	// DLBandwidth and ULBandwidth are the channel bandwidths, in MHz.
+	DLBandwidth float64
+	ULBandwidth float64
+
+	// RSRP, RSRQ and SINR are the serving cell signal values.
+	RSRP float64
+	RSRQ float64
+	SINR float64
+
+	// Neighbours are the neighbour cells, where firmware reports them.
+	Neighbours []NeighbourCell
+}
+
+// NeighbourCell is a neighbour cell.
+type NeighbourCell struct {
+	PCI    int
+	EARFCN string
+	RSRP   float64
+	RSRQ   float64
+}
+
+// cellXML is the raw serving cell information of the signal endpoint.
+type cellXML struct {
+	Mode        string `xml:"mode"`
+	PLMN        string `xml:"plmn"`
+	CellID      string `xml:"cell_id"`
+	PCI         string `xml:"pci"`
+	EARFCN      string `xml:"earfcn"`
+	Band        string `xml:"band"`
+	TAC         string `xml:"tac"`
+	LAC         string `xml:"lac"`
+	ENodeBID    string `xml:"enodeb_id"`
+	DLBandwidth string `xml:"dlbandwidth"`
+	ULBandwidth string `xml:"ulbandwidth"`
+	RSRP        string `xml:"rsrp"`
+	RSRQ        string `xml:"rsrq"`
+	SINR        string `xml:"sinr"`
+}
+
+// neighbourCellXML is the raw neighbour cell entry.
+type neighbourCellXML struct {
+	PCI    string `xml:"pci"`
+	EARFCN string `xml:"earfcn"`
+	RSRP   string `xml:"rsrp"`
+	RSRQ   string `xml:"rsrq"`
+}
+
+// CellInfo retrieves the serving cell information from the extended signal
+// values, and the neighbour cells from api/net/cell-info where available.
+func (c *Client) CellInfo() (*CellInfo, error) {
+	var x cellXML
+	if err := c.doReqXML("api/device/signal", nil, &x); err != nil {
+		return nil, err
+	}
+
+	ci := &CellInfo{
+		Mode:        parseInt(x.Mode),
+		PLMN:        x.PLMN,
+		CellID:      x.CellID,
+		PCI:         parseInt(x.PCI),
+		EARFCN:      x.EARFCN,
+		Band:        parseInt(x.Band),
+		TAC:         x.TAC,
+		DLBandwidth: parseSignalValue(x.DLBandwidth),
+		ULBandwidth: parseSignalValue(x.ULBandwidth),
+		RSRP:        parseSignalValue(x.RSRP),
+		RSRQ:        parseSignalValue(x.RSRQ),
+		SINR:        parseSignalValue(x.SINR),
+	}
+	if ci.TAC == "" {
+		ci.TAC = x.LAC
+	}
+	if eci, err := strconv.ParseUint(x.CellID, 10, 32); err == nil {
+		ci.ENodeBID, ci.Sector = int(eci>>8), int(eci&0xff)
+	}
+	if id := parseInt(x.ENodeBID); id != 0 {
+		ci.ENodeBID = id
+	}
+
+	// neighbour cells, missing on most firmware
+	var res struct {
+		Cells []neighbourCellXML `xml:"NeighbourCells>NeighbourCell"`
+	}
+	err := c.doReqXML("api/net/cell-info", nil, &res)
+	if _, ok := ErrorCode(err); ok || errors.Is(err, ErrBrokenEndpoint) {
+		return ci, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range res.Cells {
+		ci.Neighbours = append(ci.Neighbours, NeighbourCell{
+			PCI:    parseInt(n.PCI),
+			EARFCN: n.EARFCN,
+			RSRP:   parseSignalValue(n.RSRP),
+			RSRQ:   parseSignalValue(n.RSRQ),
+		})
+	}
+
+	return ci, nil
+}
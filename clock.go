@@ -0,0 +1,205 @@
+package hilink
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeLayout is the layout of the date/time values used by the WebUI (ie,
+// the SMS and device clock dates).
+const TimeLayout = "2006-01-02 15:04:05"
+
+// DeviceTime retrieves the current date/time of the device clock.
+//
+// As the device reports its local time without a time zone, the time is
+// interpreted in the device's time zone (see ParseTime).
+func (c *Client) DeviceTime() (time.Time, error) {
+	s, err := c.doReqString("api/sntp/timeinfo", nil, "currentlocaltime")
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return c.ParseTime(s)
+}
+
+// DeviceTimeSet sets the date/time of the device clock. This is distinct
+// from the NTP configuration, and is useful for devices that cannot reach an
+// NTP server.
+func (c *Client) DeviceTimeSet(t time.Time) (bool, error) {
+	return c.doReqCheckOK("api/sntp/timeinfo", SimpleRequestXML(
+		"currentlocaltime", t.In(c.location()).Format(TimeLayout),
+	))
+}
+
+// DeviceTimeSync sets the date/time of the device clock to the host's current
+// time.
+func (c *Client) DeviceTimeSync() (bool, error) {
+	return c.DeviceTimeSet(time.Now())
+}
+
+// TimeZone is the device time zone setting.
+type TimeZone struct {
+	// Offset is the offset from UTC, excluding daylight saving time.
+	Offset time.Duration
+
+	// DST is the daylight saving time flag, adding an hour to the offset.
+	DST bool
+}
+
+// String satisfies the fmt.Stringer interface, returning the offset in the
+// form used by the WebUI (ie, UTC+08:00).
+func (z TimeZone) String() string {
+	sign, off := '+', z.Offset
+	if off < 0 {
+		sign, off = '-', -off
+	}
+	h, m := off/time.Hour, (off%time.Hour)/time.Minute
+	return fmt.Sprintf("UTC%c%02d:%02d", sign, h, m)
+}
+
+// Location returns the time.Location for the time zone, including daylight
+// saving time.
+func (z TimeZone) Location() *time.Location {
+	off := z.Offset
+	if z.DST {
+		off += time.Hour
+	}
+	return time.FixedZone(z.String(), int(off/time.Second))
+}
+
+// parseTimeZone parses a time zone offset in the forms used by the WebUI (ie,
+// "UTC+08:00", "GMT-5", "+0530").
+func parseTimeZone(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "UTC"), "GMT")
+	if s == "" {
+		return 0, nil
+	}
+
+	neg := s[0] == '-'
+	if s[0] == '+' || s[0] == '-' {
+		s = s[1:]
+	}
+
+	var h, m int
+	var err error
+	switch i := strings.IndexByte(s, ':'); {
+	case i != -1:
+		h, err = strconv.Atoi(s[:i])
+		if err == nil {
+			m, err = strconv.Atoi(s[i+1:])
+		}
+	case len(s) == 4:
+		h, err = strconv.Atoi(s[:2])
+		if err == nil {
+			m, err = strconv.Atoi(s[2:])
+		}
+	default:
+		h, err = strconv.Atoi(s)
+	}
+	if err != nil || h > 14 || m > 59 {
+		return 0, ErrInvalidValue
+	}
+
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// TimeZone retrieves the device time zone setting.
+func (c *Client) TimeZone() (*TimeZone, error) {
+	var x struct {
+		TimeZone string `xml:"timezone"`
+		DST      string `xml:"dst"`
+	}
+	if err := c.doReqXML("api/sntp/timezone", nil, &x); err != nil {
+		return nil, err
+	}
+
+	off, err := parseTimeZone(x.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	z := &TimeZone{Offset: off, DST: x.DST == "1"}
+	c.setLocation(z.Location())
+
+	return z, nil
+}
+
+// TimeZoneSet sets the device time zone setting.
+func (c *Client) TimeZoneSet(z TimeZone) (bool, error) {
+	ok, err := c.doReqCheckOK("api/sntp/timezone", SimpleRequestXML(
+		"timezone", z.String(),
+		"dst", boolToString(z.DST),
+	))
+	if ok {
+		c.setLocation(z.Location())
+	}
+	return ok, err
+}
+
+// ParseTime parses a date/time value reported by the device (ie, SMS and log
+// dates) in the device's time zone.
+//
+// The time zone is retrieved from the device on first use (see TimeZone),
+// unless set with the Location option. The host's local time zone is used
+// when the device does not report its time zone.
+//
+// Besides TimeLayout, the variants reported by some firmwares are accepted
+// (see timeLayouts).
+func (c *Client) ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	loc := c.location()
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidValue
+}
+
+// timeLayouts are the date/time layouts accepted by ParseTime.
+var timeLayouts = []string{
+	TimeLayout,
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04",
+}
+
+// location returns the device's time zone location.
+func (c *Client) location() *time.Location {
+	c.Lock()
+	loc := c.loc
+	c.Unlock()
+	if loc != nil {
+		return loc
+	}
+
+	// the lookup is retried on the next use, unless the device reported an
+	// error (ie, the time zone is not supported) or an invalid time zone
+	z, err := c.TimeZone()
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr) || errors.Is(err, ErrInvalidValue):
+		c.setLocation(time.Local)
+		return time.Local
+	case err != nil:
+		return time.Local
+	}
+
+	return z.Location()
+}
+
+// setLocation sets the device's time zone location.
+func (c *Client) setLocation(loc *time.Location) {
+	c.Lock()
+	defer c.Unlock()
+	c.loc = loc
+}
@@ -0,0 +1,64 @@
+package hilink
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLocationRetried(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/sntp/timezone"] = `<response><timezone>UTC+08:00</timezone><dst>0</dst></response>`
+
+	// a failing lookup is retried on the next use
+	var fail int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) != 0 && r.URL.Path == "/api/sntp/timezone" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		d.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(URL(srv.URL + "/"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	atomic.StoreInt32(&fail, 1)
+	if loc := c.location(); loc != time.Local {
+		t.Errorf("expected local time zone, got: %v", loc)
+	}
+	atomic.StoreInt32(&fail, 0)
+	if _, off := time.Now().In(c.location()).Zone(); off != 8*3600 {
+		t.Errorf("expected device time zone, got offset: %d", off)
+	}
+	if n := d.count("/api/sntp/timezone"); n != 1 {
+		t.Errorf("expected 1 successful lookup, got: %d", n)
+	}
+
+	// the device time zone is cached
+	c.location()
+	if n := d.count("/api/sntp/timezone"); n != 1 {
+		t.Errorf("expected cached time zone, got: %d lookups", n)
+	}
+}
+
+func TestLocationDeviceError(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/sntp/timezone"] = `<error><code>100002</code><message></message></error>`
+
+	c := d.client(t)
+	for i := 0; i < 2; i++ {
+		if loc := c.location(); loc != time.Local {
+			t.Errorf("expected local time zone, got: %v", loc)
+		}
+	}
+	if n := d.count("/api/sntp/timezone"); n != 1 {
+		t.Errorf("expected 1 lookup, got: %d", n)
+	}
+}
@@ -0,0 +1,361 @@
+// Command hilinkctl is a command-line tool for common Hilink device tasks.
+//
+// Usage:
+//
+//	hilinkctl [flags] <command> [args]
+//
+// Commands:
+//
+//	status                    show the device status
+//	signal                    show the signal information
+//	sms list [inbox|outbox|draft]
+//	                          list SMS messages
+//	sms send <to> <message>   send an SMS message
+//	sms delete <index>...     delete SMS messages
+//	connect                   connect to the network provider
+//	disconnect                disconnect from the network provider
+//	reboot                    reboot the device
+//	ussd <code>               send a USSD code, and show the response
+//	profile                   list the connection profiles
+//	wifi                      show the WiFi settings
+//	wifi on|off               enable or disable the WiFi radio
+//	wifi ssid <ssid>          set the WiFi SSID
+//	wifi password <password>  set the WiFi password
+//	firmware                  show the current and latest firmware versions
+//	firmware check            check for a new firmware version
+//	firmware update           download and install the new firmware
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jpunie/hilink"
+)
+
+var (
+	flagEndpoint = flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	flagUser     = flag.String("u", "admin", "username")
+	flagPass     = flag.String("p", "", "password")
+	flagOutput   = flag.String("o", "table", "output format (table or json)")
+	flagTimeout  = flag.Duration("t", 0, "timeout of waiting commands (defaults to the timeout of each command, ie 30m for firmware update)")
+	flagDebug    = flag.Bool("v", false, "enable verbose")
+)
+
+// errUsage is the usage error.
+var errUsage = errors.New("invalid usage")
+
+// command is a subcommand.
+type command func(ctx context.Context, client *hilink.Client, args []string) error
+
+// commands are the subcommands.
+var commands = map[string]command{
+	"status":     status,
+	"signal":     signal,
+	"sms":        sms,
+	"connect":    connect,
+	"disconnect": disconnect,
+	"reboot":     reboot,
+	"ussd":       ussd,
+	"profile":    profile,
+	"wifi":       wifi,
+	"firmware":   firmware,
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] <command> [args]\n\ncommands:\n", os.Args[0])
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(os.Stderr, "  %s\n\nflags:\n", strings.Join(names, ", "))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// options
+	opts := []hilink.Option{
+		hilink.URL(*flagEndpoint),
+	}
+	if *flagPass != "" {
+		opts = append(opts, hilink.Auth(*flagUser, *flagPass))
+	}
+	if *flagDebug {
+		opts = append(opts, hilink.Log(log.Printf, log.Printf))
+	}
+
+	// create client
+	client, err := hilink.NewClient(opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	// waiting commands have their own default timeouts
+	ctx, cancel := context.WithCancel(context.Background())
+	if *flagTimeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, *flagTimeout)
+	}
+	defer cancel()
+
+	if err := cmd(ctx, client, flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// status shows the device status.
+func status(ctx context.Context, client *hilink.Client, args []string) error {
+	s, err := client.Status()
+	if err != nil {
+		return err
+	}
+	return output(s, nil, fields(s))
+}
+
+// signal shows the signal information.
+func signal(ctx context.Context, client *hilink.Client, args []string) error {
+	s, err := client.Signal()
+	if err != nil {
+		return err
+	}
+	return output(s, nil, fields(s))
+}
+
+// sms lists, sends or deletes SMS messages.
+func sms(ctx context.Context, client *hilink.Client, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: sms list|send|delete", errUsage)
+	}
+
+	switch args[0] {
+	case "list":
+		box := hilink.SmsBoxTypeInbox
+		if len(args) > 1 {
+			switch args[1] {
+			case "inbox":
+			case "outbox":
+				box = hilink.SmsBoxTypeOutbox
+			case "draft":
+				box = hilink.SmsBoxTypeDraft
+			default:
+				return fmt.Errorf("%w: unknown box %q", errUsage, args[1])
+			}
+		}
+		msgs, err := client.SmsMessages(hilink.SmsListOptions{BoxType: box, Count: 50})
+		if err != nil {
+			return err
+		}
+		var rows [][]string
+		for _, m := range msgs {
+			rows = append(rows, []string{strconv.Itoa(m.Index), m.Phone, m.Date.Format(hilink.TimeLayout), m.Content})
+		}
+		return output(msgs, []string{"INDEX", "PHONE", "DATE", "CONTENT"}, rows)
+
+	case "send":
+		if len(args) < 3 {
+			return fmt.Errorf("%w: sms send <to> <message>", errUsage)
+		}
+		res, err := client.SmsSendAndConfirm(ctx, strings.Join(args[2:], " "), args[1])
+		if err != nil {
+			return err
+		}
+		if !res.OK() {
+			return fmt.Errorf("message not sent to %s", strings.Join(append(res.Failed, res.Pending...), ", "))
+		}
+		return nil
+
+	case "delete":
+		if len(args) < 2 {
+			return fmt.Errorf("%w: sms delete <index>...", errUsage)
+		}
+		return checkOK(client.SmsDelete(args[1:]...))
+	}
+	return fmt.Errorf("%w: unknown sms command %q", errUsage, args[0])
+}
+
+// connect connects to the network provider.
+func connect(ctx context.Context, client *hilink.Client, args []string) error {
+	s, err := client.ConnectAndWait(ctx)
+	if err != nil {
+		return err
+	}
+	return output(map[string]string{"status": s.String()}, nil, [][]string{{"status", s.String()}})
+}
+
+// disconnect disconnects from the network provider.
+func disconnect(ctx context.Context, client *hilink.Client, args []string) error {
+	s, err := client.DisconnectAndWait(ctx)
+	if err != nil {
+		return err
+	}
+	return output(map[string]string{"status": s.String()}, nil, [][]string{{"status", s.String()}})
+}
+
+// reboot reboots the device.
+func reboot(ctx context.Context, client *hilink.Client, args []string) error {
+	return checkOK(client.DeviceReboot())
+}
+
+// ussd sends a USSD code, and shows the response.
+func ussd(ctx context.Context, client *hilink.Client, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: ussd <code>", errUsage)
+	}
+	content, err := client.UssdSendAndWait(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return output(map[string]string{"content": content}, nil, [][]string{{content}})
+}
+
+// profile lists the connection profiles.
+func profile(ctx context.Context, client *hilink.Client, args []string) error {
+	profiles, def, err := client.Profiles()
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, p := range profiles {
+		mark := ""
+		if p.Index == def {
+			mark = "*"
+		}
+		rows = append(rows, []string{strconv.Itoa(p.Index), mark, p.Name, p.APN, p.Username, p.IPType.String()})
+	}
+	v := map[string]interface{}{"default": def, "profiles": profiles}
+	return output(v, []string{"INDEX", "DEFAULT", "NAME", "APN", "USERNAME", "IP TYPE"}, rows)
+}
+
+// wifi shows or changes the WiFi settings.
+func wifi(ctx context.Context, client *hilink.Client, args []string) error {
+	if len(args) == 0 {
+		s, err := client.WlanBasicSettings()
+		if err != nil {
+			return err
+		}
+		return output(s, nil, fields(s))
+	}
+
+	switch {
+	case args[0] == "on" || args[0] == "off":
+		return checkOK(client.WifiEnabledSet(args[0] == "on"))
+
+	case args[0] == "ssid" && len(args) == 2:
+		s, err := client.WlanBasicSettings()
+		if err != nil {
+			return err
+		}
+		s.SSID = args[1]
+		return checkOK(client.WlanBasicSettingsSet(*s))
+
+	case args[0] == "password" && len(args) == 2:
+		return checkOK(client.WifiPasswordSet(args[1]))
+	}
+	return fmt.Errorf("%w: wifi [on|off|ssid <ssid>|password <password>]", errUsage)
+}
+
+// firmware shows the firmware versions, checks for a new firmware version, or
+// updates the firmware.
+func firmware(ctx context.Context, client *hilink.Client, args []string) error {
+	switch {
+	case len(args) == 0:
+		v, err := client.FirmwareVersion()
+		if err != nil {
+			return err
+		}
+		return output(v, nil, fields(v))
+
+	case args[0] == "check" && len(args) == 1:
+		u, err := client.FirmwareUpdateCheckAndWait(ctx)
+		if err != nil {
+			return err
+		}
+		return output(u, nil, fields(u))
+
+	case args[0] == "update" && len(args) == 1:
+		_, err := client.FirmwareUpdateAndWait(ctx, func(u hilink.FirmwareUpdate) {
+			fmt.Fprintf(os.Stderr, "%s %d%%\n", u.State(), u.Progress)
+		})
+		return err
+	}
+	return fmt.Errorf("%w: firmware [check|update]", errUsage)
+}
+
+// checkOK converts a false result to an error.
+func checkOK(ok bool, err error) error {
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		return errors.New("device did not respond with OK")
+	}
+	return nil
+}
+
+// output writes v in the selected output format: as JSON, or as a table of
+// the rows.
+func output(v interface{}, header []string, rows [][]string) error {
+	return write(os.Stdout, *flagOutput, v, header, rows)
+}
+
+// write writes v to w in the output format.
+func write(w io.Writer, format string, v interface{}, header []string, rows [][]string) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		if header != nil {
+			fmt.Fprintln(tw, strings.Join(header, "\t"))
+		}
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("%w: unknown output format %q", errUsage, format)
+}
+
+// fields returns the name and value rows of the exported fields of the
+// struct pointed to by v.
+func fields(v interface{}) [][]string {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+
+	var rows [][]string
+	for i := 0; i < rt.NumField(); i++ {
+		if rt.Field(i).PkgPath != "" {
+			continue
+		}
+		rows = append(rows, []string{rt.Field(i).Name, fmt.Sprint(rv.Field(i).Interface())})
+	}
+	return rows
+}
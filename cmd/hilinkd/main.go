@@ -0,0 +1,335 @@
+// Command hilinkd serves a JSON REST API for a Hilink device, managing the
+// device session and CSRF tokens internally.
+//
+// Endpoints:
+//
+//	GET    /status          device status
+//	GET    /signal          signal information
+//	GET    /cell            serving and neighbour cells
+//	GET    /device          device information
+//	GET    /traffic         traffic statistics
+//	GET    /notifications   notifications
+//	GET    /hosts           connected hosts
+//	GET    /sms             SMS messages (?box=inbox|outbox|draft)
+//	POST   /sms             send an SMS message ({"to": "...", "message": "..."})
+//	DELETE /sms/{index}     delete an SMS message
+//	POST   /connect         connect to the network provider
+//	POST   /disconnect      disconnect from the network provider
+//	POST   /reboot          reboot the device
+//	POST   /ussd            send a USSD code ({"code": "..."}), returning the response
+//	GET    /wifi            WiFi settings
+//
+// Requests are authorized with the bearer token given with -token, when set,
+// and are limited to -rate requests per second. The API listens on the
+// loopback interface by default; listening on other interfaces requires
+// -token.
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jpunie/hilink"
+)
+
+var (
+	flagEndpoint = flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	flagUser     = flag.String("u", "admin", "username")
+	flagPass     = flag.String("p", "", "password")
+	flagListen   = flag.String("l", "127.0.0.1:8080", "listen address (non-loopback requires -token)")
+	flagToken    = flag.String("token", "", "bearer token required for requests")
+	flagRate     = flag.Float64("rate", 5, "maximum requests per second (0 is unlimited)")
+	flagDebug    = flag.Bool("v", false, "enable verbose")
+)
+
+// errBadRequest is the bad request error.
+var errBadRequest = errors.New("bad request")
+
+// handler is an API handler, returning the response value.
+type handler func(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error)
+
+func main() {
+	flag.Parse()
+
+	// the API controls the device, refuse to expose it without a token
+	if *flagToken == "" && !loopback(*flagListen) {
+		fmt.Fprintf(os.Stderr, "error: listening on %s requires -token\n", *flagListen)
+		os.Exit(2)
+	}
+
+	// options
+	opts := []hilink.Option{
+		hilink.URL(*flagEndpoint),
+		hilink.LazySession,
+	}
+	if *flagPass != "" {
+		opts = append(opts, hilink.Auth(*flagUser, *flagPass))
+	}
+	if *flagDebug {
+		opts = append(opts, hilink.Log(log.Printf, log.Printf))
+	}
+
+	// create client
+	client, err := hilink.NewClient(opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	s := &server{client: client, limit: newLimiter(*flagRate)}
+
+	r := mux.NewRouter()
+	r.Handle("/status", s.handle(status)).Methods("GET")
+	r.Handle("/signal", s.handle(signal)).Methods("GET")
+	r.Handle("/cell", s.handle(cell)).Methods("GET")
+	r.Handle("/device", s.handle(device)).Methods("GET")
+	r.Handle("/traffic", s.handle(traffic)).Methods("GET")
+	r.Handle("/notifications", s.handle(notifications)).Methods("GET")
+	r.Handle("/hosts", s.handle(hosts)).Methods("GET")
+	r.Handle("/sms", s.handle(smsList)).Methods("GET")
+	r.Handle("/sms", s.handle(smsSend)).Methods("POST")
+	r.Handle("/sms/{index:[0-9]+}", s.handle(smsDelete)).Methods("DELETE")
+	r.Handle("/connect", s.handle(connect)).Methods("POST")
+	r.Handle("/disconnect", s.handle(disconnect)).Methods("POST")
+	r.Handle("/reboot", s.handle(reboot)).Methods("POST")
+	r.Handle("/ussd", s.handle(ussd)).Methods("POST")
+	r.Handle("/wifi", s.handle(wifi)).Methods("GET")
+
+	log.Fatal(http.ListenAndServe(*flagListen, r))
+}
+
+// loopback determines if the listen address is on the loopback interface.
+func loopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// server is the API server.
+type server struct {
+	client *hilink.Client
+	limit  *limiter
+}
+
+// handle wraps an API handler with authorization, rate limiting and JSON
+// encoding.
+func (s *server) handle(h handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// authorize
+		if *flagToken != "" {
+			tok := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(tok), []byte(*flagToken)) != 1 {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+		}
+
+		// limit
+		if !s.limit.allow() {
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "too many requests"})
+			return
+		}
+
+		v, err := h(r.Context(), s.client, r)
+		switch {
+		case errors.Is(err, errBadRequest):
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case err != nil:
+			writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		case v == nil:
+			writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+		default:
+			writeJSON(w, http.StatusOK, v)
+		}
+	})
+}
+
+// writeJSON writes v as the JSON response.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// decode decodes the JSON request body.
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", errBadRequest, err)
+	}
+	return nil
+}
+
+// checkOK converts a false result to an error.
+func checkOK(ok bool, err error) error {
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		return errors.New("device did not respond with OK")
+	}
+	return nil
+}
+
+func status(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return client.Status()
+}
+
+func signal(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return client.Signal()
+}
+
+func cell(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return client.CellInfo()
+}
+
+func device(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return client.DeviceInformation()
+}
+
+func traffic(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return client.TrafficInfo()
+}
+
+func notifications(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return client.Notifications()
+}
+
+func hosts(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return client.HostList()
+}
+
+func smsList(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	box := hilink.SmsBoxTypeInbox
+	switch r.URL.Query().Get("box") {
+	case "", "inbox":
+	case "outbox":
+		box = hilink.SmsBoxTypeOutbox
+	case "draft":
+		box = hilink.SmsBoxTypeDraft
+	default:
+		return nil, fmt.Errorf("%w: unknown box", errBadRequest)
+	}
+	return client.SmsMessages(hilink.SmsListOptions{BoxType: box, Count: 50})
+}
+
+func smsSend(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	var req struct {
+		To      string `json:"to"`
+		Message string `json:"message"`
+	}
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if req.To == "" || req.Message == "" {
+		return nil, fmt.Errorf("%w: to and message are required", errBadRequest)
+	}
+	return nil, checkOK(client.SmsSend(req.Message, req.To))
+}
+
+func smsDelete(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return nil, checkOK(client.SmsDelete(mux.Vars(r)["index"]))
+}
+
+func connect(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	s, err := client.ConnectAndWait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"status": s.String()}, nil
+}
+
+func disconnect(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	s, err := client.DisconnectAndWait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"status": s.String()}, nil
+}
+
+func reboot(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return nil, checkOK(client.DeviceReboot())
+}
+
+func ussd(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if req.Code == "" {
+		return nil, fmt.Errorf("%w: code is required", errBadRequest)
+	}
+	content, err := client.UssdSendAndWait(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"content": content}, nil
+}
+
+func wifi(ctx context.Context, client *hilink.Client, r *http.Request) (interface{}, error) {
+	return client.WlanBasicSettings()
+}
+
+// limiter is a token bucket rate limiter.
+type limiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	sync.Mutex
+}
+
+// newLimiter creates a limiter allowing rate requests per second, with
+// bursts of the same size (at least 1). A zero rate is unlimited.
+func newLimiter(rate float64) *limiter {
+	burst := rate
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// allow determines if a request is allowed.
+func (l *limiter) allow() bool {
+	if l.rate <= 0 {
+		return true
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
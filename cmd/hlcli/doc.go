@@ -3,183 +3,479 @@ package main
 // Code generated by gen.go. DO NOT EDIT.
 
 var methodParamMap = map[string][]string{
-	"NewSessionAndTokenID": {},
-	"SetSessionAndTokenID": {"sessionID", "tokenID"},
-	"GlobalConfig":         {},
-	"NetworkTypes":         {},
-	"PCAssistantConfig":    {},
-	"DeviceConfig":         {},
-	"WebUIConfig":          {},
-	"SmsConfig":            {},
-	"WlanConfig":           {},
-	"DhcpConfig":           {},
-	"CradleStatusInfo":     {},
-	"CradleMACSet":         {"addr"},
-	"CradleMAC":            {},
-	"AutorunVersion":       {},
-	"DeviceBasicInfo":      {},
-	"PublicKey":            {},
-	"DeviceControl":        {"code"},
-	"DeviceReboot":         {},
-	"DeviceReset":          {},
-	"DeviceBackup":         {},
-	"DeviceShutdown":       {},
-	"DeviceFeatures":       {},
-	"DeviceInfo":           {},
-	"DeviceModeSet":        {"mode"},
-	"FastbootFeatures":     {},
-	"PowerFeatures":        {},
-	"TetheringFeatures":    {},
-	"SignalInfo":           {},
-	"ConnectionInfo":       {},
-	"ConnectionProfile":    {"roaming", "maxIdleTime"},
-	"GlobalFeatures":       {},
-	"Language":             {},
-	"LanguageSet":          {"lang"},
-	"NotificationInfo":     {},
-	"SimInfo":              {},
-	"StatusInfo":           {},
-	"TrafficInfo":          {},
-	"TrafficClear":         {},
-	"MonthInfo":            {},
-	"WlanMonthInfo":        {},
-	"NetworkInfo":          {},
-	"WifiFeatures":         {},
-	"ModeList":             {},
-	"ModeInfo":             {},
-	"ModeNetworkInfo":      {},
-	"ModeSet":              {"netMode", "netBand", "lteBand"},
-	"PinInfo":              {},
-	"PinEnter":             {"pin"},
-	"PinActivate":          {"pin"},
-	"PinDeactivate":        {"pin"},
-	"PinChange":            {"pin", "new"},
-	"PinEnterPuk":          {"puk", "new"},
-	"PinSaveInfo":          {},
-	"PinSimlockInfo":       {},
-	"Connect":              {},
-	"Disconnect":           {},
-	"ProfileInfo":          {},
-	"ProfileAdd":           {"name", "apn", "user", "password", "isDefault"},
-	"ProfileDelete":        {"index", "newDefault"},
-	"SmsFeatures":          {},
-	"SmsList":              {"boxType", "page", "count", "sortByName", "ascending", "unreadPreferred"},
-	"SmsCount":             {},
-	"SmsSend":              {"msg", "to"},
-	"SmsSendStatus":        {},
-	"SmsReadSet":           {"id"},
-	"SmsDelete":            {"id"},
-	"UssdStatus":           {},
-	"UssdCode":             {"code"},
-	"UssdContent":          {},
-	"UssdRelease":          {},
-	"DdnsList":             {},
-	"LogPath":              {},
-	"LogInfo":              {},
-	"PhonebookGroupList":   {"page", "count", "sortByName", "ascending"},
-	"PhonebookCount":       {},
-	"PhonebookImport":      {"group"},
-	"PhonebookDelete":      {"id"},
-	"PhonebookList":        {"group", "page", "count", "sim", "sortByName", "ascending", "keyword"},
-	"PhonebookCreate":      {"group", "name", "phone", "sim"},
-	"FirewallFeatures":     {},
-	"DmzConfig":            {},
-	"DmzConfigSet":         {"enabled", "dmzIPAddress"},
-	"SipAlg":               {},
-	"SipAlgSet":            {"port", "enabled"},
-	"NatType":              {},
-	"NatTypeSet":           {"ntype"},
-	"Upnp":                 {},
-	"UpnpSet":              {"enabled"},
+	"Plan":                       {"desired"},
+	"Apply":                      {"p"},
+	"ATCommand":                  {"cmd"},
+	"DoCheckOK":                  {"path", "v"},
+	"ModeLTEBands":               {},
+	"ModeSetLTEBands":            {"bands"},
+	"Battery":                    {},
+	"CellInfo":                   {},
+	"DeviceTime":                 {},
+	"DeviceTimeSet":              {"t"},
+	"DeviceTimeSync":             {},
+	"TimeZone":                   {},
+	"TimeZoneSet":                {"z"},
+	"ParseTime":                  {"s"},
+	"ConnectAndWait":             {"ctx"},
+	"DisconnectAndWait":          {"ctx"},
+	"DataPlan":                   {},
+	"DataPlanSet":                {"p"},
+	"DeviceInformation":          {},
+	"SmsCounts":                  {},
+	"DhcpSettings":               {},
+	"DhcpConfigSet":              {"s"},
+	"StaticLeases":               {},
+	"StaticLeaseAdd":             {"l"},
+	"StaticLeaseRemove":          {"mac"},
+	"DialupConnectionGet":        {},
+	"DialupConnectionSet":        {"d"},
+	"ConnectModeSet":             {"m"},
+	"MTUSet":                     {"mtu"},
+	"MaxIdleTimeSet":             {"d"},
+	"CradleStatus":               {},
+	"FetchAll":                   {"ctx", "endpoints"},
+	"PasswordChange":             {"cur", "new"},
+	"NewSessionAndTokenID":       {},
+	"SetSessionAndTokenID":       {"sessionID", "tokenID"},
+	"SessionAndTokenID":          {},
+	"GlobalConfig":               {},
+	"NetworkTypes":               {},
+	"PCAssistantConfig":          {},
+	"DeviceConfig":               {},
+	"WebUIConfig":                {},
+	"SmsConfig":                  {},
+	"WlanConfig":                 {},
+	"DhcpConfig":                 {},
+	"CradleStatusInfo":           {},
+	"CradleMACSet":               {"addr"},
+	"CradleMAC":                  {},
+	"AutorunVersion":             {},
+	"DeviceBasicInfo":            {},
+	"PublicKey":                  {},
+	"DeviceControl":              {"code"},
+	"DeviceReboot":               {},
+	"DeviceReset":                {},
+	"DeviceBackup":               {},
+	"DeviceBackupData":           {},
+	"DeviceRestore":              {"data"},
+	"DeviceShutdown":             {},
+	"DeviceFeatures":             {},
+	"DeviceInfo":                 {},
+	"DeviceModeSet":              {"mode"},
+	"FastbootFeatures":           {},
+	"PowerFeatures":              {},
+	"TetheringFeatures":          {},
+	"SignalInfo":                 {},
+	"ConnectionInfo":             {},
+	"ConnectionProfile":          {"roaming", "maxIdleTime"},
+	"Roaming":                    {},
+	"RoamingSet":                 {"enabled"},
+	"RoamingEnable":              {},
+	"RoamingDisable":             {},
+	"GlobalFeatures":             {},
+	"Language":                   {},
+	"LanguageSet":                {"lang"},
+	"NotificationInfo":           {},
+	"SimInfo":                    {},
+	"StatusInfo":                 {},
+	"TrafficInfo":                {},
+	"TrafficClear":               {},
+	"MonthInfo":                  {},
+	"WlanMonthInfo":              {},
+	"NetworkInfo":                {},
+	"WifiFeatures":               {},
+	"ModeList":                   {},
+	"ModeInfo":                   {},
+	"ModeNetworkInfo":            {},
+	"ModeSet":                    {"netMode", "netBand", "lteBand"},
+	"ModeSetNR":                  {"netMode", "netBand", "lteBand", "nrBand"},
+	"NRModeInfo":                 {},
+	"NRModeSet":                  {"mode"},
+	"PinInfo":                    {},
+	"PinEnter":                   {"pin"},
+	"PinEnterForce":              {"pin"},
+	"PinActivate":                {"pin"},
+	"PinDeactivate":              {"pin"},
+	"PinChange":                  {"pin", "new"},
+	"PinEnterPuk":                {"puk", "new"},
+	"PinSaveInfo":                {},
+	"PinSimlockInfo":             {},
+	"MobileDataSwitch":           {},
+	"MobileDataEnabled":          {},
+	"MobileDataSet":              {"enabled"},
+	"MobileDataSwitchState":      {"state"},
+	"MobileDataActivate":         {},
+	"MobileDataDeactivate":       {},
+	"DialupFeatures":             {},
+	"DialupControlAllowed":       {},
+	"Connect":                    {},
+	"Disconnect":                 {},
+	"ProfileInfo":                {},
+	"Profiles":                   {},
+	"ProfileAdd":                 {"p", "setDefault"},
+	"ProfileModify":              {"index", "p"},
+	"ProfileSetDefault":          {"index"},
+	"ProfileDelete":              {"index", "newDefault"},
+	"SmsFeatures":                {},
+	"SmsList":                    {"opts"},
+	"SmsListMarkRead":            {"opts"},
+	"SmsCount":                   {},
+	"SmsSend":                    {"msg", "to"},
+	"SmsSendParts":               {"msg", "to"},
+	"SmsSendStatus":              {},
+	"SmsReadSet":                 {"ids"},
+	"SmsDelete":                  {"ids"},
+	"UssdStatus":                 {},
+	"UssdCode":                   {"code"},
+	"UssdContent":                {},
+	"UssdContentRaw":             {},
+	"UssdRelease":                {},
+	"DdnsList":                   {},
+	"LogPath":                    {},
+	"LogInfo":                    {},
+	"PhonebookGroupList":         {"page", "count", "sortByName", "ascending"},
+	"PhonebookCount":             {},
+	"PhonebookImport":            {"group"},
+	"PhonebookDelete":            {"id"},
+	"PhonebookList":              {"group", "page", "count", "sim", "sortByName", "ascending", "keyword"},
+	"PhonebookCreate":            {"group", "name", "phone", "sim"},
+	"FirewallFeatures":           {},
+	"DmzConfig":                  {},
+	"DmzConfigSet":               {"enabled", "dmzIPAddress"},
+	"SipAlg":                     {},
+	"SipAlgSet":                  {"port", "enabled"},
+	"NatType":                    {},
+	"NatTypeSet":                 {"ntype"},
+	"Upnp":                       {},
+	"UpnpSet":                    {"enabled"},
+	"HostList":                   {},
+	"WifiStationList":            {},
+	"MACFilter":                  {},
+	"MACFilterSet":               {"mf"},
+	"MACFilterModeSet":           {"mode"},
+	"MACFilterAdd":               {"mac"},
+	"MACFilterReplace":           {"old", "mac"},
+	"MACFilterRemove":            {"mac"},
+	"WifiBlockMAC":               {"mac"},
+	"WifiUnblockMAC":             {"mac"},
+	"NetworkMode":                {},
+	"NetworkModeSet":             {"m"},
+	"ModeSetAuto":                {},
+	"ModeSetLTEOnly":             {},
+	"ModeSet3GOnly":              {},
+	"Notifications":              {},
+	"PinStatus":                  {},
+	"NetworkScan":                {},
+	"NetworkRegister":            {"plmn", "rat"},
+	"NetworkRegisterAuto":        {},
+	"ProbeEndpoints":             {"ctx"},
+	"RebootSchedule":             {},
+	"RebootScheduleSet":          {"s"},
+	"PortForwardResources":       {},
+	"StaticLeaseResources":       {},
+	"TimeRuleResources":          {},
+	"MACFilterResources":         {},
+	"ProfileResources":           {},
+	"Go":                         {"ctx", "w"},
+	"Close":                      {},
+	"ScreenShowPassword":         {},
+	"ScreenShowPasswordSet":      {"show"},
+	"ScreenShowSSID":             {},
+	"ScreenShowSSIDSet":          {"show"},
+	"ScreenTimeout":              {},
+	"ScreenTimeoutSet":           {"d"},
+	"RefreshSession":             {},
+	"Signal":                     {},
+	"SmsMessages":                {"opts"},
+	"SmsDeleteAll":               {"box"},
+	"SmsListAll":                 {"box"},
+	"SmsIter":                    {"opts"},
+	"SmsDraftSave":               {"msg", "to"},
+	"SmsDraftUpdate":             {"index", "msg", "to"},
+	"SmsDrafts":                  {},
+	"SmsDraftSend":               {"index"},
+	"SmsOutbox":                  {},
+	"SmsCopyFromSim":             {},
+	"SmsMoveFromSim":             {},
+	"SmsSimCapacity":             {},
+	"SmsLocalCapacity":           {},
+	"SmsSettings":                {},
+	"SmsConfigSet":               {"s"},
+	"SmsSendAndConfirm":          {"ctx", "msg", "to"},
+	"SettingsSnapshot":           {},
+	"Snapshot":                   {"ctx"},
+	"WriteSnapshot":              {"ctx", "w"},
+	"PDPInfo":                    {},
+	"Status":                     {},
+	"DoEach":                     {"path", "v", "el", "fn"},
+	"SmsEach":                    {"opts", "fn"},
+	"PhonebookEach":              {"group", "page", "count", "sim", "sortByName", "ascending", "keyword", "fn"},
+	"StatisticFeatures":          {},
+	"StatisticsEnabled":          {},
+	"StatisticsEnabledSet":       {"enabled"},
+	"MonthClear":                 {},
+	"WlanMonthClear":             {},
+	"MonthResetDay":              {},
+	"MonthResetDaySet":           {"day"},
+	"TrafficReport":              {},
+	"TrafficStats":               {},
+	"FirmwareUpdateCheck":        {},
+	"FirmwareUpdate":             {},
+	"FirmwareVersion":            {},
+	"FirmwareUpdateCheckAndWait": {"ctx"},
+	"FirmwareUpdateStart":        {},
+	"FirmwareUpdateCancel":       {},
+	"FirmwareUpdateAndWait":      {"ctx", "progress"},
+	"UssdSendAndWait":            {"ctx", "code"},
+	"WANIP":                      {},
+	"WlanHandover":               {},
+	"WlanHandoverSet":            {"h"},
+	"GuestQuota":                 {},
+	"GuestQuotaSet":              {"q"},
+	"WifiEnabledSet":             {"enabled"},
+	"WlanBasicSettings":          {},
+	"WlanBasicSettingsSet":       {"s"},
+	"WlanSecuritySettings":       {},
+	"WlanSecuritySettingsSet":    {"s"},
+	"WifiPasswordSet":            {"pw"},
 }
 
 var methodCommentMap = map[string]string{
-	"NewSessionAndTokenID": "NewSessionAndTokenID starts a session with the server, and returns the session and token.",
-	"SetSessionAndTokenID": "SetSessionAndTokenID sets the sessionID and tokenID for the Client.",
-	"GlobalConfig":         "GlobalConfig retrieves global Hilink configuration.",
-	"NetworkTypes":         "NetworkTypes retrieves available network types.",
-	"PCAssistantConfig":    "PCAssistantConfig retrieves PC Assistant configuration.",
-	"DeviceConfig":         "DeviceConfig retrieves device configuration.",
-	"WebUIConfig":          "WebUIConfig retrieves WebUI configuration.",
-	"SmsConfig":            "SmsConfig retrieves device SMS configuration.",
-	"WlanConfig":           "WlanConfig retrieves basic WLAN settings.",
-	"DhcpConfig":           "DhcpConfig retrieves DHCP configuration.",
-	"CradleStatusInfo":     "CradleStatusInfo retrieves cradle status information.",
-	"CradleMACSet":         "CradleMACSet sets the MAC address for the cradle.",
-	"CradleMAC":            "CradleMAC retrieves cradle MAC address.",
-	"AutorunVersion":       "AutorunVersion retrieves device autorun version.",
-	"DeviceBasicInfo":      "DeviceBasicInfo retrieves basic device information.",
-	"PublicKey":            "PublicKey retrieves webserver public key.",
-	"DeviceControl":        "DeviceControl sends a control code to the device.",
-	"DeviceReboot":         "DeviceReboot restarts the device.",
-	"DeviceReset":          "DeviceReset resets the device configuration.",
-	"DeviceBackup":         "DeviceBackup backups device configuration and retrieves backed up configuration data as a base64 encoded string.",
-	"DeviceShutdown":       "DeviceShutdown shuts down the device.",
-	"DeviceFeatures":       "DeviceFeatures retrieves device feature information.",
-	"DeviceInfo":           "DeviceInfo retrieves general device information.",
-	"DeviceModeSet":        "DeviceModeSet sets the device mode (0-project, 1-debug).",
-	"FastbootFeatures":     "FastbootFeatures retrieves fastboot feature information.",
-	"PowerFeatures":        "PowerFeatures retrieves power feature information.",
-	"TetheringFeatures":    "TetheringFeatures retrieves USB tethering feature information.",
-	"SignalInfo":           "SignalInfo retrieves network signal information.",
-	"ConnectionInfo":       "ConnectionInfo retrieves connection (dialup) information.",
-	"ConnectionProfile":    "ConnectionProfile set connection (dialup) information for roaming and max idle time.",
-	"GlobalFeatures":       "GlobalFeatures retrieves global feature information.",
-	"Language":             "Language retrieves current language.",
-	"LanguageSet":          "LanguageSet sets the language.",
-	"NotificationInfo":     "NotificationInfo retrieves notification information.",
-	"SimInfo":              "SimInfo retrieves SIM card information.",
-	"StatusInfo":           "StatusInfo retrieves general device status information.",
-	"TrafficInfo":          "TrafficInfo retrieves traffic statistic information.",
-	"TrafficClear":         "TrafficClear clears the current traffic statistics.",
-	"MonthInfo":            "MonthInfo retrieves the month download statistic information.",
-	"WlanMonthInfo":        "WlanMonthInfo retrieves the WLAN month download statistic information.",
-	"NetworkInfo":          "NetworkInfo retrieves network provider information.",
-	"WifiFeatures":         "WifiFeatures retrieves wifi feature information.",
-	"ModeList":             "ModeList retrieves available network modes.",
-	"ModeInfo":             "ModeInfo retrieves network mode settings information.",
-	"ModeNetworkInfo":      "ModeNetworkInfo retrieves current network mode information.",
-	"ModeSet":              "ModeSet sets the network mode.",
-	"PinInfo":              "PinInfo retrieves SIM PIN status information.",
-	"PinEnter":             "PinEnter enters a SIM PIN.",
-	"PinActivate":          "PinActivate activates a SIM PIN.",
-	"PinDeactivate":        "PinDeactivate deactivates a SIM PIN.",
-	"PinChange":            "PinChange changes a SIM PIN.",
-	"PinEnterPuk":          "PinEnterPuk enters a SIM PIN puk.",
-	"PinSaveInfo":          "PinSaveInfo retrieves SIM PIN save information.",
-	"PinSimlockInfo":       "PinSimlockInfo retrieves SIM lock information.",
-	"Connect":              "Connect connects the Hilink device to the network provider.",
-	"Disconnect":           "Disconnect disconnects the Hilink device from the network provider.",
-	"ProfileInfo":          "ProfileInfo retrieves profile information (ie, APN).",
-	"ProfileAdd":           "Add connection profile and set new default profile",
-	"ProfileDelete":        "Delete connection profile an set new default profile",
-	"SmsFeatures":          "SmsFeatures retrieves SMS feature information.",
-	"SmsList":              "SmsList retrieves list of SMS in an inbox.",
-	"SmsCount":             "SmsCount retrieves count of SMS per inbox type.",
-	"SmsSend":              "SmsSend sends an SMS.",
-	"SmsSendStatus":        "SmsSendStatus retrieves SMS send status information.",
-	"SmsReadSet":           "SmsReadSet sets the read status of a SMS.",
-	"SmsDelete":            "SmsDelete deletes a specified SMS.",
-	"UssdStatus":           "UssdStatus retrieves current USSD session status information.",
-	"UssdCode":             "UssdCode sends a USSD code to the Hilink device.",
-	"UssdContent":          "UssdContent retrieves content buffer of the active USSD session.",
-	"UssdRelease":          "UssdRelease releases the active USSD session.",
-	"DdnsList":             "DdnsList retrieves list of DDNS providers.",
-	"LogPath":              "LogPath retrieves device log path (URL).",
-	"LogInfo":              "LogInfo retrieves current log setting information.",
-	"PhonebookGroupList":   "PhonebookGroupList retrieves list of the phonebook groups.",
-	"PhonebookCount":       "PhonebookCount retrieves count of phonebook entries per group.",
-	"PhonebookImport":      "PhonebookImport imports SIM contacts into specified phonebook group.",
-	"PhonebookDelete":      "PhonebookDelete deletes a specified phonebook entry.",
-	"PhonebookList":        "PhonebookList retrieves list of phonebook entries from a specified group.",
-	"PhonebookCreate":      "PhonebookCreate creates a new phonebook entry.",
-	"FirewallFeatures":     "FirewallFeatures retrieves firewall security feature information.",
-	"DmzConfig":            "DmzConfig retrieves DMZ status and IP address of DMZ host.",
-	"DmzConfigSet":         "DmzConfigSet enables or disables the DMZ and the DMZ IP address of the device.",
-	"SipAlg":               "SipAlg retrieves status and port of the SIP application-level gateway.",
-	"SipAlgSet":            "SipAlgSet enables/disables SIP application-level gateway and sets SIP port.",
-	"NatType":              "NatType retrieves NAT type.",
-	"NatTypeSet":           "NatTypeSet sets NAT type (values: 0, 1).",
-	"Upnp":                 "Upnp retrieves the status of UPNP.",
-	"UpnpSet":              "UpnpSet enables/disables UPNP.",
+	"Plan":                       "Plan determines the changes needed to reconcile the device to the desired state, for example:  \t{ \t\t\"wlan/basic-settings\": {\"WifiSsid\": \"home\", \"WifiHide\": \"0\"}, \t\t\"dhcp/settings\": {\"DhcpStartIPAddress\": \"192.168.8.100\"}, \t\t\"security/upnp\": {\"UpnpStatus\": \"0\"}, \t\t\"dialup/profiles\": { \t\t\t\"CurrentProfile\": \"2\", \t\t\t\"Profiles\": {\"Profile\": [{\"Name\": \"work\", \"ApnName\": \"internet\"}]} \t\t} \t}  The desired state uses the same endpoints and elements as a Snapshot, but only lists the values to be changed. Settings endpoints are updated by posting the current settings with the desired values merged in. Connection profiles are matched by name, adding missing profiles and modifying changed ones; profiles not listed are left in place.",
+	"Apply":                      "Apply applies the plan steps in order, stopping at the first failed step.",
+	"ATCommand":                  "ATCommand sends an AT command (ie, \"AT^SYSINFOEX\") via the HTTP passthrough of firmware that supports it, returning the raw response.  The device generally needs to be in debug mode (see DeviceModeSet), and the client must be created with the EnableATCommands option.",
+	"DoCheckOK":                  "DoCheckOK sends a request to the server with the provided path (see Do), checking that the device responded with OK.",
+	"ModeLTEBands":               "ModeLTEBands retrieves the LTE bands allowed by the network mode settings.",
+	"ModeSetLTEBands":            "ModeSetLTEBands locks the device to the LTE band numbers (ie, 3 and 7), retaining the other network mode settings. No bands allows all bands.",
+	"Battery":                    "Battery retrieves the battery state of E5-series (mobile hotspot) devices.",
+	"CellInfo":                   "CellInfo retrieves the serving cell information from the extended signal values, and the neighbour cells from api/net/cell-info where available.",
+	"DeviceTime":                 "DeviceTime retrieves the current date/time of the device clock.  As the device reports its local time without a time zone, the time is interpreted in the device's time zone (see ParseTime).",
+	"DeviceTimeSet":              "DeviceTimeSet sets the date/time of the device clock. This is distinct from the NTP configuration, and is useful for devices that cannot reach an NTP server.",
+	"DeviceTimeSync":             "DeviceTimeSync sets the date/time of the device clock to the host's current time.",
+	"TimeZone":                   "TimeZone retrieves the device time zone setting.",
+	"TimeZoneSet":                "TimeZoneSet sets the device time zone setting.",
+	"ParseTime":                  "ParseTime parses a date/time value reported by the device (ie, SMS and log dates) in the device's time zone.  The time zone is retrieved from the device on first use (see TimeZone), unless set with the Location option. The host's local time zone is used when the device does not report its time zone.  Besides TimeLayout, the variants reported by some firmwares are accepted (see timeLayouts).",
+	"ConnectAndWait":             "ConnectAndWait connects the device to the network provider (see Connect), and waits until the connection is established, returning the final connection status. When the context has no deadline, DefaultConnectTimeout applies.  ErrConnectionFailed is returned when the device reports the connection as failed, and the context error when the connection is not established in time.",
+	"DisconnectAndWait":          "DisconnectAndWait disconnects the device from the network provider (see Disconnect), and waits until the connection is torn down, returning the final connection status. When the context has no deadline, DefaultConnectTimeout applies.",
+	"DataPlan":                   "DataPlan retrieves the monthly data plan.",
+	"DataPlanSet":                "DataPlanSet sets the monthly data plan.",
+	"DeviceInformation":          "DeviceInformation retrieves the general device information.",
+	"SmsCounts":                  "SmsCounts retrieves the SMS counts per box.",
+	"DhcpSettings":               "DhcpSettings retrieves the LAN and DHCP server settings.",
+	"DhcpConfigSet":              "DhcpConfigSet sets the LAN and DHCP server settings, retaining the settings not represented by DhcpSettings. Changing the LAN address restarts the device network, and the client must be recreated with the new address.",
+	"StaticLeases":               "StaticLeases retrieves the DHCP static leases.",
+	"StaticLeaseAdd":             "StaticLeaseAdd adds a DHCP static lease, or updates the lease of the MAC address.",
+	"StaticLeaseRemove":          "StaticLeaseRemove removes the DHCP static lease of the MAC address. Removing a missing lease succeeds without changes.",
+	"DialupConnectionGet":        "DialupConnectionGet retrieves the connection (dialup) settings.",
+	"DialupConnectionSet":        "DialupConnectionSet sets the connection (dialup) settings, retaining the other settings reported by the device.",
+	"ConnectModeSet":             "ConnectModeSet sets the connect mode, retaining the other connection (dialup) settings.",
+	"MTUSet":                     "MTUSet sets the MTU of the connection, retaining the other connection (dialup) settings.",
+	"MaxIdleTimeSet":             "MaxIdleTimeSet sets the idle time after which the connection is torn down, retaining the other connection (dialup) settings. Zero never disconnects.",
+	"CradleStatus":               "CradleStatus retrieves the cradle (Ethernet WAN) status information.",
+	"FetchAll":                   "FetchAll concurrently retrieves the read-only endpoints (ie, \"api/monitoring/status\", see Do), returning the results keyed by endpoint. Endpoints not retrieved before the context is closed receive the context error.",
+	"PasswordChange":             "PasswordChange changes the password of the logged in user. On success, the new password is used for subsequent logins.",
+	"NewSessionAndTokenID":       "NewSessionAndTokenID starts a session with the server, and returns the session and token.",
+	"SetSessionAndTokenID":       "SetSessionAndTokenID sets the sessionID and tokenID for the Client.",
+	"SessionAndTokenID":          "SessionAndTokenID returns the current sessionID and tokenID for the Client, allowing the session to be exported and later restored with SetSessionAndTokenID.",
+	"GlobalConfig":               "GlobalConfig retrieves global Hilink configuration.",
+	"NetworkTypes":               "NetworkTypes retrieves available network types.",
+	"PCAssistantConfig":          "PCAssistantConfig retrieves PC Assistant configuration.",
+	"DeviceConfig":               "DeviceConfig retrieves device configuration.",
+	"WebUIConfig":                "WebUIConfig retrieves WebUI configuration.",
+	"SmsConfig":                  "SmsConfig retrieves device SMS configuration.",
+	"WlanConfig":                 "WlanConfig retrieves basic WLAN settings.",
+	"DhcpConfig":                 "DhcpConfig retrieves DHCP configuration.",
+	"CradleStatusInfo":           "CradleStatusInfo retrieves cradle status information.",
+	"CradleMACSet":               "CradleMACSet sets the MAC address for the cradle.",
+	"CradleMAC":                  "CradleMAC retrieves cradle MAC address.",
+	"AutorunVersion":             "AutorunVersion retrieves device autorun version.",
+	"DeviceBasicInfo":            "DeviceBasicInfo retrieves basic device information.",
+	"PublicKey":                  "PublicKey retrieves webserver public key.",
+	"DeviceControl":              "DeviceControl sends a control code to the device.",
+	"DeviceReboot":               "DeviceReboot restarts the device.",
+	"DeviceReset":                "DeviceReset resets the device configuration.",
+	"DeviceBackup":               "DeviceBackup backups device configuration and retrieves backed up configuration data as a base64 encoded string.",
+	"DeviceBackupData":           "DeviceBackupData backups device configuration and retrieves the backed up configuration file (nvram.bak), for use with DeviceRestore.",
+	"DeviceRestore":              "DeviceRestore restores the device configuration from a configuration file retrieved with DeviceBackupData, uploading it as the WebUI does. The device restarts once the configuration is restored.",
+	"DeviceShutdown":             "DeviceShutdown shuts down the device.",
+	"DeviceFeatures":             "DeviceFeatures retrieves device feature information.",
+	"DeviceInfo":                 "DeviceInfo retrieves general device information (see DeviceInformation for the typed information).",
+	"DeviceModeSet":              "DeviceModeSet sets the device mode (0-project, 1-debug).",
+	"FastbootFeatures":           "FastbootFeatures retrieves fastboot feature information.",
+	"PowerFeatures":              "PowerFeatures retrieves power feature information.",
+	"TetheringFeatures":          "TetheringFeatures retrieves USB tethering feature information.",
+	"SignalInfo":                 "SignalInfo retrieves network signal information.",
+	"ConnectionInfo":             "ConnectionInfo retrieves connection (dialup) information.",
+	"ConnectionProfile":          "ConnectionProfile sets the connection (dialup) information for roaming and max idle time (in seconds), retaining the other settings.  Deprecated: use DialupConnectionSet, RoamingSet or MaxIdleTimeSet.",
+	"Roaming":                    "Roaming determines if automatically connecting while roaming is enabled.",
+	"RoamingSet":                 "RoamingSet enables or disables automatically connecting while roaming.",
+	"RoamingEnable":              "RoamingEnable enables automatically connecting while roaming.",
+	"RoamingDisable":             "RoamingDisable disables automatically connecting while roaming.",
+	"GlobalFeatures":             "GlobalFeatures retrieves global feature information.",
+	"Language":                   "Language retrieves current language.",
+	"LanguageSet":                "LanguageSet sets the language.",
+	"NotificationInfo":           "NotificationInfo retrieves notification information.",
+	"SimInfo":                    "SimInfo retrieves SIM card information.",
+	"StatusInfo":                 "StatusInfo retrieves general device status information.",
+	"TrafficInfo":                "TrafficInfo retrieves traffic statistic information.",
+	"TrafficClear":               "TrafficClear clears the current traffic statistics.",
+	"MonthInfo":                  "MonthInfo retrieves the month download statistic information.",
+	"WlanMonthInfo":              "WlanMonthInfo retrieves the WLAN month download statistic information.",
+	"NetworkInfo":                "NetworkInfo retrieves network provider information.",
+	"WifiFeatures":               "WifiFeatures retrieves wifi feature information.",
+	"ModeList":                   "ModeList retrieves available network modes.",
+	"ModeInfo":                   "ModeInfo retrieves network mode settings information.",
+	"ModeNetworkInfo":            "ModeNetworkInfo retrieves current network mode information.",
+	"ModeSet":                    "ModeSet sets the network mode.",
+	"ModeSetNR":                  "ModeSetNR sets the network mode, including the 5G NR band mask, on 5G devices (ie, B818, H112, H122).",
+	"NRModeInfo":                 "NRModeInfo retrieves the 5G NR mode (SA/NSA) settings information.",
+	"NRModeSet":                  "NRModeSet sets the 5G NR mode (SA/NSA).",
+	"PinInfo":                    "PinInfo retrieves SIM PIN status information.",
+	"PinEnter":                   "PinEnter enters a SIM PIN.",
+	"PinEnterForce":              "PinEnterForce enters a SIM PIN, bypassing the PinGuard option.",
+	"PinActivate":                "PinActivate activates a SIM PIN.",
+	"PinDeactivate":              "PinDeactivate deactivates a SIM PIN.",
+	"PinChange":                  "PinChange changes a SIM PIN.",
+	"PinEnterPuk":                "PinEnterPuk enters a SIM PIN puk.",
+	"PinSaveInfo":                "PinSaveInfo retrieves SIM PIN save information.",
+	"PinSimlockInfo":             "PinSimlockInfo retrieves SIM lock information.",
+	"MobileDataSwitch":           "MobileDataSwitch retrieves mobile data switch information.",
+	"MobileDataEnabled":          "MobileDataEnabled determines if the mobile data switch is enabled.",
+	"MobileDataSet":              "MobileDataSet enables or disables the mobile data switch.",
+	"MobileDataSwitchState":      "MobileDataSwitchState sets the mobile data switch state (\"1\" enabled, \"0\" disabled).",
+	"MobileDataActivate":         "MobileDataActivate enables the mobile data switch.",
+	"MobileDataDeactivate":       "MobileDataDeactivate disables the mobile data switch.",
+	"DialupFeatures":             "DialupFeatures retrieves dialup feature information.",
+	"DialupControlAllowed":       "DialupControlAllowed determines if dialup control (ie, Connect and Disconnect) is available on the device. Operator customized firmwares may disable manual dialup control, in which case the device manages the connection itself.",
+	"Connect":                    "Connect connects the Hilink device to the network provider.",
+	"Disconnect":                 "Disconnect disconnects the Hilink device from the network provider.",
+	"ProfileInfo":                "ProfileInfo retrieves profile information (ie, APN).",
+	"Profiles":                   "Profiles retrieves the connection profiles, and the index of the default profile.",
+	"ProfileAdd":                 "ProfileAdd adds a connection profile. When setDefault is true, the added profile becomes the default profile, otherwise the default profile is left unchanged.",
+	"ProfileModify":              "ProfileModify replaces the connection profile with the index, leaving the default profile unchanged.",
+	"ProfileSetDefault":          "ProfileSetDefault makes the connection profile with the index the default profile.",
+	"ProfileDelete":              "Delete connection profile",
+	"SmsFeatures":                "SmsFeatures retrieves SMS feature information.",
+	"SmsList":                    "SmsList retrieves list of SMS in an inbox (see SmsListAll and SmsIter for paging through all messages).",
+	"SmsListMarkRead":            "SmsListMarkRead retrieves list of SMS in an inbox (see SmsList), and marks the returned unread messages as read, as the WebUI does when displaying them.",
+	"SmsCount":                   "SmsCount retrieves count of SMS per inbox type (see SmsCounts for the typed counts).",
+	"SmsSend":                    "SmsSend sends an SMS. Messages longer than a single SMS, up to SmsMaxSegments segments, are sent as a concatenated SMS, split by the device (see SmsSendParts for firmware that does not).",
+	"SmsSendParts":               "SmsSendParts sends a long SMS as separate messages, one per segment of the concatenated SMS, in order. It is intended for firmware that rejects messages longer than a single SMS.",
+	"SmsSendStatus":              "SmsSendStatus retrieves SMS send status information.",
+	"SmsReadSet":                 "SmsReadSet sets the read status of the specified SMS, in a single request.",
+	"SmsDelete":                  "SmsDelete deletes the specified SMS, in a single request (see SmsDeleteAll to empty a box).",
+	"UssdStatus":                 "UssdStatus retrieves current USSD session status information.",
+	"UssdCode":                   "UssdCode sends a USSD code to the Hilink device.",
+	"UssdContent":                "UssdContent retrieves content buffer of the active USSD session, decoding UCS-2 hex encoded content (see DecodeUssd).",
+	"UssdContentRaw":             "UssdContentRaw retrieves content buffer of the active USSD session, as returned by the device.",
+	"UssdRelease":                "UssdRelease releases the active USSD session.",
+	"DdnsList":                   "DdnsList retrieves list of DDNS providers.",
+	"LogPath":                    "LogPath retrieves device log path (URL).",
+	"LogInfo":                    "LogInfo retrieves current log setting information.",
+	"PhonebookGroupList":         "PhonebookGroupList retrieves list of the phonebook groups.",
+	"PhonebookCount":             "PhonebookCount retrieves count of phonebook entries per group.",
+	"PhonebookImport":            "PhonebookImport imports SIM contacts into specified phonebook group.",
+	"PhonebookDelete":            "PhonebookDelete deletes a specified phonebook entry.",
+	"PhonebookList":              "PhonebookList retrieves list of phonebook entries from a specified group.",
+	"PhonebookCreate":            "PhonebookCreate creates a new phonebook entry.",
+	"FirewallFeatures":           "FirewallFeatures retrieves firewall security feature information.",
+	"DmzConfig":                  "DmzConfig retrieves DMZ status and IP address of DMZ host.",
+	"DmzConfigSet":               "DmzConfigSet enables or disables the DMZ and the DMZ IP address of the device.",
+	"SipAlg":                     "SipAlg retrieves status and port of the SIP application-level gateway.",
+	"SipAlgSet":                  "SipAlgSet enables/disables SIP application-level gateway and sets SIP port.",
+	"NatType":                    "NatType retrieves NAT type.",
+	"NatTypeSet":                 "NatTypeSet sets NAT type (values: 0, 1).",
+	"Upnp":                       "Upnp retrieves the status of UPNP.",
+	"UpnpSet":                    "UpnpSet enables/disables UPNP.",
+	"HostList":                   "HostList retrieves the hosts known to the device on its LAN, both wired and wireless, including recently disconnected hosts (see LanHost.Active).",
+	"WifiStationList":            "WifiStationList retrieves the stations connected to the WiFi network.",
+	"MACFilter":                  "MACFilter retrieves the WiFi MAC filter of the primary SSID.",
+	"MACFilterSet":               "MACFilterSet sets the WiFi MAC filter of the primary SSID, replacing all filtered MAC addresses.",
+	"MACFilterModeSet":           "MACFilterModeSet sets the WiFi MAC filter mode of the primary SSID, retaining the filtered MAC addresses.",
+	"MACFilterAdd":               "MACFilterAdd adds mac to the WiFi MAC filter of the primary SSID. Adding a listed MAC address succeeds without changes.",
+	"MACFilterReplace":           "MACFilterReplace replaces the MAC address old of the WiFi MAC filter of the primary SSID with mac.",
+	"MACFilterRemove":            "MACFilterRemove removes mac from the WiFi MAC filter of the primary SSID. Removing a MAC address not listed succeeds without changes.",
+	"WifiBlockMAC":               "WifiBlockMAC blocks the WiFi client with the MAC address from connecting to the primary SSID: with a deny list, the MAC address is added to it; with an allow list, it is removed from it. A disabled filter is switched to a deny list.",
+	"WifiUnblockMAC":             "WifiUnblockMAC allows the WiFi client with the MAC address to connect to the primary SSID: with a deny list, the MAC address is removed from it; with an allow list, it is added to it. A disabled filter is left unchanged.",
+	"NetworkMode":                "NetworkMode retrieves the network mode setting.",
+	"NetworkModeSet":             "NetworkModeSet sets the network mode, retaining the band settings.",
+	"ModeSetAuto":                "ModeSetAuto sets the network mode to automatic, retaining the band settings.",
+	"ModeSetLTEOnly":             "ModeSetLTEOnly restricts the network mode to LTE, retaining the band settings.",
+	"ModeSet3GOnly":              "ModeSet3GOnly restricts the network mode to 3G, retaining the band settings.",
+	"Notifications":              "Notifications retrieves the device notification status information.",
+	"PinStatus":                  "PinStatus retrieves the SIM PIN status, including the remaining PIN and PUK attempts.",
+	"NetworkScan":                "NetworkScan scans for the available operator networks. Scans take up to NetworkScanTimeout, and devices generally drop the data connection while scanning.",
+	"NetworkRegister":            "NetworkRegister manually registers with the operator network plmn (MCC and MNC, ie \"26201\"), using the radio access technology, as found by NetworkScan. The selection persists until NetworkRegisterAuto is called.",
+	"NetworkRegisterAuto":        "NetworkRegisterAuto returns to automatic operator network selection.",
+	"ProbeEndpoints":             "ProbeEndpoints issues a GET against each known read endpoint, reporting which endpoints the firmware implements. The response data is discarded.",
+	"RebootSchedule":             "RebootSchedule retrieves the reboot schedule of the device.",
+	"RebootScheduleSet":          "RebootScheduleSet sets the reboot schedule of the device, retaining the other settings reported by the device.",
+	"PortForwardResources":       "PortForwardResources returns the ResourceClient of the port forwards (virtual servers), identified by protocol and WAN port (ie, \"6:8080\").",
+	"StaticLeaseResources":       "StaticLeaseResources returns the ResourceClient of the DHCP static leases, identified by MAC address.",
+	"TimeRuleResources":          "TimeRuleResources returns the ResourceClient of the access time rules (parental control), identified by name, where firmware supports it.",
+	"MACFilterResources":         "MACFilterResources returns the ResourceClient of the MAC filter entries of the primary SSID, identified by MAC address. Entries have the single value \"Mac\".",
+	"ProfileResources":           "ProfileResources returns the ResourceClient of the connection (APN) profiles, identified by name.",
+	"Go":                         "Go starts the watcher in the background with a Runner (see Runner), that is stopped with Stop or when the client is closed.",
+	"Close":                      "Close stops the watchers started with Go, and closes the idle connections to the device.",
+	"ScreenShowPassword":         "ScreenShowPassword determines if the WiFi password is shown on the screen of E5-series (mobile hotspot) devices.",
+	"ScreenShowPasswordSet":      "ScreenShowPasswordSet sets whether the WiFi password is shown on the screen of E5-series (mobile hotspot) devices.",
+	"ScreenShowSSID":             "ScreenShowSSID determines if the SSID is shown on the screen of E5-series (mobile hotspot) devices, where firmware supports it.",
+	"ScreenShowSSIDSet":          "ScreenShowSSIDSet sets whether the SSID is shown on the screen of E5-series (mobile hotspot) devices, where firmware supports it.",
+	"ScreenTimeout":              "ScreenTimeout retrieves the screen timeout of E5-series (mobile hotspot) devices, where firmware supports it.",
+	"ScreenTimeoutSet":           "ScreenTimeoutSet sets the screen timeout of E5-series (mobile hotspot) devices, with second precision, where firmware supports it.",
+	"RefreshSession":             "RefreshSession starts a new session with the server, logging in again when credentials were provided. Concurrent calls share a single refresh, with callers arriving while a refresh is in progress waiting for, and receiving, its result. This prevents concurrent requests failing with an expired session from each logging in, which can lock the account on some devices.",
+	"Signal":                     "Signal retrieves the network signal information as numeric values.",
+	"SmsMessages":                "SmsMessages retrieves list of SMS in an inbox (see SmsList), as typed messages, with the dates parsed in the device's time zone.",
+	"SmsDeleteAll":               "SmsDeleteAll deletes all messages of a box, returning the number of deleted messages. Messages are deleted SmsMaxReadCount at a time.",
+	"SmsListAll":                 "SmsListAll retrieves all messages of a box, newest first, paging through the box (see SmsIter).",
+	"SmsIter":                    "SmsIter returns an iterator over the messages of a box, retrieving the pages as needed, starting at the page of the options, for example:  \tit := client.SmsIter(SmsListOptions{BoxType: SmsBoxTypeInbox}) \tfor it.Next() { \t\tm := it.Message() \t\t// ... \t} \tif err := it.Err(); err != nil { \t\t// ... \t}  Count defaults to, and is capped at, SmsMaxReadCount, as the device returns at most SmsMaxReadCount messages per page. Messages stored or deleted while iterating may shift the pages, so that messages are skipped or repeated.",
+	"SmsDraftSave":               "SmsDraftSave saves an SMS to the drafts, without sending it.",
+	"SmsDraftUpdate":             "SmsDraftUpdate replaces the content and recipients of the draft.",
+	"SmsDrafts":                  "SmsDrafts retrieves all drafts, newest first.",
+	"SmsDraftSend":               "SmsDraftSend sends the draft to its recipients. The device moves the sent message to the outbox.",
+	"SmsOutbox":                  "SmsOutbox retrieves all sent messages, newest first.",
+	"SmsCopyFromSim":             "SmsCopyFromSim copies the messages stored on the SIM to the device storage, ie before moving the SIM to another modem. The WebUI API has no endpoint to copy messages to the SIM.",
+	"SmsMoveFromSim":             "SmsMoveFromSim moves the messages stored on the SIM to the device storage, freeing the SIM storage (see SmsCopyFromSim).",
+	"SmsSimCapacity":             "SmsSimCapacity retrieves the message capacity of the SIM storage (see SmsCounts).",
+	"SmsLocalCapacity":           "SmsLocalCapacity retrieves the message capacity of the device storage (see SmsCounts).",
+	"SmsSettings":                "SmsSettings retrieves the SMS settings (see SmsConfig).",
+	"SmsConfigSet":               "SmsConfigSet sets the SMS settings, retaining the other settings of the SMS configuration (see SmsConfig).",
+	"SmsSendAndConfirm":          "SmsSendAndConfirm sends an SMS (see SmsSend), and polls the send status until the device reports the result for every recipient. When the context has no deadline, DefaultSmsSendTimeout applies.  A send status left by an earlier message is ignored, until the status changes. While the send status is unchanged, or not supported by the firmware, the outbox is polled instead, the recipients of the message stored in the outbox being reported as sent.  The result is returned with the context error when waiting stops before all results are reported, listing the remaining recipients as pending.",
+	"SettingsSnapshot":           "SettingsSnapshot retrieves a snapshot of the device settings. Endpoints not supported by the device are omitted.",
+	"Snapshot":                   "Snapshot retrieves a snapshot of the full device state, querying all known read-only endpoints concurrently. Endpoints of modules disabled on the device, or not supported by the device, are omitted.",
+	"WriteSnapshot":              "WriteSnapshot streams a snapshot of the full device state (see Snapshot) to w as a single JSON object, writing each endpoint as it is retrieved.",
+	"PDPInfo":                    "PDPInfo retrieves the requested and negotiated IP types, and the assigned addresses of the mobile connection. IPv6 DNS servers are only reported by some firmware.",
+	"Status":                     "Status retrieves the general device status information.",
+	"DoEach":                     "DoEach sends a request to the server with the provided path (see Do), decoding the response as it is received, and calling fn for each element named el (ie, \"Message\"), at any depth. Memory use is bounded by the size of a single element, instead of the whole response.  The client is busy while the response is decoded: fn must not send requests with the client. An error returned by fn stops the decoding, and is returned.",
+	"SmsEach":                    "SmsEach retrieves list of SMS in an inbox (see SmsList), calling fn for each message as it is decoded (see DoEach).",
+	"PhonebookEach":              "PhonebookEach retrieves list of phonebook entries from a specified group (see PhonebookList), calling fn for each entry as it is decoded (see DoEach).",
+	"StatisticFeatures":          "StatisticFeatures retrieves the data statistic feature information (ie, whether data statistics and limits are enabled).",
+	"StatisticsEnabled":          "StatisticsEnabled determines if the data statistics feature is enabled on the device. Data limits (ie, the monthly data plan) are only enforced when the feature is enabled.",
+	"StatisticsEnabledSet":       "StatisticsEnabledSet enables or disables the data statistics feature.",
+	"MonthClear":                 "MonthClear clears the month download statistics.",
+	"WlanMonthClear":             "WlanMonthClear clears the WLAN month download statistics.",
+	"MonthResetDay":              "MonthResetDay retrieves the day of the month on which the month statistics are reset.",
+	"MonthResetDaySet":           "MonthResetDaySet sets the day of the month (1-31) on which the month statistics are reset, keeping the other data plan settings unchanged.",
+	"TrafficReport":              "TrafficReport retrieves the traffic statistics per interface.",
+	"TrafficStats":               "TrafficStats retrieves the traffic statistics of the mobile connection, merged with the month statistics, where the firmware provides them.",
+	"FirmwareUpdateCheck":        "FirmwareUpdateCheck causes the device to check for a new firmware version. The result is retrieved with FirmwareUpdate once the check completes.",
+	"FirmwareUpdate":             "FirmwareUpdate retrieves the online update status information.",
+	"FirmwareVersion":            "FirmwareVersion retrieves the current and latest firmware versions. The latest version is the one reported by the last check (see FirmwareUpdateCheck).",
+	"FirmwareUpdateCheckAndWait": "FirmwareUpdateCheckAndWait causes the device to check for a new firmware version (see FirmwareUpdateCheck), and polls the status until the check completes. When the context has no deadline, DefaultFirmwareCheckTimeout applies.  The result of an earlier check is not taken as the result, until the status changes (ie, to checking).",
+	"FirmwareUpdateStart":        "FirmwareUpdateStart causes the device to download and install the offered firmware (see FirmwareUpdate). The device reboots once the firmware is installed.",
+	"FirmwareUpdateCancel":       "FirmwareUpdateCancel cancels the download of the offered firmware.",
+	"FirmwareUpdateAndWait":      "FirmwareUpdateAndWait starts the firmware update (see FirmwareUpdateStart), and polls the status until the firmware is downloaded and being installed, calling progress (if not nil) with each status. When the context has no deadline, DefaultFirmwareUpdateTimeout applies. A failed download left by an earlier update is ignored, until the status changes.  The device is unreachable while the firmware is installed, and reboots once installed.",
+	"UssdSendAndWait":            "UssdSendAndWait sends a USSD code (see UssdCode), waits for the network response, and retrieves it (see UssdContent). When the context has no deadline, DefaultUssdTimeout applies. The USSD session is released when no response is received.  Menu responses can be replied to with UssdSendAndWait, or with a UssdSession.",
+	"WANIP":                      "WANIP retrieves the WAN addresses of the mobile connection from the device status, falling back to the connection (dialup) settings on firmware that reports them there. The addresses are empty when not connected.  Note that the WAN address is only the public address when the carrier does not use carrier-grade NAT.",
+	"WlanHandover":               "WlanHandover retrieves the band preference (handover) setting of dual-band devices.",
+	"WlanHandoverSet":            "WlanHandoverSet sets the band preference (handover) setting of dual-band devices.",
+	"GuestQuota":                 "GuestQuota retrieves the session quota of guest WiFi networks, where firmware supports it.",
+	"GuestQuotaSet":              "GuestQuotaSet sets the session quota of guest WiFi networks, where firmware supports it. The duration and data limit are rounded up to the next minute and megabyte, so that a quota below the unit is not set as unlimited.",
+	"WifiEnabledSet":             "WifiEnabledSet enables or disables the WiFi radio, retaining the other basic WLAN settings.",
+	"WlanBasicSettings":          "WlanBasicSettings retrieves the basic WLAN settings.",
+	"WlanBasicSettingsSet":       "WlanBasicSettingsSet sets the basic WLAN settings, retaining the settings not represented by WlanBasicSettings. Devices generally restart the WiFi radio, disconnecting clients.",
+	"WlanSecuritySettings":       "WlanSecuritySettings retrieves the WLAN security settings.",
+	"WlanSecuritySettingsSet":    "WlanSecuritySettingsSet sets the WLAN security settings, retaining the settings not represented by WlanSecuritySettings (ie, WEP keys and WPS). Devices generally restart the WiFi radio, disconnecting clients.  Firmware requiring RSA encrypted requests for the endpoint needs the RSAEncryption option.",
+	"WifiPasswordSet":            "WifiPasswordSet sets the WPA passphrase of the WiFi network, retaining the other WLAN security settings.",
 }
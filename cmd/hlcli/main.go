@@ -124,9 +124,12 @@ func doHelpMethodParams(methodName string) {
 		str += "  -" + methodParamMap[method.Name][i-1]
 		if methodTyp.Kind() != reflect.Bool {
 			str += "="
-			if lastVd {
+			switch {
+			case lastVd:
 				str += p.String()[2:]
-			} else {
+			case p.Kind() == reflect.Struct:
+				str += "json"
+			default:
 				str += p.String()
 			}
 			if lastVd {
@@ -170,6 +173,7 @@ func main() {
 
 	// add method params to flagset
 	in := make([]reflect.Value, method.Type.NumIn())
+	structs := make(map[int]*string)
 	for i := 1; i < method.Type.NumIn(); i++ {
 		p := method.Type.In(i)
 		n := methodParamMap[method.Name][i-1]
@@ -184,6 +188,10 @@ func main() {
 			v = fs.Uint(n, 0, "")
 		case reflect.String:
 			v = fs.String(n, "", "")
+		case reflect.Struct:
+			// struct params are passed as json
+			structs[i] = fs.String(n, "{}", "")
+			v = reflect.New(p).Interface()
 		}
 
 		// special ...string case
@@ -197,6 +205,11 @@ func main() {
 
 	// parse flags
 	fs.Parse(os.Args[2:])
+	for i, str := range structs {
+		if err := json.Unmarshal([]byte(*str), in[i].Addr().Interface()); err != nil {
+			doExit("error: invalid -%s: %v", methodParamMap[method.Name][i-1], err)
+		}
+	}
 
 	// hilink options
 	opts := []hilink.Option{
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/jpunie/hilink"
+	"github.com/jpunie/hilink/exporter"
+)
+
+var (
+	flagEndpoint = flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	flagListen   = flag.String("l", ":9770", "listen address")
+	flagInterval = flag.Duration("interval", 0, "scrape interval")
+	flagDebug    = flag.Bool("v", false, "enable verbose")
+)
+
+func main() {
+	flag.Parse()
+
+	// options
+	opts := []hilink.Option{
+		hilink.URL(*flagEndpoint),
+		hilink.LazySession,
+	}
+	if *flagDebug {
+		opts = append(opts, hilink.Log(log.Printf, log.Printf))
+	}
+
+	// create client
+	client, err := hilink.NewClient(opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// scrape
+	e := &exporter.Exporter{
+		Client:   client,
+		Interval: *flagInterval,
+		OnError: func(err error) {
+			log.Printf("scrape: %v", err)
+		},
+	}
+	go e.Run(context.Background())
+
+	http.Handle("/metrics", e)
+	log.Fatal(http.ListenAndServe(*flagListen, nil))
+}
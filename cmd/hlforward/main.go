@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jpunie/hilink"
+	"github.com/jpunie/hilink/forwarder"
+)
+
+var (
+	flagEndpoint = flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	flagUser     = flag.String("u", "admin", "username")
+	flagPass     = flag.String("p", "", "password")
+	flagWebhook  = flag.String("webhook", "", "webhook url sms messages are posted to")
+	flagAuth     = flag.String("webhook-auth", "", "webhook authorization header")
+	flagSMTP     = flag.String("smtp", "", "smtp server address (ie, smtp.example.com:587)")
+	flagSMTPUser = flag.String("smtp-user", "", "smtp username")
+	flagSMTPPass = flag.String("smtp-pass", "", "smtp password")
+	flagFrom     = flag.String("from", "", "email sender address")
+	flagTo       = flag.String("to", "", "email recipient addresses (comma separated)")
+	flagSubject  = flag.String("subject", forwarder.DefaultSubject, "email subject template")
+	flagState    = flag.String("state", "", "state file, to not forward sms messages again after restarts")
+	flagDelete   = flag.Bool("delete", false, "delete forwarded sms messages from the device")
+	flagDebug    = flag.Bool("v", false, "enable verbose")
+)
+
+func main() {
+	flag.Parse()
+
+	// options
+	opts := []hilink.Option{
+		hilink.URL(*flagEndpoint),
+		hilink.LazySession,
+	}
+	if *flagPass != "" {
+		opts = append(opts, hilink.Auth(*flagUser, *flagPass))
+	}
+	if *flagDebug {
+		opts = append(opts, hilink.Log(log.Printf, log.Printf))
+	}
+
+	// create client
+	client, err := hilink.NewClient(opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	f := &forwarder.Forwarder{
+		Client:  client,
+		Webhook: *flagWebhook,
+		Subject: *flagSubject,
+		Delete:  *flagDelete,
+		OnForward: func(m forwarder.Message) {
+			log.Printf("forwarded sms %d from %s", m.Index, m.Phone)
+		},
+		OnError: func(err error) {
+			log.Printf("error: %v", err)
+		},
+	}
+	if *flagAuth != "" {
+		f.Header = http.Header{"Authorization": {*flagAuth}}
+	}
+	if *flagSMTP != "" {
+		f.SMTP = &forwarder.SMTP{
+			Addr:     *flagSMTP,
+			Username: *flagSMTPUser,
+			Password: *flagSMTPPass,
+			From:     *flagFrom,
+			To:       strings.Split(*flagTo, ","),
+		}
+	}
+	if *flagState != "" {
+		if f.Store, err = hilink.OpenFileStore(*flagState); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := f.Run(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jpunie/hilink"
+	"github.com/jpunie/hilink/mqttbridge"
+)
+
+var (
+	flagEndpoint   = flag.String("endpoint", "http://192.168.8.1/", "api endpoint")
+	flagUser       = flag.String("u", "admin", "username")
+	flagPass       = flag.String("p", "", "password")
+	flagBroker     = flag.String("broker", "localhost:1883", "mqtt broker address")
+	flagBrokerUser = flag.String("broker-user", "", "mqtt broker username")
+	flagBrokerPass = flag.String("broker-pass", "", "mqtt broker password")
+	flagPrefix     = flag.String("prefix", mqttbridge.DefaultPrefix, "mqtt topic prefix")
+	flagState      = flag.String("state", "", "state file, to not republish sms messages after restarts")
+	flagDelete     = flag.Bool("delete", false, "delete published sms messages from the device")
+	flagDiscovery  = flag.Bool("discovery", false, "publish home assistant discovery configs")
+	flagNotifyTo   = flag.String("notify-to", "", "phone number of the home assistant sms notify entity")
+	flagDebug      = flag.Bool("v", false, "enable verbose")
+)
+
+func main() {
+	flag.Parse()
+
+	// options
+	opts := []hilink.Option{
+		hilink.URL(*flagEndpoint),
+		hilink.LazySession,
+	}
+	if *flagPass != "" {
+		opts = append(opts, hilink.Auth(*flagUser, *flagPass))
+	}
+	if *flagDebug {
+		opts = append(opts, hilink.Log(log.Printf, log.Printf))
+	}
+
+	// create client
+	client, err := hilink.NewClient(opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	b := &mqttbridge.Bridge{
+		Client:      client,
+		Prefix:      *flagPrefix,
+		Delete:      *flagDelete,
+		Discovery:   *flagDiscovery,
+		SmsNotifyTo: *flagNotifyTo,
+		OnError: func(err error) {
+			log.Printf("error: %v", err)
+		},
+	}
+	if *flagState != "" {
+		if b.Store, err = hilink.OpenFileStore(*flagState); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// connect to broker
+	conn, err := mqttbridge.Dial(*flagBroker, mqttbridge.Options{
+		ClientID: "hlmqtt",
+		Username: *flagBrokerUser,
+		Password: *flagBrokerPass,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	b.Conn = conn
+
+	// run until the broker connection is lost
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-conn.Done()
+		cancel()
+	}()
+	if err := b.Run(ctx); err != nil && conn.Err() != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", conn.Err())
+		os.Exit(1)
+	}
+}
@@ -197,13 +197,12 @@ func getProfile(w http.ResponseWriter, r *http.Request) {
 }
 
 func createNewProfileFromRequest(client *hilink.Client, newProfile ProfileRequest) (bool, error) {
-	return client.ProfileAdd(
-		newProfile.Name,
-		newProfile.ApnName,
-		newProfile.Username,
-		newProfile.Password,
-		newProfile.IsDefault,
-	)
+	return client.ProfileAdd(hilink.Profile{
+		Name:     newProfile.Name,
+		APN:      newProfile.ApnName,
+		Username: newProfile.Username,
+		Password: newProfile.Password,
+	}, newProfile.IsDefault)
 }
 
 func createProfile(w http.ResponseWriter, r *http.Request) {
@@ -412,7 +411,7 @@ func setConnectionInfo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	flag, err := client.ConnectionProfile(
-		connectionRequest.Roaming,
+		connectionRequest.Roaming == "1",
 		connectionRequest.MaxIdleTime,
 	)
 
@@ -427,7 +426,7 @@ func setConnectionInfo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	flag, err = client.MobileDataSwitchState(connectionRequest.DataSwitch)
+	flag, err = client.MobileDataSet(connectionRequest.DataSwitch == "1")
 
 	if !flag {
 		http.Error(w, "Call returned with failure", http.StatusInternalServerError)
@@ -481,7 +480,7 @@ func connect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	flag, err = client.MobileDataSwitchState("1")
+	flag, err = client.MobileDataSet(true)
 	if !flag {
 		http.Error(w, "Call returned with failure", http.StatusInternalServerError)
 		return
@@ -513,7 +512,7 @@ func disconnect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	flag, err = client.MobileDataSwitchState("0")
+	flag, err = client.MobileDataSet(false)
 	if !flag {
 		http.Error(w, "Call returned with failure", http.StatusInternalServerError)
 		return
@@ -534,7 +533,7 @@ func listSmsbox(w http.ResponseWriter, r *http.Request, boxType hilink.SmsBoxTyp
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	l, err := client.SmsList(uint(boxType), 1, 50, false, true, true)
+	l, err := client.SmsList(hilink.SmsListOptions{BoxType: boxType, Count: 50, Ascending: true, UnreadPreferred: true})
 	if err != nil {
 		resetHilinkClient()
 		http.Error(w, "Call return with failure", http.StatusInternalServerError)
@@ -619,7 +618,7 @@ func checkForSms() {
 		time.Sleep(SMS_CHECK_DELAY * time.Second)
 		client, err := getHilinkClient()
 		if err == nil {
-			l, err := client.SmsList(uint(hilink.SmsBoxTypeInbox), 1, 50, false, true, true)
+			l, err := client.SmsList(hilink.SmsListOptions{BoxType: hilink.SmsBoxTypeInbox, Count: 50, Ascending: true, UnreadPreferred: true})
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "error: %v\n", err)
 				continue
@@ -714,8 +713,8 @@ func handleSetApnSms(client *hilink.Client, message map[string]interface{}) {
 		return
 	}
 
-	client.ConnectionProfile("1", "3600")
-	client.MobileDataSwitchState("1")
+	client.ConnectionProfile(true, "3600")
+	client.MobileDataSet(true)
 	client.Connect()
 
 	if flag {
@@ -810,7 +809,7 @@ func handleSmsClearSms(client *hilink.Client, message map[string]interface{}) {
 }
 
 func clearSmsbox(client *hilink.Client, boxType hilink.SmsBoxType) (int, error) {
-	l, err := client.SmsList(uint(boxType), 1, 50, false, true, false)
+	l, err := client.SmsList(hilink.SmsListOptions{BoxType: boxType, Count: 50, Ascending: true})
 	if err != nil {
 		return 0, err
 	}
@@ -954,8 +953,8 @@ func checkInitializedAndConnected() {
 					continue
 				}
 				if changed {
-					client.ConnectionProfile("1", "3600")
-					client.MobileDataSwitchState("1")
+					client.ConnectionProfile(true, "3600")
+					client.MobileDataSet(true)
 					client.Connect()
 				}
 			}
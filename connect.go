@@ -0,0 +1,87 @@
+package hilink
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultConnectTimeout is the default timeout of ConnectAndWait and
+	// DisconnectAndWait, when the context has no deadline.
+	DefaultConnectTimeout = time.Minute
+
+	// connectPollInterval is the status poll interval of ConnectAndWait and
+	// DisconnectAndWait.
+	connectPollInterval = time.Second
+)
+
+// ConnectAndWait connects the device to the network provider (see Connect),
+// and waits until the connection is established, returning the final
+// connection status. When the context has no deadline, DefaultConnectTimeout
+// applies.
+//
+// ErrConnectionFailed is returned when the device reports the connection as
+// failed, and the context error when the connection is not established in
+// time.
+func (c *Client) ConnectAndWait(ctx context.Context) (ConnectionStatus, error) {
+	if err := checkOK(c.Connect()); err != nil {
+		return 0, err
+	}
+	return c.waitConnectionStatus(ctx, ConnectionStatusConnected)
+}
+
+// DisconnectAndWait disconnects the device from the network provider (see
+// Disconnect), and waits until the connection is torn down, returning the
+// final connection status. When the context has no deadline,
+// DefaultConnectTimeout applies.
+func (c *Client) DisconnectAndWait(ctx context.Context) (ConnectionStatus, error) {
+	if err := checkOK(c.Disconnect()); err != nil {
+		return 0, err
+	}
+	return c.waitConnectionStatus(ctx, ConnectionStatusDisconnected)
+}
+
+// waitConnectionStatus polls the connection status until it reaches want.
+//
+// When connecting, a failed status is only final once another status has
+// been reported, as the status of an earlier failed attempt is reported
+// until the device starts connecting.
+func (c *Client) waitConnectionStatus(ctx context.Context, want ConnectionStatus) (ConnectionStatus, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultConnectTimeout)
+		defer cancel()
+	}
+
+	t := time.NewTicker(connectPollInterval)
+	defer t.Stop()
+
+	var last ConnectionStatus
+	started := false
+	for {
+		s, err := c.Status()
+		if err != nil {
+			return last, err
+		}
+		last = s.ConnectionStatus
+
+		failed := last == ConnectionStatusFailed && want == ConnectionStatusConnected
+		switch {
+		case last == want:
+			return last, nil
+		case failed && started:
+			return last, ErrConnectionFailed
+		case !failed:
+			started = true
+		}
+
+		select {
+		case <-ctx.Done():
+			if failed {
+				return last, ErrConnectionFailed
+			}
+			return last, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
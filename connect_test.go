@@ -0,0 +1,49 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestConnectAndWait(t *testing.T) {
+	status := func(s ConnectionStatus) string {
+		return "<response><ConnectionStatus>" + strconv.Itoa(int(s)) + "</ConnectionStatus></response>"
+	}
+	tests := []struct {
+		name     string
+		statuses []ConnectionStatus
+		exp      ConnectionStatus
+		err      error
+	}{
+		{
+			name:     "stale failed",
+			statuses: []ConnectionStatus{ConnectionStatusFailed, ConnectionStatusConnecting, ConnectionStatusConnected},
+			exp:      ConnectionStatusConnected,
+		},
+		{
+			name:     "failed",
+			statuses: []ConnectionStatus{ConnectionStatusFailed, ConnectionStatusConnecting, ConnectionStatusFailed},
+			exp:      ConnectionStatusFailed,
+			err:      ErrConnectionFailed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := newFakeDevice()
+			defer d.Close()
+			for _, s := range test.statuses {
+				d.Sequences["/api/monitoring/status"] = append(d.Sequences["/api/monitoring/status"], status(s))
+			}
+
+			s, err := d.client(t).ConnectAndWait(context.Background())
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got: %v", test.err, err)
+			}
+			if s != test.exp {
+				t.Errorf("expected status %s, got: %s", test.exp, s)
+			}
+		})
+	}
+}
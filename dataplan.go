@@ -0,0 +1,180 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DataPlan is the monthly data plan configured on the device.
+type DataPlan struct {
+	// Enabled is the data plan enabled flag. Usage alerts are only shown by
+	// the WebUI when the plan is enabled.
+	Enabled bool
+
+	// ResetDay is the day of the month (1-31) on which the month statistics
+	// are reset.
+	ResetDay int
+
+	// Limit is the monthly data limit, in bytes, with megabyte precision.
+	Limit uint64
+
+	// Threshold is the usage warning threshold, in percent of the limit.
+	Threshold int
+}
+
+// DataPlan retrieves the monthly data plan.
+func (c *Client) DataPlan() (*DataPlan, error) {
+	var x startDateXML
+	if err := c.doReqXML("api/monitoring/start_date", nil, &x); err != nil {
+		return nil, err
+	}
+	return &DataPlan{
+		Enabled:   x.SetMonthData == "1",
+		ResetDay:  parseInt(x.StartDay),
+		Limit:     parseDataSize(x.DataLimit),
+		Threshold: parseInt(x.MonthThreshold),
+	}, nil
+}
+
+// DataPlanSet sets the monthly data plan.
+func (c *Client) DataPlanSet(p DataPlan) (bool, error) {
+	switch {
+	case p.ResetDay < 1 || p.ResetDay > 31:
+		return false, fmt.Errorf("%w: reset day must be 1 to 31", ErrInvalidValue)
+	case p.Threshold < 0 || p.Threshold > 100:
+		return false, fmt.Errorf("%w: threshold must be 0 to 100 percent", ErrInvalidValue)
+	}
+
+	return c.doReqCheckOK("api/monitoring/start_date", SimpleRequestXML(
+		"StartDay", strconv.Itoa(p.ResetDay),
+		"DataLimit", formatDataSize(p.Limit),
+		"MonthThreshold", strconv.Itoa(p.Threshold),
+		"SetMonthData", boolToString(p.Enabled),
+	))
+}
+
+// formatDataSize formats a data size as used by the WebUI (ie, "500MB",
+// "10GB"), with megabyte precision.
+func formatDataSize(n uint64) string {
+	if n >= 1<<30 && n%(1<<30) == 0 {
+		return strconv.FormatUint(n>>30, 10) + "GB"
+	}
+	return strconv.FormatUint(n>>20, 10) + "MB"
+}
+
+// DataUsage is a data usage threshold crossing.
+type DataUsage struct {
+	// Threshold is the crossed threshold, in percent of the limit.
+	Threshold int
+
+	// Usage is the month data usage (upload and download), in bytes.
+	Usage uint64
+
+	// Limit is the monthly data limit, in bytes.
+	Limit uint64
+}
+
+// Percent returns the usage, in percent of the limit.
+func (u DataUsage) Percent() float64 {
+	if u.Limit == 0 {
+		return 0
+	}
+	return float64(u.Usage) * 100 / float64(u.Limit)
+}
+
+// DataUsageWatcher polls the month data usage of a device, notifying when
+// the usage crosses percentages of the data plan limit, ie to alert on
+// metered backup links.
+type DataUsageWatcher struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Interval is the poll interval. Defaults to 5 minutes.
+	Interval time.Duration
+
+	// Thresholds are the notified usage thresholds, in percent of the limit.
+	// Defaults to 50, 80 and 100 percent.
+	Thresholds []int
+
+	// Limit is the monthly data limit, in bytes. Defaults to the data plan
+	// limit of the device.
+	Limit uint64
+
+	// OnThreshold is called when the usage crosses a threshold. Thresholds
+	// are notified again once the usage drops below them, ie after the
+	// month statistics are reset.
+	OnThreshold func(DataUsage)
+
+	// OnError is called when the usage cannot be retrieved.
+	OnError func(error)
+
+	crossed map[int]bool
+}
+
+// Run runs the watcher until the context is closed.
+func (w *DataUsageWatcher) Run(ctx context.Context) error {
+	if w.Client == nil {
+		return ErrNilClient
+	}
+
+	interval := w.Interval
+	if interval == 0 {
+		interval = 5 * time.Minute
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		usage, limit, err := w.Client.monthUsage()
+		switch {
+		case err != nil && w.OnError != nil:
+			w.OnError(err)
+		case err == nil:
+			if w.Limit != 0 {
+				limit = w.Limit
+			}
+			w.check(usage, limit)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// check notifies the thresholds crossed by the usage, lowest first.
+func (w *DataUsageWatcher) check(usage, limit uint64) {
+	if limit == 0 {
+		return
+	}
+	if w.crossed == nil {
+		w.crossed = make(map[int]bool)
+	}
+
+	thresholds := append([]int(nil), w.Thresholds...)
+	if len(thresholds) == 0 {
+		thresholds = []int{50, 80, 100}
+	}
+	sort.Ints(thresholds)
+
+	u := DataUsage{Usage: usage, Limit: limit}
+	for _, th := range thresholds {
+		above := u.Percent() >= float64(th)
+		switch {
+		case above && !w.crossed[th]:
+			w.crossed[th] = true
+			if w.OnThreshold != nil {
+				u.Threshold = th
+				w.OnThreshold(u)
+			}
+		case !above:
+			w.crossed[th] = false
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package hilink
+
+// DeviceInformation is the general device information.
+type DeviceInformation struct {
+	DeviceName      string `xml:"DeviceName"`
+	SerialNumber    string `xml:"SerialNumber"`
+	IMEI            string `xml:"Imei"`
+	IMSI            string `xml:"Imsi"`
+	ICCID           string `xml:"Iccid"`
+	MSISDN          string `xml:"Msisdn"`
+	HardwareVersion string `xml:"HardwareVersion"`
+	SoftwareVersion string `xml:"SoftwareVersion"`
+	WebUIVersion    string `xml:"WebUIVersion"`
+	MacAddress1     string `xml:"MacAddress1"`
+	MacAddress2     string `xml:"MacAddress2"`
+	ProductFamily   string `xml:"ProductFamily"`
+	Classify        string `xml:"Classify"`
+}
+
+// DeviceInformation retrieves the general device information.
+func (c *Client) DeviceInformation() (*DeviceInformation, error) {
+	var d DeviceInformation
+	if err := c.doReqXML("api/device/information", nil, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// SmsCounts are the SMS counts per box, in the device (local) and SIM
+// storage.
+type SmsCounts struct {
+	LocalUnread  int `xml:"LocalUnread"`
+	LocalInbox   int `xml:"LocalInbox"`
+	LocalOutbox  int `xml:"LocalOutbox"`
+	LocalDraft   int `xml:"LocalDraft"`
+	LocalDeleted int `xml:"LocalDeleted"`
+	LocalMax     int `xml:"LocalMax"`
+	SimUnread    int `xml:"SimUnread"`
+	SimInbox     int `xml:"SimInbox"`
+	SimOutbox    int `xml:"SimOutbox"`
+	SimDraft     int `xml:"SimDraft"`
+	SimUsed      int `xml:"SimUsed"`
+	SimMax       int `xml:"SimMax"`
+	NewMsg       int `xml:"NewMsg"`
+}
+
+// SmsCounts retrieves the SMS counts per box.
+func (c *Client) SmsCounts() (*SmsCounts, error) {
+	var s SmsCounts
+	if err := c.doReqXML("api/sms/sms-count", nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,167 @@
+package hilink
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// DhcpSettings are the LAN and DHCP server settings.
+type DhcpSettings struct {
+	// IPAddress is the LAN IPv4 address of the device.
+	IPAddress string
+
+	// Netmask is the LAN netmask (ie, 255.255.255.0).
+	Netmask string
+
+	// Enabled is the DHCP server enabled flag.
+	Enabled bool
+
+	// Start and End are the first and last addresses of the DHCP pool.
+	Start, End string
+
+	// LeaseTime is the DHCP lease time, with second precision.
+	LeaseTime time.Duration
+
+	// DNS are the DNS servers (at most 2) announced to DHCP clients,
+	// overriding the device as DNS server. Empty announces the device.
+	DNS []string
+}
+
+// dhcpSettingsXML is the raw DHCP settings.
+type dhcpSettingsXML struct {
+	IPAddress    string `xml:"DhcpIPAddress"`
+	Netmask      string `xml:"DhcpLanNetmask"`
+	Status       string `xml:"DhcpStatus"`
+	Start        string `xml:"DhcpStartIPAddress"`
+	End          string `xml:"DhcpEndIPAddress"`
+	LeaseTime    string `xml:"DhcpLeaseTime"`
+	DnsStatus    string `xml:"DnsStatus"`
+	PrimaryDns   string `xml:"PrimaryDns"`
+	SecondaryDns string `xml:"SecondaryDns"`
+}
+
+// DhcpSettings retrieves the LAN and DHCP server settings.
+func (c *Client) DhcpSettings() (*DhcpSettings, error) {
+	var x dhcpSettingsXML
+	if err := c.doReqXML("api/dhcp/settings", nil, &x); err != nil {
+		return nil, err
+	}
+
+	s := &DhcpSettings{
+		IPAddress: x.IPAddress,
+		Netmask:   x.Netmask,
+		Enabled:   x.Status == "1",
+		Start:     x.Start,
+		End:       x.End,
+		LeaseTime: time.Duration(parseUint(x.LeaseTime)) * time.Second,
+	}
+	if x.DnsStatus == "0" {
+		for _, dns := range []string{x.PrimaryDns, x.SecondaryDns} {
+			if dns != "" {
+				s.DNS = append(s.DNS, dns)
+			}
+		}
+	}
+	return s, nil
+}
+
+// DhcpConfigSet sets the LAN and DHCP server settings, retaining the
+// settings not represented by DhcpSettings. Changing the LAN address
+// restarts the device network, and the client must be recreated with the
+// new address.
+func (c *Client) DhcpConfigSet(s DhcpSettings) (bool, error) {
+	for _, addr := range append([]string{s.IPAddress, s.Netmask, s.Start, s.End}, s.DNS...) {
+		if err := ValidateIPv4Address(addr); err != nil {
+			return false, err
+		}
+	}
+	if len(s.DNS) > 2 {
+		return false, ErrResourceFull
+	}
+
+	d, err := c.DhcpConfig()
+	if err != nil {
+		return false, err
+	}
+	d["DhcpIPAddress"] = s.IPAddress
+	d["DhcpLanNetmask"] = s.Netmask
+	d["DhcpStatus"] = boolToString(s.Enabled)
+	d["DhcpStartIPAddress"] = s.Start
+	d["DhcpEndIPAddress"] = s.End
+	d["DhcpLeaseTime"] = strconv.FormatInt(int64(s.LeaseTime/time.Second), 10)
+	d["DnsStatus"] = boolToString(len(s.DNS) == 0)
+	if len(s.DNS) != 0 {
+		d["PrimaryDns"] = s.DNS[0]
+		d["SecondaryDns"] = ""
+		if len(s.DNS) > 1 {
+			d["SecondaryDns"] = s.DNS[1]
+		}
+	}
+
+	return c.doReqCheckOK("api/dhcp/settings", d)
+}
+
+// StaticLease is a DHCP static lease (MAC to IP address reservation).
+type StaticLease struct {
+	// MAC is the MAC address of the host.
+	MAC string
+
+	// IP is the reserved IPv4 address.
+	IP string
+
+	// Enabled is the lease enabled flag.
+	Enabled bool
+}
+
+// StaticLeases retrieves the DHCP static leases.
+func (c *Client) StaticLeases() ([]StaticLease, error) {
+	l := c.StaticLeaseResources().(*listResource)
+	rs, err := l.read()
+	if err != nil {
+		return nil, err
+	}
+
+	leases := make([]StaticLease, len(rs))
+	for i, r := range rs {
+		leases[i] = StaticLease{
+			MAC:     r["HostHw"],
+			IP:      r["HostIp"],
+			Enabled: r["HostEnabled"] == "1",
+		}
+	}
+	return leases, nil
+}
+
+// StaticLeaseAdd adds a DHCP static lease, or updates the lease of the MAC
+// address.
+func (c *Client) StaticLeaseAdd(l StaticLease) (bool, error) {
+	if err := ValidateMACAddress(l.MAC); err != nil {
+		return false, err
+	}
+	if err := ValidateIPv4Address(l.IP); err != nil {
+		return false, err
+	}
+
+	rc := c.StaticLeaseResources()
+	r := Resource{
+		"HostHw":      l.MAC,
+		"HostIp":      l.IP,
+		"HostEnabled": boolToString(l.Enabled),
+	}
+	_, err := rc.Read(l.MAC)
+	switch {
+	case err == nil:
+		err = rc.Update(l.MAC, r)
+	case errors.Is(err, ErrResourceNotFound):
+		_, err = rc.Create(r)
+	}
+	return err == nil, err
+}
+
+// StaticLeaseRemove removes the DHCP static lease of the MAC address.
+// Removing a missing lease succeeds without changes.
+func (c *Client) StaticLeaseRemove(mac string) (bool, error) {
+	err := c.StaticLeaseResources().Delete(mac)
+	return err == nil, err
+}
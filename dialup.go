@@ -0,0 +1,173 @@
+package hilink
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ConnectMode is the connect mode of the mobile data connection.
+type ConnectMode int
+
+// ConnectMode values.
+const (
+	ConnectModeAuto ConnectMode = iota
+	ConnectModeManual
+)
+
+// String satisfies the fmt.Stringer interface.
+func (m ConnectMode) String() string {
+	switch m {
+	case ConnectModeAuto:
+		return "auto"
+	case ConnectModeManual:
+		return "manual"
+	}
+	return fmt.Sprintf("ConnectMode(%d)", int(m))
+}
+
+// DialupConnection are the connection (dialup) settings.
+type DialupConnection struct {
+	// ConnectMode is the connect mode.
+	ConnectMode ConnectMode
+
+	// MTU is the MTU of the connection, from 576 to 1500.
+	MTU int
+
+	// MaxIdleTime is the idle time after which the connection is torn down.
+	// Zero never disconnects.
+	MaxIdleTime time.Duration
+
+	// RoamAutoConnect enables automatically connecting while roaming.
+	RoamAutoConnect bool
+}
+
+// dialupConnectionXML are the connection (dialup) settings fields, in the
+// order returned by the device, including unknown fields.
+type dialupConnectionXML struct {
+	Fields []xmlElement `xml:",any"`
+}
+
+// get returns the value of the field.
+func (x dialupConnectionXML) get(name string) string {
+	for _, f := range x.Fields {
+		if f.XMLName.Local == name {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// set sets the value of the field, adding it when missing.
+func (x *dialupConnectionXML) set(name, value string) {
+	for i, f := range x.Fields {
+		if f.XMLName.Local == name {
+			x.Fields[i].Value = value
+			return
+		}
+	}
+	x.Fields = append(x.Fields, xmlElement{Value: value})
+	x.Fields[len(x.Fields)-1].XMLName.Local = name
+}
+
+// pairs returns the request pairs of the fields.
+func (x dialupConnectionXML) pairs() []string {
+	pairs := make([]string, 0, 2*len(x.Fields))
+	for _, f := range x.Fields {
+		pairs = append(pairs, f.XMLName.Local, f.Value)
+	}
+	return pairs
+}
+
+// dialupConnection retrieves the connection (dialup) settings fields.
+func (c *Client) dialupConnection() (*dialupConnectionXML, error) {
+	var x dialupConnectionXML
+	if err := c.doReqXML("api/dialup/connection", nil, &x); err != nil {
+		return nil, err
+	}
+	return &x, nil
+}
+
+// dialupConnectionModify changes the connection (dialup) settings with fn,
+// retaining the fields unknown to fn.
+func (c *Client) dialupConnectionModify(fn func(*dialupConnectionXML)) (bool, error) {
+	x, err := c.dialupConnection()
+	if err != nil {
+		return false, err
+	}
+	fn(x)
+	return c.doReqCheckOK("api/dialup/connection", SimpleRequestXML(x.pairs()...))
+}
+
+// DialupConnectionGet retrieves the connection (dialup) settings.
+func (c *Client) DialupConnectionGet() (*DialupConnection, error) {
+	x, err := c.dialupConnection()
+	if err != nil {
+		return nil, err
+	}
+	return &DialupConnection{
+		ConnectMode:     ConnectMode(parseInt(x.get("ConnectMode"))),
+		MTU:             parseInt(x.get("MTU")),
+		MaxIdleTime:     time.Duration(parseInt(x.get("MaxIdelTime"))) * time.Second,
+		RoamAutoConnect: x.get("RoamAutoConnectEnable") == "1",
+	}, nil
+}
+
+// DialupConnectionSet sets the connection (dialup) settings, retaining the
+// other settings reported by the device.
+func (c *Client) DialupConnectionSet(d DialupConnection) (bool, error) {
+	if err := validMTU(d.MTU); err != nil {
+		return false, err
+	}
+	if d.MaxIdleTime < 0 {
+		return false, ErrInvalidValue
+	}
+
+	return c.dialupConnectionModify(func(x *dialupConnectionXML) {
+		x.set("ConnectMode", strconv.Itoa(int(d.ConnectMode)))
+		x.set("MTU", strconv.Itoa(d.MTU))
+		x.set("MaxIdelTime", strconv.FormatInt(int64(d.MaxIdleTime/time.Second), 10))
+		x.set("RoamAutoConnectEnable", boolToString(d.RoamAutoConnect))
+	})
+}
+
+// ConnectModeSet sets the connect mode, retaining the other connection
+// (dialup) settings.
+func (c *Client) ConnectModeSet(m ConnectMode) (bool, error) {
+	return c.connectionSet("ConnectMode", strconv.Itoa(int(m)))
+}
+
+// MTUSet sets the MTU of the connection, retaining the other connection
+// (dialup) settings.
+func (c *Client) MTUSet(mtu int) (bool, error) {
+	if err := validMTU(mtu); err != nil {
+		return false, err
+	}
+	return c.connectionSet("MTU", strconv.Itoa(mtu))
+}
+
+// MaxIdleTimeSet sets the idle time after which the connection is torn
+// down, retaining the other connection (dialup) settings. Zero never
+// disconnects.
+func (c *Client) MaxIdleTimeSet(d time.Duration) (bool, error) {
+	if d < 0 {
+		return false, ErrInvalidValue
+	}
+	return c.connectionSet("MaxIdelTime", strconv.FormatInt(int64(d/time.Second), 10))
+}
+
+// connectionSet changes a single connection (dialup) setting, retaining the
+// other settings.
+func (c *Client) connectionSet(name, value string) (bool, error) {
+	return c.dialupConnectionModify(func(x *dialupConnectionXML) {
+		x.set(name, value)
+	})
+}
+
+// validMTU validates an MTU.
+func validMTU(mtu int) error {
+	if mtu < 576 || mtu > 1500 {
+		return fmt.Errorf("%w: mtu must be 576 to 1500", ErrInvalidValue)
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+// Package exporter exposes the signal, status and traffic statistics of a
+// Hilink device as Prometheus metrics.
+//
+// Example:
+//
+//	client, _ := hilink.NewClient()
+//	e := &exporter.Exporter{Client: client}
+//	go e.Run(ctx)
+//	http.Handle("/metrics", e)
+//	log.Fatal(http.ListenAndServe(":9770", nil))
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jpunie/hilink"
+)
+
+// DefaultNamespace is the default metric name prefix.
+const DefaultNamespace = "hilink"
+
+// metricType is the Prometheus metric type.
+type metricType string
+
+// metricType values.
+const (
+	gauge   metricType = "gauge"
+	counter metricType = "counter"
+)
+
+// metric is a metric exported from a device endpoint value.
+type metric struct {
+	key  string
+	name string
+	typ  metricType
+	help string
+}
+
+// metrics are the exported metrics, keyed by endpoint.
+var metrics = map[string][]metric{
+	"api/device/signal": {
+		{"rsrp", "signal_rsrp_dbm", gauge, "LTE reference signal received power."},
+		{"rsrq", "signal_rsrq_db", gauge, "LTE reference signal received quality."},
+		{"rssi", "signal_rssi_dbm", gauge, "Received signal strength indicator."},
+		{"sinr", "signal_sinr_db", gauge, "LTE signal to interference plus noise ratio."},
+		{"rscp", "signal_rscp_dbm", gauge, "UMTS received signal code power."},
+		{"ecio", "signal_ecio_db", gauge, "UMTS energy per chip to interference ratio."},
+		{"band", "signal_band", gauge, "LTE band of the serving cell."},
+	},
+	"api/monitoring/status": {
+		{"ConnectionStatus", "connection_status", gauge, "Connection status code (901 is connected)."},
+		{"CurrentNetworkTypeEx", "network_type", gauge, "Extended network type code (101 is LTE)."},
+		{"SignalIcon", "signal_bars", gauge, "Signal strength bars."},
+		{"RoamingStatus", "roaming", gauge, "Roaming flag."},
+		{"CurrentWifiUser", "wifi_clients", gauge, "Connected WiFi clients."},
+		{"BatteryPercent", "battery_percent", gauge, "Battery charge, in percent."},
+	},
+	"api/monitoring/traffic-statistics": {
+		{"CurrentConnectTime", "connection_seconds", gauge, "Duration of the current connection."},
+		{"CurrentUpload", "connection_upload_bytes", gauge, "Data uploaded during the current connection."},
+		{"CurrentDownload", "connection_download_bytes", gauge, "Data downloaded during the current connection."},
+		{"CurrentUploadRate", "upload_rate_bytes", gauge, "Current upload rate, in bytes per second."},
+		{"CurrentDownloadRate", "download_rate_bytes", gauge, "Current download rate, in bytes per second."},
+		{"TotalConnectTime", "connection_seconds_total", counter, "Total connection time."},
+		{"TotalUpload", "upload_bytes_total", counter, "Total data uploaded."},
+		{"TotalDownload", "download_bytes_total", counter, "Total data downloaded."},
+	},
+	"api/monitoring/month_statistics": {
+		{"CurrentMonthUpload", "month_upload_bytes", gauge, "Data uploaded during the current month."},
+		{"CurrentMonthDownload", "month_download_bytes", gauge, "Data downloaded during the current month."},
+		{"MonthDuration", "month_connection_seconds", gauge, "Connection time during the current month."},
+	},
+}
+
+// Notification metrics, exported from hilink.Notifications.
+var (
+	smsUnreadMetric          = metric{name: "sms_unread", typ: gauge, help: "Unread SMS messages."}
+	smsStorageFullMetric     = metric{name: "sms_storage_full", typ: gauge, help: "SMS storage full flag."}
+	onlineUpdateStatusMetric = metric{name: "online_update_status", typ: gauge, help: "Online update status code (12 is a new version offered)."}
+)
+
+// Exporter exposes the metrics of a device over HTTP, in the Prometheus text
+// format. The metrics are scraped by Run, or on each request when Run is not
+// running.
+type Exporter struct {
+	// Client is the client of the device.
+	Client *hilink.Client
+
+	// Interval is the scrape interval. Defaults to 15 seconds.
+	Interval time.Duration
+
+	// Scheduler, when set, polls the endpoints every Interval along with
+	// the other consumers of the device (see hilink.Scheduler), and Run
+	// exports the last polled values. The scrape duration is then zero.
+	Scheduler *hilink.Scheduler
+
+	// Namespace is the metric name prefix. Defaults to DefaultNamespace.
+	Namespace string
+
+	// OnError is called when endpoints cannot be scraped.
+	OnError func(error)
+
+	last    []byte
+	running bool
+	polled  scrape
+	sync.Mutex
+}
+
+// scrape is the result of a scrape.
+type scrape struct {
+	data     map[string]hilink.FetchResult
+	n        *hilink.Notifications
+	nErr     error
+	duration time.Duration
+}
+
+// Run scrapes the device until the context is closed.
+func (e *Exporter) Run(ctx context.Context) error {
+	if e.Client == nil {
+		return hilink.ErrNilClient
+	}
+
+	interval := e.Interval
+	if interval == 0 {
+		interval = 15 * time.Second
+	}
+
+	e.Lock()
+	e.running = true
+	e.Unlock()
+	defer func() {
+		e.Lock()
+		e.running = false
+		e.Unlock()
+	}()
+
+	if e.Scheduler != nil {
+		return e.schedule(ctx, interval)
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		b := e.Scrape(ctx)
+		e.Lock()
+		e.last = b
+		e.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.Lock()
+	b := e.last
+	running := e.running
+	e.Unlock()
+
+	if !running || b == nil {
+		b = e.Scrape(r.Context())
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(b)
+}
+
+// schedule polls the endpoints through the scheduler until the context is
+// closed, rendering the metrics on each poll.
+func (e *Exporter) schedule(ctx context.Context, interval time.Duration) error {
+	e.Lock()
+	e.polled = scrape{data: make(map[string]hilink.FetchResult)}
+	e.Unlock()
+
+	for endpoint := range metrics {
+		endpoint := endpoint
+		defer e.Scheduler.Poll(endpoint, interval, func(d hilink.XMLData, err error) {
+			e.report(endpoint, err)
+
+			e.Lock()
+			defer e.Unlock()
+			e.polled.data[endpoint] = hilink.FetchResult{Data: d, Err: err}
+			e.last = e.render(e.polled)
+		})()
+	}
+	defer e.Scheduler.PollNotifications(interval, func(n *hilink.Notifications, err error) {
+		e.report("notifications", err)
+
+		e.Lock()
+		defer e.Unlock()
+		e.polled.n, e.polled.nErr = n, err
+		e.last = e.render(e.polled)
+	})()
+
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// Scrape scrapes the device, returning the metrics in the Prometheus text
+// format.
+func (e *Exporter) Scrape(ctx context.Context) []byte {
+	var r scrape
+	start := time.Now()
+	if e.Client != nil {
+		endpoints := make([]string, 0, len(metrics))
+		for endpoint := range metrics {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+
+		r.data = e.Client.FetchAll(ctx, endpoints...)
+		for _, endpoint := range endpoints {
+			e.report(endpoint, r.data[endpoint].Err)
+		}
+		r.n, r.nErr = e.Client.Notifications()
+		e.report("notifications", r.nErr)
+	}
+	r.duration = time.Since(start)
+
+	return e.render(r)
+}
+
+// report reports the scrape error of the endpoint, if any.
+func (e *Exporter) report(endpoint string, err error) {
+	if err != nil && e.OnError != nil {
+		e.OnError(fmt.Errorf("%s: %w", endpoint, err))
+	}
+}
+
+// render renders the metrics of the scrape in the Prometheus text format.
+// The device is up when all endpoints were scraped.
+func (e *Exporter) render(r scrape) []byte {
+	ns := e.Namespace
+	if ns == "" {
+		ns = DefaultNamespace
+	}
+
+	var up float64
+	var values []sample
+	if r.data != nil {
+		up = 1
+		for endpoint, ms := range metrics {
+			res, ok := r.data[endpoint]
+			if !ok || res.Err != nil {
+				up = 0
+				continue
+			}
+			for _, m := range ms {
+				s, _ := res.Data[m.key].(string)
+				if v, ok := parseValue(s); ok {
+					values = append(values, sample{m, v})
+				}
+			}
+		}
+
+		if r.n == nil {
+			up = 0
+		} else {
+			values = append(values, notificationSamples(r.n)...)
+		}
+	}
+	values = append(values,
+		sample{metric{name: "up", typ: gauge, help: "Whether all endpoints of the device were scraped."}, up},
+		sample{metric{name: "scrape_duration_seconds", typ: gauge, help: "Duration of the scrape."}, r.duration.Seconds()},
+	)
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].name < values[j].name
+	})
+
+	var buf bytes.Buffer
+	for _, s := range values {
+		name := ns + "_" + s.name
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, s.help)
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, s.typ)
+		fmt.Fprintf(&buf, "%s %s\n", name, strconv.FormatFloat(s.v, 'f', -1, 64))
+	}
+	return buf.Bytes()
+}
+
+// notificationSamples returns the samples of the notification metrics.
+func notificationSamples(n *hilink.Notifications) []sample {
+	full := 0.0
+	if n.SmsStorageFull {
+		full = 1
+	}
+	return []sample{
+		{smsUnreadMetric, float64(n.UnreadMessages)},
+		{smsStorageFullMetric, full},
+		{onlineUpdateStatusMetric, float64(n.OnlineUpdateStatus)},
+	}
+}
+
+// sample is a metric value.
+type sample struct {
+	metric
+	v float64
+}
+
+// parseValue parses a device value, stripping the units (ie, "-95dBm").
+func parseValue(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimLeft(s, "<>=")
+	s = strings.TrimRight(s, "dBmMHz ")
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
@@ -0,0 +1,77 @@
+package exporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpunie/hilink"
+)
+
+// newDevice starts a fake device server.
+func newDevice() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/webserver/SesTokInfo":
+			w.Write([]byte(`<response><SesInfo>a</SesInfo><TokInfo>b</TokInfo></response>`))
+		case "/api/monitoring/check-notifications":
+			w.Write([]byte(`<response><UnreadMessage>3</UnreadMessage><SmsStorageFull>1</SmsStorageFull><OnlineUpdateStatus>12</OnlineUpdateStatus></response>`))
+		default:
+			w.Write([]byte(`<response><Value>0</Value></response>`))
+		}
+	}))
+}
+
+func TestScrapeNotifications(t *testing.T) {
+	srv := newDevice()
+	defer srv.Close()
+
+	client, err := hilink.NewClient(hilink.URL(srv.URL + "/"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	e := &Exporter{Client: client}
+	out := string(e.Scrape(context.Background()))
+
+	for _, exp := range []string{
+		"hilink_sms_unread 3\n",
+		"hilink_sms_storage_full 1\n",
+		"hilink_online_update_status 12\n",
+		"hilink_up 1\n",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected %q in:\n%s", exp, out)
+		}
+	}
+}
+
+func TestRunScheduler(t *testing.T) {
+	srv := newDevice()
+	defer srv.Close()
+
+	client, err := hilink.NewClient(hilink.URL(srv.URL + "/"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	s := &hilink.Scheduler{Client: client, Tick: 10 * time.Millisecond}
+	e := &Exporter{Client: client, Scheduler: s}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.Run(ctx)
+	go s.Run(ctx)
+
+	// the metrics are rendered once all endpoints are polled
+	for start := time.Now(); time.Since(start) < time.Second; time.Sleep(10 * time.Millisecond) {
+		e.Lock()
+		out := string(e.last)
+		e.Unlock()
+		if strings.Contains(out, "hilink_up 1\n") && strings.Contains(out, "hilink_sms_unread 3\n") {
+			return
+		}
+	}
+	t.Fatal("expected polled metrics")
+}
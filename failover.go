@@ -0,0 +1,221 @@
+package hilink
+
+import (
+	"context"
+	"time"
+)
+
+// CradleStatus is the cradle (Ethernet WAN) status information.
+type CradleStatus struct {
+	CradleStatus   int              `xml:"cradlestatus"`
+	ConnectStatus  ConnectionStatus `xml:"connectstatus"`
+	ConnectionMode int              `xml:"connectionmode"`
+	IPAddress      string           `xml:"ipaddress"`
+}
+
+// CradleStatus retrieves the cradle (Ethernet WAN) status information.
+func (c *Client) CradleStatus() (*CradleStatus, error) {
+	var s CradleStatus
+	if err := c.doReqXML("api/cradle/status-info", nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// WanLink represents the WAN links of a B-series router.
+type WanLink int
+
+// WanLink values.
+const (
+	WanLinkNone WanLink = iota
+	WanLinkEthernet
+	WanLinkMobile
+)
+
+// String satisfies the fmt.Stringer interface.
+func (l WanLink) String() string {
+	switch l {
+	case WanLinkEthernet:
+		return "ethernet"
+	case WanLinkMobile:
+		return "mobile"
+	}
+	return "none"
+}
+
+// WanState is the WAN state observed by a Failover.
+type WanState struct {
+	EthernetUp bool
+	MobileUp   bool
+	Active     WanLink
+}
+
+// FailoverPolicy decides the WAN link that should be active for the observed
+// WAN state. Returning WanLinkNone (or the active link) leaves the active
+// link unchanged.
+type FailoverPolicy func(WanState) WanLink
+
+// PreferEthernet is a FailoverPolicy that uses the Ethernet WAN whenever it
+// is up, and the mobile connection otherwise.
+func PreferEthernet(s WanState) WanLink {
+	if s.EthernetUp {
+		return WanLinkEthernet
+	}
+	return WanLinkMobile
+}
+
+// FailoverEvent is the event emitted by a Failover when switching the active
+// WAN link, or when an error is encountered.
+type FailoverEvent struct {
+	Time  time.Time
+	From  WanLink
+	To    WanLink
+	State WanState
+	Err   error
+}
+
+// IsFallback determines if the event is a fallback to the Ethernet WAN (as
+// opposed to a failover to the mobile connection).
+func (e FailoverEvent) IsFallback() bool {
+	return e.To == WanLinkEthernet
+}
+
+// Failover watches the Ethernet WAN and mobile connection states of a
+// B-series router, and switches the active WAN link according to the
+// Policy.
+type Failover struct {
+	// Client is the client of the router.
+	Client *Client
+
+	// Policy decides the active link. Defaults to PreferEthernet.
+	Policy FailoverPolicy
+
+	// Switch switches the active link. Defaults to enabling the mobile data
+	// switch when switching to mobile, and disabling it when switching to
+	// Ethernet.
+	Switch func(ctx context.Context, to WanLink) error
+
+	// Interval is the poll interval. Defaults to 10 seconds.
+	Interval time.Duration
+
+	// Hold is the duration the Policy decision must hold before the link is
+	// switched, preventing flapping.
+	Hold time.Duration
+
+	// Notify is called for each emitted event.
+	Notify func(FailoverEvent)
+
+	active  WanLink
+	pending WanLink
+	since   time.Time
+}
+
+// Run runs the failover loop until the context is closed.
+func (f *Failover) Run(ctx context.Context) error {
+	if f.Client == nil {
+		return ErrNilClient
+	}
+
+	interval := f.Interval
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		f.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// check polls the WAN state and switches the active link when required.
+func (f *Failover) check(ctx context.Context) {
+	s, err := f.state()
+	if err != nil {
+		f.notify(FailoverEvent{Time: time.Now(), From: f.active, To: f.active, Err: err})
+		return
+	}
+
+	policy := f.Policy
+	if policy == nil {
+		policy = PreferEthernet
+	}
+
+	to := policy(s)
+	if to == WanLinkNone || to == f.active {
+		f.pending = WanLinkNone
+		return
+	}
+
+	// wait for decision to hold
+	now := time.Now()
+	if f.pending != to {
+		f.pending, f.since = to, now
+	}
+	if now.Sub(f.since) < f.Hold {
+		return
+	}
+
+	sw := f.Switch
+	if sw == nil {
+		sw = f.switchMobileData
+	}
+	ev := FailoverEvent{Time: now, From: f.active, To: to, State: s}
+	if ev.Err = sw(ctx, to); ev.Err == nil {
+		f.active, f.pending = to, WanLinkNone
+	}
+	f.notify(ev)
+}
+
+// state retrieves the current WAN state.
+func (f *Failover) state() (WanState, error) {
+	cs, err := f.Client.CradleStatus()
+	if err != nil {
+		return WanState{}, err
+	}
+	st, err := f.Client.Status()
+	if err != nil {
+		return WanState{}, err
+	}
+
+	s := WanState{
+		EthernetUp: cs.ConnectStatus == ConnectionStatusConnected,
+		MobileUp:   st.ConnectionStatus == ConnectionStatusConnected,
+	}
+
+	// determine initially active link
+	if f.active == WanLinkNone {
+		switch {
+		case s.EthernetUp:
+			f.active = WanLinkEthernet
+		case s.MobileUp:
+			f.active = WanLinkMobile
+		}
+	}
+	s.Active = f.active
+
+	return s, nil
+}
+
+// switchMobileData switches the active link by toggling the mobile data
+// switch.
+func (f *Failover) switchMobileData(ctx context.Context, to WanLink) error {
+	if to == WanLinkMobile {
+		return checkOK(f.Client.MobileDataActivate())
+	}
+	return checkOK(f.Client.MobileDataDeactivate())
+}
+
+// notify emits the event.
+func (f *Failover) notify(ev FailoverEvent) {
+	if f.Notify != nil {
+		f.Notify(ev)
+	}
+}
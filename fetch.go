@@ -0,0 +1,78 @@
+package hilink
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultFetchConcurrency is the default number of concurrent requests sent
+// by FetchAll.
+const DefaultFetchConcurrency = 4
+
+// FetchResult is the result of retrieving an endpoint with FetchAll.
+type FetchResult struct {
+	// Data is the retrieved data.
+	Data XMLData
+
+	// Err is the error encountered, if any.
+	Err error
+}
+
+// FetchAll concurrently retrieves the read-only endpoints (ie,
+// "api/monitoring/status", see Do), returning the results keyed by
+// endpoint. Endpoints not retrieved before the context is closed receive the
+// context error.
+func (c *Client) FetchAll(ctx context.Context, endpoints ...string) map[string]FetchResult {
+	results := make(map[string]FetchResult, len(endpoints))
+	c.fetch(ctx, endpoints, func(endpoint string, d XMLData, err error) {
+		results[endpoint] = FetchResult{d, err}
+	})
+	for _, endpoint := range endpoints {
+		if _, ok := results[endpoint]; !ok {
+			results[endpoint] = FetchResult{Err: ctx.Err()}
+		}
+	}
+	return results
+}
+
+// fetch concurrently retrieves the endpoints, calling fn (serially) with
+// each result, until the context is closed.
+func (c *Client) fetch(ctx context.Context, endpoints []string, fn func(string, XMLData, error)) {
+	type result struct {
+		endpoint string
+		d        XMLData
+		err      error
+	}
+
+	queue, results := make(chan string), make(chan result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < DefaultFetchConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for endpoint := range queue {
+				d, err := c.Do(endpoint, nil)
+				results <- result{endpoint, d, err}
+			}
+		}()
+	}
+	go func() {
+		defer close(queue)
+		for _, endpoint := range endpoints {
+			select {
+			case <-ctx.Done():
+				return
+			case queue <- endpoint:
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		fn(r.endpoint, r.d, r.err)
+	}
+}
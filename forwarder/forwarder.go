@@ -0,0 +1,395 @@
+// Package forwarder forwards the SMS messages received by a Hilink device to
+// a webhook (as a JSON POST) and/or by email, for example:
+//
+//	f := &forwarder.Forwarder{
+//		Client:  client,
+//		Webhook: "https://example.com/sms",
+//		SMTP: &forwarder.SMTP{
+//			Addr: "smtp.example.com:587",
+//			From: "modem@example.com",
+//			To:   []string{"me@example.com"},
+//		},
+//		Delete: true,
+//	}
+//	err := f.Run(ctx)
+//
+// Failed deliveries are retried, and messages are only deleted from the
+// device (or recorded as forwarded in the Store) once delivered to all
+// targets.
+package forwarder
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/jpunie/hilink"
+)
+
+// Default templates.
+const (
+	// DefaultSubject is the default email subject template.
+	DefaultSubject = "SMS from {{.Phone}}"
+
+	// DefaultBody is the default email body template.
+	DefaultBody = "From: {{.Phone}}\nDate: {{.Date.Format \"2006-01-02 15:04:05\"}}\n\n{{.Content}}\n"
+)
+
+// Error values.
+var (
+	// ErrNoTarget is the no delivery target error.
+	ErrNoTarget = errors.New("no webhook or smtp target")
+
+	// ErrBadStatus is the webhook bad status code error.
+	ErrBadStatus = errors.New("webhook bad status code")
+)
+
+// Message is a received SMS message, as forwarded. Webhooks receive it as
+// JSON, and it is the data of the templates.
+type Message struct {
+	Index   int       `json:"index"`
+	Phone   string    `json:"phone"`
+	Content string    `json:"content"`
+	Date    time.Time `json:"date"`
+}
+
+// SMTP is an SMTP delivery target.
+type SMTP struct {
+	// Addr is the address of the SMTP server (ie, "smtp.example.com:587").
+	// STARTTLS is used when supported by the server.
+	Addr string
+
+	// Username and Password are the PLAIN authentication credentials, if
+	// any.
+	Username string
+	Password string
+
+	// From is the sender address.
+	From string
+
+	// To are the recipient addresses.
+	To []string
+}
+
+// Forwarder forwards received SMS messages (see hilink.SmsWatcher).
+type Forwarder struct {
+	// Client is the client of the device.
+	Client *hilink.Client
+
+	// Webhook is the URL messages are POSTed to, as JSON (see Message).
+	Webhook string
+
+	// WebhookTemplate, when set, is the text/template of the webhook request
+	// body, instead of the JSON message.
+	WebhookTemplate string
+
+	// Header are additional webhook request headers (ie, Authorization).
+	Header http.Header
+
+	// HTTPClient is the webhook HTTP client. Defaults to a client with a 30
+	// second timeout.
+	HTTPClient *http.Client
+
+	// SMTP is the email delivery target.
+	SMTP *SMTP
+
+	// Subject and Body are the text/template email subject and body.
+	// Default to DefaultSubject and DefaultBody.
+	Subject string
+	Body    string
+
+	// Retries is the number of retries of failed deliveries. Defaults to 3.
+	// Negative values disable retries.
+	Retries int
+
+	// RetryDelay is the delay before the first retry, doubling with each
+	// retry. Defaults to 10 seconds.
+	RetryDelay time.Duration
+
+	// SmsInterval is the inbox poll interval (see hilink.SmsWatcher).
+	SmsInterval time.Duration
+
+	// Delete deletes forwarded messages from the device.
+	Delete bool
+
+	// Store, when set, persists the last forwarded message (see
+	// hilink.SmsWatcher).
+	Store hilink.Store
+
+	// OnForward is called when a message is delivered to all targets.
+	OnForward func(Message)
+
+	// OnError is called when the inbox cannot be polled, or a message cannot
+	// be delivered after the retries.
+	OnError func(error)
+
+	webhookTpl, subjectTpl, bodyTpl *template.Template
+	tplErr                          error
+	once                            sync.Once
+}
+
+// Run runs the forwarder until the context is closed.
+func (f *Forwarder) Run(ctx context.Context) error {
+	switch {
+	case f.Client == nil:
+		return hilink.ErrNilClient
+	case f.Webhook == "" && f.SMTP == nil:
+		return ErrNoTarget
+	}
+	if err := f.parseTemplates(); err != nil {
+		return err
+	}
+
+	w := &hilink.SmsWatcher{
+		Client:   f.Client,
+		Handle:   f.forward,
+		Interval: f.SmsInterval,
+		Store:    f.Store,
+		OnError:  f.onError,
+	}
+	return w.Run(ctx)
+}
+
+// Forward delivers the message to the targets, retrying failed deliveries.
+func (f *Forwarder) Forward(ctx context.Context, m Message) error {
+	if err := f.parseTemplates(); err != nil {
+		return err
+	}
+
+	retries := f.Retries
+	if retries == 0 {
+		retries = 3
+	}
+	delay := f.RetryDelay
+	if delay == 0 {
+		delay = 10 * time.Second
+	}
+
+	// targets, removed once delivered
+	targets := make(map[string]func(context.Context, Message) error)
+	if f.Webhook != "" {
+		targets["webhook"] = f.postWebhook
+	}
+	if f.SMTP != nil {
+		targets["smtp"] = f.sendMail
+	}
+	if len(targets) == 0 {
+		return ErrNoTarget
+	}
+
+	for i := 0; ; i++ {
+		var errs []string
+		for name, fn := range targets {
+			if err := fn(ctx, m); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+				continue
+			}
+			delete(targets, name)
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+		if i >= retries {
+			return fmt.Errorf("forward sms %d: %s", m.Index, strings.Join(errs, "; "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay << uint(i)):
+		}
+	}
+}
+
+// forward forwards a received message, deleting it once delivered. Messages
+// that cannot be delivered are forwarded again on the next poll.
+func (f *Forwarder) forward(ctx context.Context, sms hilink.SmsMessage) error {
+	m := Message{sms.Index, sms.Phone, sms.Content, sms.Date}
+	if err := f.Forward(ctx, m); err != nil {
+		return err
+	}
+	if f.Delete {
+		if _, err := f.Client.SmsDelete(strconv.Itoa(m.Index)); err != nil {
+			f.onError(err)
+		}
+	}
+	if f.OnForward != nil {
+		f.OnForward(m)
+	}
+	return nil
+}
+
+// parseTemplates parses the templates, once.
+func (f *Forwarder) parseTemplates() error {
+	f.once.Do(func() {
+		parse := func(name, text, def string) *template.Template {
+			if text == "" {
+				text = def
+			}
+			t, err := template.New(name).Parse(text)
+			if err != nil && f.tplErr == nil {
+				f.tplErr = err
+			}
+			return t
+		}
+
+		if f.WebhookTemplate != "" {
+			f.webhookTpl = parse("webhook", f.WebhookTemplate, "")
+		}
+		f.subjectTpl = parse("subject", f.Subject, DefaultSubject)
+		f.bodyTpl = parse("body", f.Body, DefaultBody)
+	})
+	return f.tplErr
+}
+
+// postWebhook posts the message to the webhook.
+func (f *Forwarder) postWebhook(ctx context.Context, m Message) error {
+	var body []byte
+	if f.webhookTpl != nil {
+		var buf bytes.Buffer
+		if err := f.webhookTpl.Execute(&buf, m); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	} else {
+		var err error
+		if body, err = json.Marshal(m); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest("POST", f.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range f.Header {
+		req.Header[k] = v
+	}
+
+	hc := f.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	res, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%w %d", ErrBadStatus, res.StatusCode)
+	}
+	return nil
+}
+
+// sendMail emails the message.
+func (f *Forwarder) sendMail(ctx context.Context, m Message) error {
+	var subject, body bytes.Buffer
+	if err := f.subjectTpl.Execute(&subject, m); err != nil {
+		return err
+	}
+	if err := f.bodyTpl.Execute(&body, m); err != nil {
+		return err
+	}
+
+	s := f.SMTP
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject.String()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.Replace(body.String(), "\n", "\r\n", -1))
+
+	return s.send(ctx, msg.Bytes())
+}
+
+// smtpTimeout is the timeout of SMTP deliveries.
+const smtpTimeout = 30 * time.Second
+
+// send sends the message, closing the connection when the context is closed
+// or the timeout expires.
+func (s *SMTP) send(ctx context.Context, msg []byte) error {
+	host := s.Addr
+	if i := strings.LastIndexByte(host, ':'); i != -1 {
+		host = host[:i]
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	for _, to := range s.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// onError calls the error callback.
+func (f *Forwarder) onError(err error) {
+	if f.OnError != nil {
+		f.OnError(err)
+	}
+}
@@ -0,0 +1,57 @@
+package hilink
+
+import (
+	"net"
+)
+
+// URLFromDefaultGateway returns the URL of the Hilink WebUI, assuming that the
+// Hilink device is the default gateway of the host.
+func URLFromDefaultGateway() (string, error) {
+	ip, err := defaultGateway()
+	if err != nil {
+		return "", err
+	}
+	return "http://" + ip.String() + "/", nil
+}
+
+// DefaultGatewayURL is an option that sets the URL on the Client to the
+// address of the host's default gateway (see URLFromDefaultGateway).
+func DefaultGatewayURL(c *Client) error {
+	rawurl, err := URLFromDefaultGateway()
+	if err != nil {
+		return err
+	}
+	return URL(rawurl)(c)
+}
+
+// guessGateway guesses the gateway as being the first host address on the
+// network of the first non-loopback interface that is up and has an IPv4
+// address, which is the case for the networks issued by Hilink devices (ie,
+// 192.168.8.0/24).
+func guessGateway() (net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			n, ok := a.(*net.IPNet)
+			if !ok || n.IP.To4() == nil {
+				continue
+			}
+			ip := n.IP.Mask(n.Mask).To4()
+			ip[3]++
+			return ip, nil
+		}
+	}
+
+	return nil, ErrNoDefaultGateway
+}
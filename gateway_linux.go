@@ -0,0 +1,60 @@
+//go:build linux
+// +build linux
+
+package hilink
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultGateway returns the gateway of the lowest metric default route in
+// the kernel routing table, falling back to guessGateway when the routing
+// table is not available.
+func defaultGateway() (net.IP, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return guessGateway()
+	}
+	defer f.Close()
+
+	var gw net.IP
+	metric := -1
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// Iface Destination Gateway Flags RefCnt Use Metric Mask ...
+		fields := strings.Fields(s.Text())
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		buf, err := hex.DecodeString(fields[2])
+		if err != nil || len(buf) != 4 {
+			continue
+		}
+		m, err := strconv.Atoi(fields[6])
+		if err != nil {
+			continue
+		}
+		if metric == -1 || m < metric {
+			// addresses are in host (little endian) byte order
+			ip := make(net.IP, 4)
+			binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(buf))
+			gw, metric = ip, m
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	if gw == nil {
+		return nil, ErrNoDefaultGateway
+	}
+
+	return gw, nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package hilink
+
+import (
+	"net"
+)
+
+// defaultGateway returns the default gateway of the host. As the routing table
+// is not readily available, the gateway is guessed from the host's interface
+// addresses.
+func defaultGateway() (net.IP, error) {
+	return guessGateway()
+}
@@ -2,11 +2,12 @@
 package hilink
 
 import (
-	"crypto/sha256"
+	"bytes"
+	"context"
 	"encoding/base64"
-	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/cookiejar"
@@ -38,13 +39,34 @@ const (
 type Client struct {
 	rawurl    string
 	url       *url.URL
+	host      string
 	authID    string
 	authPW    string
 	nostart   bool
+	norelogin bool
+	pinguard  bool
+	atcmd     bool
 	client    *http.Client
 	token     string
 	transport http.RoundTripper
 
+	jar           http.CookieJar
+	loc           *time.Location
+	transportOpts []func(*http.Transport)
+	quirks        *Quirks
+	autoquirks    bool
+	auth          AuthStrategy
+
+	runners map[*Runner]bool
+	runmu   sync.Mutex
+	session sessionFlight
+	tokens  *tokenPool
+	lazy    bool
+	init    lazyInit
+	audit   AuditSink
+	actor   string
+	rsa     rsaState
+
 	sync.Mutex
 }
 
@@ -55,7 +77,8 @@ func NewClient(opts ...Option) (*Client, error) {
 	// create client
 	c := &Client{
 		client: &http.Client{
-			Timeout: DefaultTimeout,
+			Transport: NewTransport(),
+			Timeout:   DefaultTimeout,
 		},
 	}
 
@@ -75,39 +98,96 @@ func NewClient(opts ...Option) (*Client, error) {
 		}
 	}
 
-	// start session
-	if !c.nostart {
-		// retrieve session id
-		sessID, tokID, err := c.NewSessionAndTokenID()
+	// configure transport
+	err = c.applyTransportOpts()
+	if err != nil {
+		return nil, err
+	}
+
+	// start session, unless deferred to first use
+	if !c.lazy {
+		err = c.start()
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		// set session id
-		err = c.SetSessionAndTokenID(sessID, tokID)
+	// prefetch tokens
+	if c.tokens != nil {
+		_, err = c.Go(context.Background(), WatcherFunc(c.prefetchTokens))
 		if err != nil {
 			return nil, err
 		}
+	}
+
+	return c, nil
+}
 
-		// try login, ignore the OK value
-		_, err = c.login()
+// start starts the session with the server and detects the device quirks,
+// as configured by the options.
+func (c *Client) start() error {
+	// start session, ignore the login OK value
+	if !c.nostart {
+		_, err := c.startSession()
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return c, nil
+	// detect quirks, logging in again with the detected login scheme
+	if c.autoquirks {
+		err := c.detectQuirks()
+		if err != nil {
+			return err
+		}
+		if c.quirks != nil && c.quirks.PasswordType != 0 && !c.nostart {
+			_, err = c.startSession()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// startSession starts a new session with the server, logging in when
+// credentials were provided.
+func (c *Client) startSession() (bool, error) {
+	// retrieve session id
+	sessID, tokID, err := c.NewSessionAndTokenID()
+	if err != nil {
+		return false, err
+	}
+
+	// set session id
+	err = c.SetSessionAndTokenID(sessID, tokID)
+	if err != nil {
+		return false, err
+	}
+
+	// try login
+	return c.login()
 }
 
 // createRequest creates a request for use with the Client.
 func (c *Client) createRequest(urlstr string, v interface{}) (*http.Request, error) {
 	if v == nil {
-		return http.NewRequest("GET", urlstr, nil)
+		req, err := http.NewRequest("GET", urlstr, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.setHost(req)
+		return req, nil
 	}
 
-	// encode xml
-	body, err := encodeXML(v)
-	if err != nil {
+	// encode xml, or multipart upload
+	contentType := "application/x-www-form-urlencoded; charset=UTF-8"
+	var body io.Reader
+	var err error
+	if m, ok := v.(*multipartFile); ok {
+		contentType, body = m.contentType, bytes.NewReader(m.data)
+	} else if body, err = encodeXML(v); err != nil {
 		return nil, err
 	}
 
@@ -116,59 +196,197 @@ func (c *Client) createRequest(urlstr string, v interface{}) (*http.Request, err
 	if err != nil {
 		return nil, err
 	}
+	c.setHost(req)
 
 	// set content type and CSRF token
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
-	req.Header.Set(TokenHeader, c.token)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(TokenHeader, c.nextToken())
 
 	return req, nil
 }
 
+// httpClient returns the http client for the endpoint path, with the
+// timeout extended for long running endpoints (see slowEndpoints).
+func (c *Client) httpClient(path string) *http.Client {
+	d, ok := slowEndpoints[path]
+	if !ok || c.client.Timeout == 0 || c.client.Timeout >= d {
+		return c.client
+	}
+	hc := *c.client
+	hc.Timeout = d
+	return &hc
+}
+
+// setHost overrides the Host header of the request, when set.
+func (c *Client) setHost(req *http.Request) {
+	if c.host != "" {
+		req.Host = c.host
+	}
+}
+
 // doReq sends a request to the server with the provided path. If data is nil,
 // then GET will be used as the HTTP method, otherwise POST will be used.
 func (c *Client) doReq(path string, v interface{}, takeFirstEl bool) (interface{}, error) {
-	c.Lock()
-	defer c.Unlock()
-
-	var err error
+	body, err := c.doReqBody(path, v)
+	if err != nil {
+		return nil, err
+	}
 
-	// create http request
-	q, err := c.createRequest(c.rawurl+path, v)
+	// decode
+	m, err := decodeXML(body, takeFirstEl)
 	if err != nil {
 		return nil, err
 	}
 
-	// do request
-	r, err := c.client.Do(q)
+	return m, nil
+}
+
+// doReqBody sends a request to the server with the provided path, returning
+// the raw response body.
+func (c *Client) doReqBody(path string, v interface{}) ([]byte, error) {
+	status, body, err := c.doReqStatus(path, v)
 	if err != nil {
 		return nil, err
 	}
-	defer r.Body.Close()
 
 	// check status code
-	if r.StatusCode != http.StatusOK {
+	if status != http.StatusOK {
 		return nil, ErrBadStatusCode
 	}
 
-	// retrieve and save csrf token header
-	tok := r.Header.Get(TokenHeader)
-	if tok != "" {
-		c.token = tok
+	return body, nil
+}
+
+// doReqStatus sends a request to the server with the provided path,
+// returning the HTTP status code and the raw response body. Requests failing
+// with an invalid session or token error are retried once with a new
+// session, unless disabled with NoAutoRelogin.
+func (c *Client) doReqStatus(path string, v interface{}) (int, []byte, error) {
+	gen := c.sessionGen()
+	status, body, err := c.doReqStatusOnce(path, v)
+	if err != nil || c.norelogin || status != http.StatusOK || !sessionExpired(body) {
+		return status, body, err
 	}
 
-	// read body
-	body, err := ioutil.ReadAll(r.Body)
+	// refresh session, shared with concurrent requests, and retry
+	if _, err := c.refreshSession(gen); err != nil {
+		return 0, nil, err
+	}
+	return c.doReqStatusOnce(path, v)
+}
+
+// doReqStatusOnce sends a request to the server with the provided path,
+// returning the HTTP status code and the raw response body.
+func (c *Client) doReqStatusOnce(path string, v interface{}) (int, []byte, error) {
+	var status int
+	var body []byte
+	err := c.doReqStream(path, v, func(code int, r io.Reader) error {
+		var err error
+		status = code
+		body, err = ioutil.ReadAll(r)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
-	// decode
-	m, err := decodeXML(body, takeFirstEl)
+	return status, body, nil
+}
+
+// doReqStream sends a request to the server with the provided path, passing
+// the HTTP status code and the response body to fn as the response is
+// received.
+func (c *Client) doReqStream(path string, v interface{}, fn func(int, io.Reader) error) error {
+	// start lazy session
+	if err := c.ensureSession(); err != nil {
+		return err
+	}
+
+	// retrieve the public key for encrypted endpoints
+	var key *rsaKey
+	if v != nil && c.encrypted(path) {
+		var err error
+		if key, err = c.publicKey(); err != nil {
+			return err
+		}
+	}
+
+	c.Lock()
+	locked := true
+	defer func() {
+		if locked {
+			c.Unlock()
+		}
+	}()
+
+	var err error
+	hc := c.httpClient(path)
+	audited := c.audited(path, v)
+
+	// apply quirks
+	if c.quirks != nil {
+		if c.quirks.broken(path) {
+			return fmt.Errorf("%w %q", ErrBrokenEndpoint, path)
+		}
+		path = c.quirks.path(path)
+	}
+
+	// create http request
+	q, err := c.createRequest(c.rawurl+path, v)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if key != nil {
+		if err := key.encrypt(q); err != nil {
+			return err
+		}
 	}
 
-	return m, nil
+	// GET requests do not use the csrf token, and are sent concurrently
+	if v == nil {
+		c.Unlock()
+		locked = false
+	}
+
+	// do request
+	r, err := hc.Do(q)
+	if err != nil {
+		if audited {
+			c.auditRequest(path, v, 0, nil, err)
+		}
+		return err
+	}
+	defer r.Body.Close()
+
+	// record mutating requests, with the response
+	body := io.Reader(r.Body)
+	if audited {
+		var buf bytes.Buffer
+		body = io.TeeReader(r.Body, &buf)
+		defer func() {
+			c.auditRequest(path, v, r.StatusCode, buf.Bytes(), nil)
+		}()
+	}
+
+	// retrieve and save csrf token header
+	if r.StatusCode == http.StatusOK && (c.quirks == nil || !c.quirks.StaticToken) {
+		tok := r.Header.Get(TokenHeader)
+		if tok != "" {
+			if !locked {
+				c.Lock()
+				locked = true
+			}
+			c.token = tok
+		}
+	}
+
+	// release for streamed GET bodies
+	if v == nil && locked {
+		c.Unlock()
+		locked = false
+	}
+
+	return fn(r.StatusCode, body)
 }
 
 // doReqString wraps a request operation, returning the data of the specified
@@ -229,6 +447,17 @@ func (c *Client) doReqCheckOK(path string, v interface{}) (bool, error) {
 	return s == "OK", nil
 }
 
+// doReqXML wraps a request operation, decoding the XML response into dst
+// using encoding/xml.
+func (c *Client) doReqXML(path string, v interface{}, dst interface{}) error {
+	body, err := c.doReqBody(path, v)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalXML(body, dst)
+}
+
 // login authentifies the user using the user identifier and password given
 // with the Auth option. Return nil if succeeded, or no Auth option
 // was given, or the identifier is an empty string.
@@ -236,21 +465,39 @@ func (c *Client) login() (bool, error) {
 	if c.authID == "" {
 		return false, nil
 	}
-	// encode hashed password
-	h := sha256.Sum256([]byte(c.authPW + c.token))
-	tokenizedPW := base64.RawStdEncoding.EncodeToString([]byte(hex.EncodeToString(h[:])))
-	return c.doReqCheckOK("api/user/login", XMLData{
-		"Username":      c.authID,
-		"Password":      tokenizedPW,
-		"password_type": 4,
-	})
+	return c.authStrategy().Login(c, c.authID, c.authPW)
+}
+
+// PasswordChange changes the password of the logged in user. On success, the
+// new password is used for subsequent logins.
+func (c *Client) PasswordChange(cur, new string) (bool, error) {
+	ok, err := c.doReqCheckOK("api/user/password", SimpleRequestXML(
+		"Username", c.authID,
+		"CurrentPassword", base64.StdEncoding.EncodeToString([]byte(cur)),
+		"NewPassword", base64.StdEncoding.EncodeToString([]byte(new)),
+		"encryption_enable", "1",
+	))
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	c.authPW = new
+	return true, nil
 }
 
 // Do sends a request to the server with the provided path. If data is nil,
 // then GET will be used as the HTTP method, otherwise POST will be used.
 func (c *Client) Do(path string, v interface{}) (XMLData, error) {
-	// send request
-	res, err := c.doReq(path, v, true)
+	return decodeData(c.doReqBody(path, v))
+}
+
+// decodeData decodes the root element of a response body.
+func decodeData(body []byte, err error) (XMLData, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := decodeXML(body, true)
 	if err != nil {
 		return nil, err
 	}
@@ -309,9 +556,13 @@ func (c *Client) SetSessionAndTokenID(sessionID, tokenID string) error {
 	var err error
 
 	// create cookie jar
-	c.client.Jar, err = cookiejar.New(nil)
-	if err != nil {
-		return err
+	if c.jar != nil {
+		c.client.Jar = c.jar
+	} else {
+		c.client.Jar, err = cookiejar.New(nil)
+		if err != nil {
+			return err
+		}
 	}
 
 	// set values on client
@@ -320,10 +571,30 @@ func (c *Client) SetSessionAndTokenID(sessionID, tokenID string) error {
 		Value: sessionID,
 	}})
 	c.token = tokenID
+	c.flushTokens()
 
 	return nil
 }
 
+// SessionAndTokenID returns the current sessionID and tokenID for the Client,
+// allowing the session to be exported and later restored with
+// SetSessionAndTokenID.
+func (c *Client) SessionAndTokenID() (string, string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.client.Jar == nil {
+		return "", "", ErrNoSession
+	}
+	for _, cookie := range c.client.Jar.Cookies(c.url) {
+		if cookie.Name == "SessionID" {
+			return cookie.Value, c.token, nil
+		}
+	}
+
+	return "", "", ErrNoSession
+}
+
 // GlobalConfig retrieves global Hilink configuration.
 func (c *Client) GlobalConfig() (XMLData, error) {
 	return c.Do("config/global/config.xml", nil)
@@ -371,6 +642,9 @@ func (c *Client) CradleStatusInfo() (XMLData, error) {
 
 // CradleMACSet sets the MAC address for the cradle.
 func (c *Client) CradleMACSet(addr string) (bool, error) {
+	if err := ValidateMACAddress(addr); err != nil {
+		return false, err
+	}
 	return c.doReqCheckOK("api/cradle/current-mac", XMLData{
 		"currentmac": addr,
 	})
@@ -416,18 +690,56 @@ func (c *Client) DeviceReset() (bool, error) {
 // DeviceBackup backups device configuration and retrieves backed up
 // configuration data as a base64 encoded string.
 func (c *Client) DeviceBackup() (string, error) {
+	data, err := c.DeviceBackupData()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DeviceBackupData backups device configuration and retrieves the backed up
+// configuration file (nvram.bak), for use with DeviceRestore.
+func (c *Client) DeviceBackupData() ([]byte, error) {
 	// cause backup to be generated
 	ok, err := c.DeviceControl(3)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if !ok {
-		return "", errors.New("unable to backup device configuration")
+		return nil, errors.New("unable to backup device configuration")
 	}
 
 	// retrieve data
-	//res, err := c.doReq("nvram.bak")
-	return " -- not implemented -- ", nil
+	data, err := c.doReqBody("nvram.bak", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the data is binary, unless an error is returned
+	if b := bytes.TrimSpace(data); bytes.HasPrefix(b, []byte("<?xml")) || bytes.HasPrefix(b, []byte("<error>")) {
+		var apiErr *APIError
+		if _, err := decodeXML(b, false); errors.As(err, &apiErr) {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// DeviceRestore restores the device configuration from a configuration
+// file retrieved with DeviceBackupData, uploading it as the WebUI does. The
+// device restarts once the configuration is restored.
+func (c *Client) DeviceRestore(data []byte) (bool, error) {
+	body, err := c.doReqBody("restore.cgi", newMultipartFile("uploadfile", "nvram.bak", data))
+	if err != nil {
+		return false, err
+	}
+
+	// the response is an html page, unless an error is returned
+	if bytes.Contains(body, []byte("<error>")) {
+		_, err := decodeXML(body, false)
+		return false, err
+	}
+	return true, nil
 }
 
 // DeviceShutdown shuts down the device.
@@ -440,7 +752,8 @@ func (c *Client) DeviceFeatures() (XMLData, error) {
 	return c.Do("api/device/device-feature-switch", nil)
 }
 
-// DeviceInfo retrieves general device information.
+// DeviceInfo retrieves general device information (see DeviceInformation
+// for the typed information).
 func (c *Client) DeviceInfo() (XMLData, error) {
 	return c.Do("api/device/information", nil)
 }
@@ -477,20 +790,39 @@ func (c *Client) ConnectionInfo() (XMLData, error) {
 	return c.Do("api/dialup/connection", nil)
 }
 
-// doReqConn wraps a connection manipulation request.
-func (c *Client) ConnectionProfile(roaming, maxIdleTime string,
+// ConnectionProfile sets the connection (dialup) information for roaming and
+// max idle time (in seconds), retaining the other settings.
+//
+// Deprecated: use DialupConnectionSet, RoamingSet or MaxIdleTimeSet.
+func (c *Client) ConnectionProfile(roaming bool, maxIdleTime string) (bool, error) {
+	return c.dialupConnectionModify(func(x *dialupConnectionXML) {
+		x.set("MaxIdelTime", maxIdleTime)
+		x.set("RoamAutoConnectEnable", boolToString(roaming))
+	})
+}
 
-// connectMode, autoReconnect, roamAutoConnect, roamAutoReconnect string,
-// interval, idle int,
-) (bool, error) {
-	return c.doReqCheckOK("api/dialup/connection", SimpleRequestXML(
-		"ConnectMode", "0",
-		"MTU", "1500",
-		"MaxIdelTime", maxIdleTime,
-		"RoamAutoConnectEnable", roaming,
-		"auto_dial_switch", "1",
-		"pdp_always_on", "0",
-	))
+// Roaming determines if automatically connecting while roaming is enabled.
+func (c *Client) Roaming() (bool, error) {
+	s, err := c.doReqString("api/dialup/connection", nil, "RoamAutoConnectEnable")
+	if err != nil {
+		return false, err
+	}
+	return s == "1", nil
+}
+
+// RoamingSet enables or disables automatically connecting while roaming.
+func (c *Client) RoamingSet(enabled bool) (bool, error) {
+	return c.connectionSet("RoamAutoConnectEnable", boolToString(enabled))
+}
+
+// RoamingEnable enables automatically connecting while roaming.
+func (c *Client) RoamingEnable() (bool, error) {
+	return c.RoamingSet(true)
+}
+
+// RoamingDisable disables automatically connecting while roaming.
+func (c *Client) RoamingDisable() (bool, error) {
+	return c.RoamingSet(false)
 }
 
 // GlobalFeatures retrieves global feature information.
@@ -581,13 +913,60 @@ func (c *Client) ModeSet(netMode, netBand, lteBand string) (bool, error) {
 	))
 }
 
+// ModeSetNR sets the network mode, including the 5G NR band mask, on 5G
+// devices (ie, B818, H112, H122).
+func (c *Client) ModeSetNR(netMode, netBand, lteBand, nrBand string) (bool, error) {
+	return c.doReqCheckOK("api/net/net-mode", SimpleRequestXML(
+		"NetworkMode", netMode,
+		"NetworkBand", netBand,
+		"LTEBand", lteBand,
+		"NRBand", nrBand,
+	))
+}
+
+// NRModeInfo retrieves the 5G NR mode (SA/NSA) settings information.
+func (c *Client) NRModeInfo() (XMLData, error) {
+	return c.Do("api/net/nr-mode", nil)
+}
+
+// NRModeSet sets the 5G NR mode (SA/NSA).
+func (c *Client) NRModeSet(mode NRMode) (bool, error) {
+	return c.doReqCheckOK("api/net/nr-mode", SimpleRequestXML(
+		"nrmode", fmt.Sprintf("%d", mode),
+	))
+}
+
 // PinInfo retrieves SIM PIN status information.
 func (c *Client) PinInfo() (XMLData, error) {
 	return c.Do("api/pin/status", nil)
 }
 
-// doReqPin wraps a SIM PIN manipulation request.
-func (c *Client) doReqPin(pt PinType, cur, new, puk string) (bool, error) {
+// doReqPin wraps a SIM PIN manipulation request. When the PinGuard option
+// is set and force is false, the request is refused if it would use up the
+// last remaining attempt.
+func (c *Client) doReqPin(pt PinType, cur, new, puk string, force bool) (bool, error) {
+	// validate locally, as the device only reports a generic error
+	if err := ValidatePin(cur); err != nil {
+		return false, err
+	}
+	if pt == PinTypeChange || pt == PinTypeEnterPuk {
+		if err := ValidatePin(new); err != nil {
+			return false, err
+		}
+	}
+	if pt == PinTypeEnterPuk {
+		if err := ValidatePuk(puk); err != nil {
+			return false, err
+		}
+	}
+
+	// check remaining attempts
+	if c.pinguard && !force {
+		if err := c.checkPinAttempts(pt); err != nil {
+			return false, err
+		}
+	}
+
 	return c.doReqCheckOK("api/pin/operate", SimpleRequestXML(
 		"OperateType", fmt.Sprintf("%d", pt),
 		"CurrentPin", cur,
@@ -598,27 +977,32 @@ func (c *Client) doReqPin(pt PinType, cur, new, puk string) (bool, error) {
 
 // PinEnter enters a SIM PIN.
 func (c *Client) PinEnter(pin string) (bool, error) {
-	return c.doReqPin(PinTypeEnter, pin, "", "")
+	return c.doReqPin(PinTypeEnter, pin, "", "", false)
+}
+
+// PinEnterForce enters a SIM PIN, bypassing the PinGuard option.
+func (c *Client) PinEnterForce(pin string) (bool, error) {
+	return c.doReqPin(PinTypeEnter, pin, "", "", true)
 }
 
 // PinActivate activates a SIM PIN.
 func (c *Client) PinActivate(pin string) (bool, error) {
-	return c.doReqPin(PinTypeActivate, pin, "", "")
+	return c.doReqPin(PinTypeActivate, pin, "", "", false)
 }
 
 // PinDeactivate deactivates a SIM PIN.
 func (c *Client) PinDeactivate(pin string) (bool, error) {
-	return c.doReqPin(PinTypeDeactivate, pin, "", "")
+	return c.doReqPin(PinTypeDeactivate, pin, "", "", false)
 }
 
 // PinChange changes a SIM PIN.
 func (c *Client) PinChange(pin, new string) (bool, error) {
-	return c.doReqPin(PinTypeChange, pin, new, "")
+	return c.doReqPin(PinTypeChange, pin, new, "", false)
 }
 
 // PinEnterPuk enters a SIM PIN puk.
 func (c *Client) PinEnterPuk(puk, new string) (bool, error) {
-	return c.doReqPin(PinTypeEnterPuk, new, new, puk)
+	return c.doReqPin(PinTypeEnterPuk, new, new, puk, false)
 }
 
 // PinSaveInfo retrieves SIM PIN save information.
@@ -631,26 +1015,59 @@ func (c *Client) PinSimlockInfo() (XMLData, error) {
 	return c.Do("api/pin/simlock", nil)
 }
 
+// MobileDataSwitch retrieves mobile data switch information.
 func (c *Client) MobileDataSwitch() (XMLData, error) {
 	return c.Do("api/dialup/mobile-dataswitch", nil)
 }
 
-func (c *Client) MobileDataSwitchState(state string) (bool, error) {
+// MobileDataEnabled determines if the mobile data switch is enabled.
+func (c *Client) MobileDataEnabled() (bool, error) {
+	s, err := c.doReqString("api/dialup/mobile-dataswitch", nil, "dataswitch")
+	if err != nil {
+		return false, err
+	}
+	return s == "1", nil
+}
+
+// MobileDataSet enables or disables the mobile data switch.
+func (c *Client) MobileDataSet(enabled bool) (bool, error) {
 	return c.doReqCheckOK("api/dialup/mobile-dataswitch", XMLData{
-		"dataswitch": state,
+		"dataswitch": boolToString(enabled),
 	})
 }
 
+// MobileDataSwitchState sets the mobile data switch state ("1" enabled, "0"
+// disabled).
+func (c *Client) MobileDataSwitchState(state string) (bool, error) {
+	return c.MobileDataSet(state == "1")
+}
+
+// MobileDataActivate enables the mobile data switch.
 func (c *Client) MobileDataActivate() (bool, error) {
-	return c.doReqCheckOK("api/dialup/mobile-dataswitch", XMLData{
-		"dataswitch": "1",
-	})
+	return c.MobileDataSet(true)
 }
 
+// MobileDataDeactivate disables the mobile data switch.
 func (c *Client) MobileDataDeactivate() (bool, error) {
-	return c.doReqCheckOK("api/dialup/mobile-dataswitch", XMLData{
-		"dataswitch": "0",
-	})
+	return c.MobileDataSet(false)
+}
+
+// DialupFeatures retrieves dialup feature information.
+func (c *Client) DialupFeatures() (XMLData, error) {
+	return c.Do("api/dialup/dialup-feature-switch", nil)
+}
+
+// DialupControlAllowed determines if dialup control (ie, Connect and
+// Disconnect) is available on the device. Operator customized firmwares may
+// disable manual dialup control, in which case the device manages the
+// connection itself.
+func (c *Client) DialupControlAllowed() (bool, error) {
+	f, err := c.DialupFeatures()
+	if err != nil {
+		return false, err
+	}
+	s, ok := f["dialup_enable"].(string)
+	return !ok || s != "0", nil
 }
 
 // Connect connects the Hilink device to the network provider.
@@ -678,37 +1095,57 @@ func (c *Client) ProfileInfo() (XMLData, error) {
 	return c.Do("api/dialup/profiles", nil)
 }
 
-// Add connection profile
-func (c *Client) ProfileAdd(name string, apn string, user string, password string, isDefault bool) (bool, error) {
-	var newDefaultValue string
-	if isDefault {
-		newDefaultValue = "0"
-	} else {
-		newDefaultValue = "1"
-	}
-	return c.doReqCheckOK("api/dialup/profiles", XMLData{
-		"Delete":     0,
-		"SetDefault": newDefaultValue,
-		"Modify":     1,
-		"Profile": XMLData{
-			"Index":        "", //original is new_index
-			"IsValid":      1,
-			"Name":         name,
-			"ApnIsStatic":  "1",
-			"ApnName":      apn,
-			"DialupNum":    "*99#",
-			"Username":     user,
-			"Password":     password,
-			"AuthMode":     "0",
-			"IpIsStatic":   "",
-			"IpAddress":    "",
-			"DnsIsStatic":  "",
-			"PrimaryDns":   "",
-			"SecondaryDns": "",
-			"ReadOnly":     "0",
-			"iptype":       "0",
-		},
-	})
+// Profiles retrieves the connection profiles, and the index of the default
+// profile.
+func (c *Client) Profiles() ([]Profile, int, error) {
+	var res struct {
+		CurrentProfile string    `xml:"CurrentProfile"`
+		Profiles       []Profile `xml:"Profiles>Profile"`
+	}
+	if err := c.doReqXML("api/dialup/profiles", nil, &res); err != nil {
+		return nil, 0, err
+	}
+	return res.Profiles, parseInt(res.CurrentProfile), nil
+}
+
+// ProfileAdd adds a connection profile. When setDefault is true, the added
+// profile becomes the default profile, otherwise the default profile is left
+// unchanged.
+func (c *Client) ProfileAdd(p Profile, setDefault bool) (bool, error) {
+	if err := p.Validate(); err != nil {
+		return false, err
+	}
+
+	// index is assigned by the device
+	p.Index = 0
+
+	return c.doReqCheckOK("api/dialup/profiles", profileReq("1", boolToString(setDefault), p.xml()))
+}
+
+// ProfileModify replaces the connection profile with the index, leaving the
+// default profile unchanged.
+func (c *Client) ProfileModify(index int, p Profile) (bool, error) {
+	if err := p.Validate(); err != nil {
+		return false, err
+	}
+
+	_, def, err := c.Profiles()
+	if err != nil {
+		return false, err
+	}
+
+	p.Index = index
+	return c.doReqCheckOK("api/dialup/profiles", profileReq("2", strconv.Itoa(def), p.xml()))
+}
+
+// ProfileSetDefault makes the connection profile with the index the default
+// profile.
+func (c *Client) ProfileSetDefault(index int) (bool, error) {
+	return c.doReqCheckOK("api/dialup/profiles", SimpleRequestXML(
+		"Delete", "0",
+		"SetDefault", strconv.Itoa(index),
+		"Modify", "0",
+	))
 }
 
 // Delete connection profile
@@ -725,45 +1162,102 @@ func (c *Client) SmsFeatures() (XMLData, error) {
 	return c.Do("api/sms/sms-feature-switch", nil)
 }
 
-// SmsList retrieves list of SMS in an inbox.
-func (c *Client) SmsList(boxType, page, count uint, sortByName, ascending, unreadPreferred bool) (XMLData, error) {
-	// execute request -- note: the order is important!
-	return c.Do("api/sms/sms-list", SimpleRequestXML(
-		"PageIndex", fmt.Sprintf("%d", page),
-		"ReadCount", fmt.Sprintf("%d", count),
-		"BoxType", fmt.Sprintf("%d", boxType),
-		"SortType", boolToString(sortByName),
-		"Ascending", boolToString(ascending),
-		"UnreadPreferred", boolToString(unreadPreferred),
-	))
+// SmsList retrieves list of SMS in an inbox (see SmsListAll and SmsIter for
+// paging through all messages).
+func (c *Client) SmsList(opts SmsListOptions) (XMLData, error) {
+	return c.Do("api/sms/sms-list", opts.request())
+}
+
+// SmsListMarkRead retrieves list of SMS in an inbox (see SmsList), and marks
+// the returned unread messages as read, as the WebUI does when displaying
+// them.
+func (c *Client) SmsListMarkRead(opts SmsListOptions) (XMLData, error) {
+	l, err := c.SmsList(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if m, ok := l["Messages"].(map[string]interface{}); ok {
+		var ids []string
+		var unread []map[string]interface{}
+		for _, msg := range xmlList(m["Message"]) {
+			// smstat 0 is unread
+			if msg["Smstat"] != "0" {
+				continue
+			}
+			id, _ := msg["Index"].(string)
+			ids, unread = append(ids, id), append(unread, msg)
+		}
+		if err := checkOK(c.SmsReadSet(ids...)); err != nil {
+			return nil, err
+		}
+		for _, msg := range unread {
+			msg["Smstat"] = "1"
+		}
+	}
+
+	return l, nil
 }
 
-// SmsCount retrieves count of SMS per inbox type.
+// SmsCount retrieves count of SMS per inbox type (see SmsCounts for the
+// typed counts).
 func (c *Client) SmsCount() (XMLData, error) {
 	return c.Do("api/sms/sms-count", nil)
 }
 
-// SmsSend sends an SMS.
+// SmsSend sends an SMS. Messages longer than a single SMS, up to
+// SmsMaxSegments segments, are sent as a concatenated SMS, split by the
+// device (see SmsSendParts for firmware that does not).
 func (c *Client) SmsSend(msg string, to ...string) (bool, error) {
-	if len(msg) >= 160 {
+	if SmsSegments(msg) > SmsMaxSegments {
 		return false, ErrMessageTooLong
 	}
+	return c.smsSend(msg, to...)
+}
 
+// SmsSendParts sends a long SMS as separate messages, one per segment of the
+// concatenated SMS, in order. It is intended for firmware that rejects
+// messages longer than a single SMS.
+func (c *Client) SmsSendParts(msg string, to ...string) (bool, error) {
+	segs := smsSplit(msg)
+	if len(segs) > SmsMaxSegments {
+		return false, ErrMessageTooLong
+	}
+	for _, seg := range segs {
+		ok, err := c.smsSend(seg, to...)
+		if err != nil || !ok {
+			return ok, err
+		}
+	}
+	return true, nil
+}
+
+// smsSend sends an SMS.
+func (c *Client) smsSend(msg string, to ...string) (bool, error) {
+	return c.smsRequest("api/sms/send-sms", "-1", msg, to...)
+}
+
+// smsRequest sends (or saves) an SMS, with the index of the draft, or -1 for
+// a new message.
+func (c *Client) smsRequest(path, index, msg string, to ...string) (bool, error) {
 	// build phones
 	phones := []string{}
 	for _, t := range to {
+		if err := ValidatePhoneNumber(t); err != nil {
+			return false, err
+		}
 		phones = append(phones, "Phone", t)
 	}
 
 	// send request (order matters below!)
-	return c.doReqCheckOK("api/sms/send-sms", SimpleRequestXML(
-		"Index", "-1",
-		"Phones", "\n"+string(xmlPairs("    ", phones...)),
+	return c.doReqCheckOK(path, requestXML(
+		"Index", index,
+		"Phones", xmlFragment("\n"+xmlPairsString("    ", phones...)),
 		"Sca", "",
 		"Content", msg,
-		"Length", fmt.Sprintf("%d", len(msg)),
+		"Length", strconv.Itoa(SmsLength(msg)),
 		"Reserved", "1",
-		"Date", time.Now().Format("2006-01-02 15:04:05"),
+		"Date", time.Now().Format(TimeLayout),
 	))
 }
 
@@ -772,18 +1266,22 @@ func (c *Client) SmsSendStatus() (XMLData, error) {
 	return c.Do("api/sms/send-status", nil)
 }
 
-// SmsReadSet sets the read status of a SMS.
-func (c *Client) SmsReadSet(id string) (bool, error) {
-	return c.doReqCheckOK("api/sms/set-read", SimpleRequestXML(
-		"Index", id,
-	))
+// SmsReadSet sets the read status of the specified SMS, in a single
+// request.
+func (c *Client) SmsReadSet(ids ...string) (bool, error) {
+	if len(ids) == 0 {
+		return true, nil
+	}
+	return c.doReqCheckOK("api/sms/set-read", SimpleRequestXML(smsIndexes(ids)...))
 }
 
-// SmsDelete deletes a specified SMS.
-func (c *Client) SmsDelete(id string) (bool, error) {
-	return c.doReqCheckOK("api/sms/delete-sms", SimpleRequestXML(
-		"Index", id,
-	))
+// SmsDelete deletes the specified SMS, in a single request (see SmsDeleteAll
+// to empty a box).
+func (c *Client) SmsDelete(ids ...string) (bool, error) {
+	if len(ids) == 0 {
+		return true, nil
+	}
+	return c.doReqCheckOK("api/sms/delete-sms", SimpleRequestXML(smsIndexes(ids)...))
 }
 
 // UssdStatus retrieves current USSD session status information.
@@ -810,8 +1308,19 @@ func (c *Client) UssdCode(code string) (bool, error) {
 	))
 }
 
-// UssdContent retrieves content buffer of the active USSD session.
+// UssdContent retrieves content buffer of the active USSD session, decoding
+// UCS-2 hex encoded content (see DecodeUssd).
 func (c *Client) UssdContent() (string, error) {
+	s, err := c.UssdContentRaw()
+	if err != nil {
+		return "", err
+	}
+	return DecodeUssd(s), nil
+}
+
+// UssdContentRaw retrieves content buffer of the active USSD session, as
+// returned by the device.
+func (c *Client) UssdContentRaw() (string, error) {
 	return c.doReqString("api/ussd/get", nil, "content")
 }
 
@@ -880,7 +1389,7 @@ func (c *Client) PhonebookList(group, page, count uint, sim, sortByName, ascendi
 
 // PhonebookCreate creates a new phonebook entry.
 func (c *Client) PhonebookCreate(group uint, name, phone string, sim bool) (XMLData, error) {
-	return c.Do("api/pb/pb-new", SimpleRequestXML(
+	return c.Do("api/pb/pb-new", requestXML(
 		"GroupID", fmt.Sprintf("%d", group),
 		"SaveType", boolToString(sim),
 		"Field", xmlNvp("FormattedName", name),
@@ -904,6 +1413,11 @@ func (c *Client) DmzConfig() (XMLData, error) {
 // DmzConfigSet enables or disables the DMZ and the DMZ IP address of the
 // device.
 func (c *Client) DmzConfigSet(enabled bool, dmzIPAddress string) (bool, error) {
+	if enabled || dmzIPAddress != "" {
+		if err := ValidateIPv4Address(dmzIPAddress); err != nil {
+			return false, err
+		}
+	}
 	return c.doReqCheckOK("api/security/dmz", SimpleRequestXML(
 		"DmzIPAddress", dmzIPAddress,
 		"DmzStatus", boolToString(enabled),
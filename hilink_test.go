@@ -0,0 +1,105 @@
+package hilink
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/clbanning/mxj"
+)
+
+// fakeDevice is a fake device server, recording the POSTed request bodies
+// by path.
+type fakeDevice struct {
+	*httptest.Server
+
+	// Responses are the response bodies by path. Paths without a response
+	// return OK.
+	Responses map[string]string
+
+	// Sequences are the successive response bodies by path, the last
+	// response being repeated, taking precedence over Responses.
+	Sequences map[string][]string
+
+	mu       sync.Mutex
+	requests map[string][][]byte
+	hits     map[string]int
+}
+
+// newFakeDevice starts a fake device server, closed with Close.
+func newFakeDevice() *fakeDevice {
+	d := &fakeDevice{
+		Responses: make(map[string]string),
+		Sequences: make(map[string][]string),
+		requests:  make(map[string][][]byte),
+		hits:      make(map[string]int),
+	}
+	d.Server = httptest.NewServer(http.HandlerFunc(d.serve))
+	return d
+}
+
+// serve serves a request.
+func (d *fakeDevice) serve(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/webserver/SesTokInfo" {
+		w.Write([]byte(`<response><SesInfo>session</SesInfo><TokInfo>token</TokInfo></response>`))
+		return
+	}
+
+	d.mu.Lock()
+	d.hits[r.URL.Path]++
+	if r.Method == "POST" {
+		body, _ := ioutil.ReadAll(r.Body)
+		d.requests[r.URL.Path] = append(d.requests[r.URL.Path], body)
+	}
+	res, ok := d.Responses[r.URL.Path]
+	if seq := d.Sequences[r.URL.Path]; len(seq) != 0 {
+		res, ok = seq[0], true
+		if len(seq) > 1 {
+			d.Sequences[r.URL.Path] = seq[1:]
+		}
+	}
+	d.mu.Unlock()
+	if !ok {
+		res = `<response>OK</response>`
+	}
+	w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` + res))
+}
+
+// client returns a client of the device.
+func (d *fakeDevice) client(t testing.TB) *Client {
+	c, err := NewClient(URL(d.URL + "/"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	return c
+}
+
+// count returns the number of requests to the path.
+func (d *fakeDevice) count(path string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.hits[path]
+}
+
+// request returns the last request POSTed to the path, decoded.
+func (d *fakeDevice) request(t testing.TB, path string) mxj.Map {
+	d.mu.Lock()
+	reqs := d.requests[path]
+	d.mu.Unlock()
+	if len(reqs) == 0 {
+		t.Fatalf("expected request to %s", path)
+	}
+
+	m, err := mxj.NewMapXml(reqs[len(reqs)-1])
+	if err != nil {
+		t.Fatalf("expected no error decoding %s request, got: %v", path, err)
+	}
+	req, ok := m["request"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected request element, got: %v", m)
+	}
+	return req
+}
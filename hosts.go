@@ -0,0 +1,97 @@
+package hilink
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LanHost is a host connected to the device, via WiFi or Ethernet.
+type LanHost struct {
+	// MAC is the MAC address.
+	MAC string
+
+	// IP is the IPv4 address, when assigned. Hosts with several addresses
+	// report them separated by semicolons.
+	IP string
+
+	// Hostname is the host name, as reported via DHCP.
+	Hostname string
+
+	// Wireless is set for hosts connected via WiFi.
+	Wireless bool
+
+	// SSID is the SSID of wireless hosts.
+	SSID string
+
+	// ConnectedFor is the connection time, with second precision.
+	ConnectedFor time.Duration
+
+	// Signal is the signal strength of wireless hosts, in dBm, where
+	// firmware reports it. Zero when not reported.
+	Signal int
+
+	// Active is set for hosts currently connected. Stations listed by
+	// WifiStationList are always active.
+	Active bool
+}
+
+// hostXML is the raw host entry.
+type hostXML struct {
+	MacAddress     string `xml:"MacAddress"`
+	IpAddress      string `xml:"IpAddress"`
+	HostName       string `xml:"HostName"`
+	ActualName     string `xml:"ActualName"`
+	InterfaceType  string `xml:"InterfaceType"`
+	AssociatedSsid string `xml:"AssociatedSsid"`
+	AssociatedTime string `xml:"AssociatedTime"`
+	Rssi           string `xml:"Rssi"`
+	Active         string `xml:"Active"`
+}
+
+// host converts the raw host entry.
+func (x hostXML) host(wireless bool) LanHost {
+	h := LanHost{
+		MAC:          strings.ToUpper(x.MacAddress),
+		IP:           x.IpAddress,
+		Hostname:     x.HostName,
+		Wireless:     wireless || x.InterfaceType == "Wireless",
+		SSID:         x.AssociatedSsid,
+		ConnectedFor: time.Duration(parseUint(x.AssociatedTime)) * time.Second,
+		Active:       wireless || x.Active == "1",
+	}
+	if h.Hostname == "" {
+		h.Hostname = x.ActualName
+	}
+	if i, err := strconv.Atoi(x.Rssi); err == nil {
+		h.Signal = i
+	}
+	return h
+}
+
+// hosts retrieves the host entries of an endpoint.
+func (c *Client) hosts(path string, wireless bool) ([]LanHost, error) {
+	var res struct {
+		Hosts []hostXML `xml:"Hosts>Host"`
+	}
+	if err := c.doReqXML(path, nil, &res); err != nil {
+		return nil, err
+	}
+
+	hosts := make([]LanHost, len(res.Hosts))
+	for i, x := range res.Hosts {
+		hosts[i] = x.host(wireless)
+	}
+	return hosts, nil
+}
+
+// HostList retrieves the hosts known to the device on its LAN, both wired
+// and wireless, including recently disconnected hosts (see LanHost.Active).
+func (c *Client) HostList() ([]LanHost, error) {
+	return c.hosts("api/lan/HostInfo", false)
+}
+
+// WifiStationList retrieves the stations connected to the WiFi network.
+func (c *Client) WifiStationList() ([]LanHost, error) {
+	return c.hosts("api/wlan/host-list", true)
+}
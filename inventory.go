@@ -0,0 +1,237 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DeviceInventory is the identity and health of a device, as gathered by
+// Manager.Inventory.
+type DeviceInventory struct {
+	// Model is the device model (ie, "E3372h-320").
+	Model string
+
+	// Firmware is the firmware version.
+	Firmware string
+
+	// IMEI is the IMEI of the device.
+	IMEI string
+
+	// ICCID is the ICCID of the SIM card.
+	ICCID string
+
+	// Signal is the signal information, nil when it could not be
+	// retrieved.
+	Signal *Signal
+
+	// DataUsage is the month data usage, in bytes.
+	DataUsage uint64
+
+	// DataLimit is the monthly data limit configured on the device, in
+	// bytes, or 0.
+	DataLimit uint64
+
+	// Time is the collection time.
+	Time time.Time
+
+	// Err is the error encountered when the device could not be reached.
+	Err error
+}
+
+// Inventory is an inventory snapshot of a device fleet, keyed by device
+// name.
+type Inventory map[string]DeviceInventory
+
+// Inventory concurrently gathers the identity and health of all devices.
+func (m *Manager) Inventory(ctx context.Context) Inventory {
+	var mu sync.Mutex
+	inv := make(Inventory)
+
+	errs := m.Each(ctx, func(ctx context.Context, name string, c *Client) error {
+		d := c.inventory()
+
+		mu.Lock()
+		inv[name] = d
+		mu.Unlock()
+
+		return d.Err
+	})
+
+	// devices skipped by a closed context
+	for name, err := range errs {
+		if _, ok := inv[name]; !ok {
+			inv[name] = DeviceInventory{Time: time.Now(), Err: err}
+		}
+	}
+
+	return inv
+}
+
+// inventory gathers the identity and health of the device. Signal and data
+// usage are optional, as not all devices report them.
+func (c *Client) inventory() DeviceInventory {
+	d := DeviceInventory{Time: time.Now()}
+
+	info, err := c.DeviceInformation()
+	if err != nil {
+		d.Err = err
+		return d
+	}
+	d.Model, d.Firmware = info.DeviceName, info.SoftwareVersion
+	d.IMEI, d.ICCID = info.IMEI, info.ICCID
+
+	if s, err := c.Signal(); err == nil {
+		d.Signal = s
+	}
+	d.DataUsage, d.DataLimit, _ = c.monthUsage()
+
+	return d
+}
+
+// InventoryChange is a change event between two inventory snapshots.
+type InventoryChange struct {
+	// Device is the device name.
+	Device string
+
+	// Field is the changed field: "reachable", "model", "firmware", "imei"
+	// or "iccid" (ie, a swapped SIM card), or "added" or "removed" when the
+	// device was added to or removed from the fleet.
+	Field string
+
+	// Old and New are the old and new values, empty when the device was
+	// added or removed.
+	Old, New string
+}
+
+// String satisfies the fmt.Stringer interface.
+func (c InventoryChange) String() string {
+	if c.Field == "added" || c.Field == "removed" {
+		return fmt.Sprintf("%s: %s", c.Device, c.Field)
+	}
+	return fmt.Sprintf("%s: %s changed from %q to %q", c.Device, c.Field, c.Old, c.New)
+}
+
+// DiffInventory returns the identity and reachability changes between the
+// inventory snapshots a and b, in device name order. Identity changes of
+// unreachable devices are ignored.
+func DiffInventory(a, b Inventory) []InventoryChange {
+	names := make(map[string]bool)
+	for name := range a {
+		names[name] = true
+	}
+	for name := range b {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []InventoryChange
+	for _, name := range sorted {
+		x, inA := a[name]
+		y, inB := b[name]
+		switch {
+		case !inA:
+			changes = append(changes, InventoryChange{Device: name, Field: "added"})
+			continue
+		case !inB:
+			changes = append(changes, InventoryChange{Device: name, Field: "removed"})
+			continue
+		}
+
+		if (x.Err == nil) != (y.Err == nil) {
+			changes = append(changes, InventoryChange{name, "reachable", fmt.Sprint(x.Err == nil), fmt.Sprint(y.Err == nil)})
+		}
+		if x.Err != nil || y.Err != nil {
+			continue
+		}
+		for _, f := range []struct {
+			field    string
+			old, new string
+		}{
+			{"model", x.Model, y.Model},
+			{"firmware", x.Firmware, y.Firmware},
+			{"imei", x.IMEI, y.IMEI},
+			{"iccid", x.ICCID, y.ICCID},
+		} {
+			if f.old != f.new {
+				changes = append(changes, InventoryChange{name, f.field, f.old, f.new})
+			}
+		}
+	}
+
+	return changes
+}
+
+// InventoryCollector periodically gathers the inventory of a device fleet.
+type InventoryCollector struct {
+	// Manager is the manager of the devices.
+	Manager *Manager
+
+	// Interval is the collection interval. Defaults to 5 minutes.
+	Interval time.Duration
+
+	// OnInventory is called with each inventory snapshot.
+	OnInventory func(Inventory)
+
+	// OnChange is called for each change between consecutive snapshots.
+	OnChange func(InventoryChange)
+
+	last Inventory
+	sync.Mutex
+}
+
+// Inventory returns the last inventory snapshot, or nil.
+func (ic *InventoryCollector) Inventory() Inventory {
+	ic.Lock()
+	defer ic.Unlock()
+
+	return ic.last
+}
+
+// Run runs the collector until the context is closed.
+func (ic *InventoryCollector) Run(ctx context.Context) error {
+	if ic.Manager == nil {
+		return ErrNilManager
+	}
+
+	interval := ic.Interval
+	if interval == 0 {
+		interval = 5 * time.Minute
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		inv := ic.Manager.Inventory(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		ic.Lock()
+		last := ic.last
+		ic.last = inv
+		ic.Unlock()
+
+		if ic.OnInventory != nil {
+			ic.OnInventory(inv)
+		}
+		if last != nil && ic.OnChange != nil {
+			for _, c := range DiffInventory(last, inv) {
+				ic.OnChange(c)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package hilink
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// lazyInit is the one-time initialization state of a lazy session.
+type lazyInit struct {
+	done bool
+	sync.Mutex
+}
+
+// LazySession is an option that defers starting the session (and detecting
+// quirks, see AutoQuirks) until the first request, so that clients can be
+// created while the device is unreachable. Concurrent first requests wait
+// for a single initialization. A failed initialization is retried with
+// the next request, and is reported with ErrDeviceUnreachable when the
+// device cannot be reached.
+func LazySession(c *Client) error {
+	c.lazy = true
+	return nil
+}
+
+// ensureSession starts the session of a lazy client, once.
+func (c *Client) ensureSession() error {
+	if !c.lazy {
+		return nil
+	}
+
+	c.init.Lock()
+	defer c.init.Unlock()
+
+	if c.init.done {
+		return nil
+	}
+
+	// initialize with an eager client sharing the http.Client, and its
+	// session cookie (see sessionClient)
+	s := c.sessionClient()
+	if err := s.start(); err != nil {
+		var nerr net.Error
+		if errors.As(err, &nerr) {
+			return fmt.Errorf("%w: %v", ErrDeviceUnreachable, err)
+		}
+		return fmt.Errorf("unable to start session: %w", err)
+	}
+
+	c.Lock()
+	c.token = s.token
+	c.quirks = s.quirks
+	c.Unlock()
+	atomic.CompareAndSwapInt32(&c.rsa.detect, rsaUnknown, atomic.LoadInt32(&s.rsa.detect))
+
+	c.init.done = true
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package hilink
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MACFilterMode is the WiFi MAC filter mode.
+type MACFilterMode int
+
+// MACFilterMode values.
+const (
+	MACFilterDisabled MACFilterMode = iota
+	MACFilterAllow
+	MACFilterDeny
+)
+
+// String satisfies the fmt.Stringer interface.
+func (m MACFilterMode) String() string {
+	switch m {
+	case MACFilterAllow:
+		return "allow"
+	case MACFilterDeny:
+		return "deny"
+	}
+	return "disabled"
+}
+
+// MACFilter is the WiFi MAC filter of the primary SSID. Depending on the
+// mode, the listed MAC addresses are the only ones allowed to connect
+// (allow list), or are blocked (deny list).
+type MACFilter struct {
+	// Mode is the filter mode.
+	Mode MACFilterMode
+
+	// MACs are the filtered MAC addresses, at most 10.
+	MACs []string
+}
+
+// MACFilter retrieves the WiFi MAC filter of the primary SSID.
+func (c *Client) MACFilter() (*MACFilter, error) {
+	f := &macFilterResource{c: c}
+	_, ssid, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	mf := &MACFilter{
+		Mode: f.mode(ssid),
+	}
+	for i := 0; i < macFilterSlots; i++ {
+		if v, _ := ssid[fmt.Sprintf("WifiMacFilterMac%d", i)].(string); v != "" {
+			mf.MACs = append(mf.MACs, v)
+		}
+	}
+	return mf, nil
+}
+
+// MACFilterSet sets the WiFi MAC filter of the primary SSID, replacing all
+// filtered MAC addresses.
+func (c *Client) MACFilterSet(mf MACFilter) (bool, error) {
+	if len(mf.MACs) > macFilterSlots {
+		return false, ErrResourceFull
+	}
+	for _, mac := range mf.MACs {
+		if err := ValidateMACAddress(mac); err != nil {
+			return false, err
+		}
+	}
+
+	err := c.macFilterModify(func(f *macFilterResource, ssid map[string]interface{}) error {
+		ssid["WifiMacFilterStatus"] = strconv.Itoa(int(mf.Mode))
+		for i := 0; i < macFilterSlots; i++ {
+			mac := ""
+			if i < len(mf.MACs) {
+				mac = mf.MACs[i]
+			}
+			ssid[fmt.Sprintf("WifiMacFilterMac%d", i)] = mac
+		}
+		return nil
+	})
+	return err == nil, err
+}
+
+// MACFilterModeSet sets the WiFi MAC filter mode of the primary SSID,
+// retaining the filtered MAC addresses.
+func (c *Client) MACFilterModeSet(mode MACFilterMode) (bool, error) {
+	err := c.macFilterModify(func(f *macFilterResource, ssid map[string]interface{}) error {
+		ssid["WifiMacFilterStatus"] = strconv.Itoa(int(mode))
+		return nil
+	})
+	return err == nil, err
+}
+
+// MACFilterAdd adds mac to the WiFi MAC filter of the primary SSID. Adding a
+// listed MAC address succeeds without changes.
+func (c *Client) MACFilterAdd(mac string) (bool, error) {
+	_, err := c.MACFilterResources().Create(Resource{"Mac": mac})
+	return err == nil, err
+}
+
+// MACFilterReplace replaces the MAC address old of the WiFi MAC filter of
+// the primary SSID with mac.
+func (c *Client) MACFilterReplace(old, mac string) (bool, error) {
+	err := c.MACFilterResources().Update(old, Resource{"Mac": mac})
+	return err == nil, err
+}
+
+// MACFilterRemove removes mac from the WiFi MAC filter of the primary SSID.
+// Removing a MAC address not listed succeeds without changes.
+func (c *Client) MACFilterRemove(mac string) (bool, error) {
+	err := c.MACFilterResources().Delete(mac)
+	return err == nil, err
+}
+
+// WifiBlockMAC blocks the WiFi client with the MAC address from connecting
+// to the primary SSID: with a deny list, the MAC address is added to it;
+// with an allow list, it is removed from it. A disabled filter is switched
+// to a deny list.
+func (c *Client) WifiBlockMAC(mac string) (bool, error) {
+	if err := ValidateMACAddress(mac); err != nil {
+		return false, err
+	}
+
+	err := c.macFilterModify(func(f *macFilterResource, ssid map[string]interface{}) error {
+		switch f.mode(ssid) {
+		case MACFilterAllow:
+			f.remove(ssid, mac)
+			return nil
+		case MACFilterDisabled:
+			// start from an empty deny list, as listed entries were allowed
+			for i := 0; i < macFilterSlots; i++ {
+				ssid[fmt.Sprintf("WifiMacFilterMac%d", i)] = ""
+			}
+			ssid["WifiMacFilterStatus"] = strconv.Itoa(int(MACFilterDeny))
+		}
+		return f.add(ssid, mac)
+	})
+	return err == nil, err
+}
+
+// WifiUnblockMAC allows the WiFi client with the MAC address to connect to
+// the primary SSID: with a deny list, the MAC address is removed from it;
+// with an allow list, it is added to it. A disabled filter is left
+// unchanged.
+func (c *Client) WifiUnblockMAC(mac string) (bool, error) {
+	if err := ValidateMACAddress(mac); err != nil {
+		return false, err
+	}
+
+	err := c.macFilterModify(func(f *macFilterResource, ssid map[string]interface{}) error {
+		switch f.mode(ssid) {
+		case MACFilterAllow:
+			return f.add(ssid, mac)
+		case MACFilterDeny:
+			f.remove(ssid, mac)
+		}
+		return nil
+	})
+	return err == nil, err
+}
+
+// macFilterModify retrieves the MAC filter settings of the primary SSID,
+// modifies them with fn, and posts them back.
+func (c *Client) macFilterModify(fn func(f *macFilterResource, ssid map[string]interface{}) error) error {
+	f := &macFilterResource{c: c}
+	d, ssid, err := f.read()
+	if err != nil {
+		return err
+	}
+	if err := fn(f, ssid); err != nil {
+		return err
+	}
+	return f.write(d, ssid)
+}
+
+// mode returns the MAC filter mode of the SSID settings.
+func (f *macFilterResource) mode(ssid map[string]interface{}) MACFilterMode {
+	v, _ := ssid["WifiMacFilterStatus"].(string)
+	return MACFilterMode(parseUint(v))
+}
+
+// add adds mac to a free slot of the SSID settings, unless already listed.
+func (f *macFilterResource) add(ssid map[string]interface{}, mac string) error {
+	if f.slot(ssid, mac) != -1 {
+		return nil
+	}
+	i := f.slot(ssid, "")
+	if i == -1 {
+		return ErrResourceFull
+	}
+	ssid[fmt.Sprintf("WifiMacFilterMac%d", i)] = mac
+	return nil
+}
+
+// remove clears the slot of the SSID settings holding mac, if any.
+func (f *macFilterResource) remove(ssid map[string]interface{}, mac string) {
+	if i := f.slot(ssid, mac); i != -1 {
+		ssid[fmt.Sprintf("WifiMacFilterMac%d", i)] = ""
+	}
+}
@@ -0,0 +1,184 @@
+package hilink
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// DefaultManagerConcurrency is the default number of devices a Manager
+// operates on concurrently.
+const DefaultManagerConcurrency = 8
+
+// Manager manages a fleet of devices, each identified by a name.
+type Manager struct {
+	// Concurrency is the number of devices operated on concurrently.
+	// Defaults to DefaultManagerConcurrency.
+	Concurrency int
+
+	devices map[string]*Client
+	sync.Mutex
+}
+
+// NewManager creates a new device manager.
+func NewManager() *Manager {
+	return &Manager{
+		devices: make(map[string]*Client),
+	}
+}
+
+// Add adds the named device, replacing any device with the same name.
+func (m *Manager) Add(name string, c *Client) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.devices == nil {
+		m.devices = make(map[string]*Client)
+	}
+	m.devices[name] = c
+}
+
+// Remove removes the named device.
+func (m *Manager) Remove(name string) {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.devices, name)
+}
+
+// Client returns the client of the named device, or nil.
+func (m *Manager) Client(name string) *Client {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.devices[name]
+}
+
+// Names returns the sorted names of the devices.
+func (m *Manager) Names() []string {
+	m.Lock()
+	defer m.Unlock()
+
+	names := make([]string, 0, len(m.devices))
+	for name := range m.devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Each calls fn for each device concurrently, returning the errors keyed by
+// device name. Devices not yet started when the context is closed receive
+// the context error.
+func (m *Manager) Each(ctx context.Context, fn func(ctx context.Context, name string, c *Client) error) map[string]error {
+	m.Lock()
+	devices := make(map[string]*Client, len(m.devices))
+	for name, c := range m.devices {
+		devices[name] = c
+	}
+	m.Unlock()
+
+	n := m.Concurrency
+	if n <= 0 {
+		n = DefaultManagerConcurrency
+	}
+
+	var mu sync.Mutex
+	errs := make(map[string]error)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, n)
+	for name, c := range devices {
+		var err error
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case sem <- struct{}{}:
+			// a slot and the context closing may be ready together
+			if err = ctx.Err(); err != nil {
+				<-sem
+			}
+		}
+		if err != nil {
+			mu.Lock()
+			errs[name] = err
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, c *Client) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(ctx, name, c); err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name, c)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// RotationResult is the password rotation result of a device.
+type RotationResult struct {
+	// Changed is set when the device accepted the new password.
+	Changed bool
+
+	// Verified is set when logging in with the new password succeeded.
+	Verified bool
+
+	// Err is the error encountered, if any.
+	Err error
+}
+
+// NeedsRollback determines if the password was changed on the device, but
+// could not be verified, requiring manual intervention.
+func (r RotationResult) NeedsRollback() bool {
+	return r.Changed && !r.Verified
+}
+
+// RotatePassword changes the admin password of all devices to pw, verifying
+// each change by logging in again with the new password. The results are
+// keyed by device name.
+func (m *Manager) RotatePassword(ctx context.Context, pw string) map[string]RotationResult {
+	var mu sync.Mutex
+	results := make(map[string]RotationResult)
+
+	errs := m.Each(ctx, func(ctx context.Context, name string, c *Client) error {
+		var r RotationResult
+		defer func() {
+			mu.Lock()
+			results[name] = r
+			mu.Unlock()
+		}()
+
+		// change
+		if r.Err = checkOK(c.PasswordChange(c.authPW, pw)); r.Err != nil {
+			return r.Err
+		}
+		r.Changed = true
+
+		// verify
+		if r.Err = checkOK(c.RefreshSession()); r.Err != nil {
+			return r.Err
+		}
+		r.Verified = true
+
+		return nil
+	})
+
+	// devices skipped by a closed context
+	for name, err := range errs {
+		if _, ok := results[name]; !ok {
+			results[name] = RotationResult{Err: err}
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,43 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestManagerEachCanceled(t *testing.T) {
+	m := NewManager()
+	m.Concurrency = 1
+	for i := 0; i < 4; i++ {
+		m.Add("dev"+strconv.Itoa(i), &Client{})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errFailed := errors.New("failed")
+	errs := m.Each(ctx, func(ctx context.Context, name string, c *Client) error {
+		// the remaining devices are not started, while this device fails
+		cancel()
+		return errFailed
+	})
+	if len(errs) != 4 {
+		t.Fatalf("expected 4 errors, got: %v", errs)
+	}
+
+	var failed int
+	for name, err := range errs {
+		switch err {
+		case errFailed:
+			failed++
+		case context.Canceled:
+		default:
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+	if failed != 1 {
+		t.Errorf("expected 1 device failed, got: %d", failed)
+	}
+}
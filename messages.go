@@ -0,0 +1,58 @@
+package hilink
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage is the language of the ErrorCodeMessageMap messages.
+const DefaultLanguage = "en"
+
+// errorCatalog is the error message catalog, keyed by language.
+var errorCatalog = struct {
+	sync.RWMutex
+	langs map[string]map[string]string
+}{
+	langs: make(map[string]map[string]string),
+}
+
+// RegisterErrorMessages registers the error messages of a language (ie,
+// "de", or "pt-BR"), keyed by error code. Messages are merged with the
+// messages previously registered for the language, and messages registered
+// for DefaultLanguage override the ErrorCodeMessageMap messages.
+func RegisterErrorMessages(lang string, messages map[string]string) {
+	errorCatalog.Lock()
+	defer errorCatalog.Unlock()
+
+	lang = strings.ToLower(lang)
+	m, ok := errorCatalog.langs[lang]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		errorCatalog.langs[lang] = m
+	}
+	for code, msg := range messages {
+		m[code] = msg
+	}
+}
+
+// ErrorMessage returns the message of an error code in the language,
+// falling back to the base language (ie, "pt" for "pt-BR"), and then to
+// DefaultLanguage. An empty string is returned for unknown codes.
+func ErrorMessage(lang, code string) string {
+	errorCatalog.RLock()
+	defer errorCatalog.RUnlock()
+
+	lang = strings.ToLower(lang)
+	langs := []string{lang}
+	if i := strings.IndexAny(lang, "-_"); i != -1 {
+		langs = append(langs, lang[:i])
+	}
+	langs = append(langs, DefaultLanguage)
+
+	for _, l := range langs {
+		if msg, ok := errorCatalog.langs[l][code]; ok {
+			return msg
+		}
+	}
+	return ErrorCodeMessageMap[code]
+}
@@ -0,0 +1,381 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// EventType is the type of a Monitor event.
+type EventType int
+
+// EventType values.
+const (
+	EventConnectionUp EventType = iota
+	EventConnectionDown
+	EventNewSMS
+	EventSignalChanged
+	EventWANIPChanged
+	EventSIMStateChanged
+)
+
+// String satisfies the fmt.Stringer interface.
+func (t EventType) String() string {
+	switch t {
+	case EventConnectionUp:
+		return "connection up"
+	case EventConnectionDown:
+		return "connection down"
+	case EventNewSMS:
+		return "new sms"
+	case EventSignalChanged:
+		return "signal changed"
+	case EventWANIPChanged:
+		return "wan ip changed"
+	case EventSIMStateChanged:
+		return "sim state changed"
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event is a Monitor event.
+type Event struct {
+	// Type is the event type.
+	Type EventType
+
+	// Time is the time the event was detected.
+	Time time.Time
+
+	// Status is the device status, for connection, WAN IP and SIM state
+	// events.
+	Status *Status
+
+	// WANIP are the WAN addresses, for WAN IP events.
+	WANIP *WANIP
+
+	// Signal is the signal information, for signal events.
+	Signal *Signal
+
+	// Message is the received message, for SMS events.
+	Message *SmsMessage
+
+	// Old and New are the previous and new values of WAN IP (the changed
+	// IPv4 or IPv6 address) and SIM state (status code) events.
+	Old, New string
+}
+
+// Monitor polls a device, dispatching typed events to its subscribers, so
+// that multiple consumers share a single set of polls. Only the endpoints
+// needed by the subscribed event types are polled, for example:
+//
+//	m := &Monitor{Client: client}
+//	m.Subscribe(func(e Event) {
+//		log.Printf("%s: %s", e.Type, e.New)
+//	}, EventConnectionDown, EventWANIPChanged)
+//	err := m.Run(ctx)
+//
+// The first poll of each endpoint establishes the initial state, without
+// dispatching events. WAN IP events are dispatched when an address changes to
+// a new address, not when it is lost (ie, when disconnected), so that they
+// can drive dynamic DNS updates.
+type Monitor struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// StatusInterval is the status poll interval, for connection, WAN IP
+	// and SIM state events. Defaults to 10 seconds.
+	StatusInterval time.Duration
+
+	// SignalInterval is the signal poll interval. Defaults to 30 seconds.
+	SignalInterval time.Duration
+
+	// SmsInterval is the inbox poll interval (see SmsWatcher).
+	SmsInterval time.Duration
+
+	// Scheduler, when set, polls the status, signal and notification
+	// endpoints along with the other consumers of the device, ie an
+	// exporter (see Scheduler).
+	Scheduler *Scheduler
+
+	// SignalDelta is the change of RSRP or SINR, in dB, dispatching a
+	// signal event. Band, cell and mode changes always dispatch an event.
+	// Defaults to 3 dB.
+	SignalDelta float64
+
+	// Store, when set, persists the state of the SMS watcher (see
+	// SmsWatcher), and the last WAN addresses, so that WAN IP changes while
+	// not running are dispatched on the first poll.
+	Store Store
+
+	// OnError is called when an endpoint cannot be polled.
+	OnError func(error)
+
+	subs   map[*monitorSub]bool
+	status *Status
+	signal *Signal
+	wan    *WANIP
+	sync.Mutex
+}
+
+// monitorSub is a Monitor subscription.
+type monitorSub struct {
+	types map[EventType]bool
+	fn    func(Event)
+}
+
+// wants determines if the subscription wants the event type.
+func (s *monitorSub) wants(t EventType) bool {
+	return len(s.types) == 0 || s.types[t]
+}
+
+// Subscribe registers fn to be called with the events of the types, or all
+// events when no type is given. The returned func removes the subscription.
+// Subscriptions are only effective for the endpoints polled when Run
+// starts. fn is called from the polling goroutine, and must not block.
+func (m *Monitor) Subscribe(fn func(Event), types ...EventType) func() {
+	s := &monitorSub{fn: fn, types: make(map[EventType]bool)}
+	for _, t := range types {
+		s.types[t] = true
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.subs == nil {
+		m.subs = make(map[*monitorSub]bool)
+	}
+	m.subs[s] = true
+
+	return func() {
+		m.Lock()
+		defer m.Unlock()
+
+		delete(m.subs, s)
+	}
+}
+
+// Events returns a channel receiving the events of the types, or all events
+// when no type is given, buffering up to size events. Events are dropped
+// when the buffer is full. The returned func removes the subscription.
+func (m *Monitor) Events(size int, types ...EventType) (<-chan Event, func()) {
+	ch := make(chan Event, size)
+	cancel := m.Subscribe(func(e Event) {
+		select {
+		case ch <- e:
+		default:
+		}
+	}, types...)
+	return ch, cancel
+}
+
+// Run runs the monitor until the context is closed.
+func (m *Monitor) Run(ctx context.Context) error {
+	if m.Client == nil {
+		return ErrNilClient
+	}
+
+	var status, signal *poller
+	if m.wants(EventConnectionUp, EventConnectionDown, EventWANIPChanged, EventSIMStateChanged) {
+		status = newPoller(m.Scheduler, m.Client, "api/monitoring/status", durationOr(m.StatusInterval, 10*time.Second))
+		defer status.close()
+	}
+	if m.wants(EventSignalChanged) {
+		signal = newPoller(m.Scheduler, m.Client, "api/device/signal", durationOr(m.SignalInterval, 30*time.Second))
+		defer signal.close()
+	}
+
+	var msgs chan SmsMessage
+	if m.wants(EventNewSMS) {
+		msgs = make(chan SmsMessage)
+		w := &SmsWatcher{
+			Client:    m.Client,
+			C:         msgs,
+			Interval:  m.SmsInterval,
+			Scheduler: m.Scheduler,
+			Store:     m.Store,
+			OnError:   m.OnError,
+		}
+		go w.Run(ctx)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-status.C():
+			m.pollStatus(decodeStatus(status.fetch()))
+		case <-signal.C():
+			m.pollSignal(decodeSignal(signal.fetch()))
+		case msg, ok := <-msgs:
+			if !ok {
+				msgs = nil
+				continue
+			}
+			m.dispatch(Event{Type: EventNewSMS, Message: &msg})
+		}
+	}
+}
+
+// wants determines if any subscription wants any of the event types.
+func (m *Monitor) wants(types ...EventType) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	for s := range m.subs {
+		for _, t := range types {
+			if s.wants(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pollStatus handles the polled status, dispatching connection, WAN IP and
+// SIM state events.
+func (m *Monitor) pollStatus(s *Status, err error) {
+	if err != nil {
+		m.onError(err)
+		return
+	}
+
+	last := m.status
+	m.status = s
+	if last == nil {
+		m.loadWANIP(s)
+		return
+	}
+
+	up := s.ConnectionStatus == ConnectionStatusConnected
+	wasUp := last.ConnectionStatus == ConnectionStatusConnected
+	switch {
+	case up && !wasUp:
+		m.dispatch(Event{Type: EventConnectionUp, Status: s, Old: last.ConnectionStatus.String(), New: s.ConnectionStatus.String()})
+	case !up && wasUp:
+		m.dispatch(Event{Type: EventConnectionDown, Status: s, Old: last.ConnectionStatus.String(), New: s.ConnectionStatus.String()})
+	}
+	m.checkWANIP(s)
+	if s.SimStatus != last.SimStatus {
+		m.dispatch(Event{Type: EventSIMStateChanged, Status: s, Old: fmt.Sprint(last.SimStatus), New: fmt.Sprint(s.SimStatus)})
+	}
+}
+
+// WAN address Store keys.
+const (
+	wanIPv4Key = "wan/ipv4"
+	wanIPv6Key = "wan/ipv6"
+)
+
+// loadWANIP establishes the initial WAN addresses, dispatching WAN IP events
+// for the addresses changed since they were stored.
+func (m *Monitor) loadWANIP(s *Status) {
+	last := m.Client.wanIP(s)
+	m.wan = last
+	if m.Store == nil {
+		return
+	}
+
+	for _, v := range []struct {
+		key string
+		dst *string
+	}{{wanIPv4Key, &last.IPv4}, {wanIPv6Key, &last.IPv6}} {
+		stored, ok, err := m.Store.Get(v.key)
+		switch {
+		case err == nil && ok:
+			*v.dst = stored
+		case err == nil && *v.dst != "":
+			err = m.Store.Set(v.key, *v.dst)
+		}
+		if err != nil {
+			m.onError(err)
+		}
+	}
+	m.checkWANIP(s)
+}
+
+// checkWANIP dispatches WAN IP events for the changed WAN addresses, and
+// stores them.
+func (m *Monitor) checkWANIP(s *Status) {
+	last := m.wan
+	cur := m.Client.wanIP(s)
+	if cur.IPv4 == "" {
+		cur.IPv4 = last.IPv4
+	}
+	if cur.IPv6 == "" {
+		cur.IPv6 = last.IPv6
+	}
+	m.wan = cur
+
+	for _, v := range []struct {
+		key, old, new string
+	}{{wanIPv4Key, last.IPv4, cur.IPv4}, {wanIPv6Key, last.IPv6, cur.IPv6}} {
+		if v.old == v.new {
+			continue
+		}
+		if m.Store != nil {
+			if err := m.Store.Set(v.key, v.new); err != nil {
+				m.onError(err)
+			}
+		}
+		m.dispatch(Event{Type: EventWANIPChanged, Status: s, WANIP: cur, Old: v.old, New: v.new})
+	}
+}
+
+// pollSignal handles the polled signal information, dispatching signal
+// events.
+func (m *Monitor) pollSignal(s *Signal, err error) {
+	if err != nil {
+		m.onError(err)
+		return
+	}
+
+	delta := m.SignalDelta
+	if delta == 0 {
+		delta = 3
+	}
+
+	last := m.signal
+	switch {
+	case last == nil:
+		m.signal = s
+	case s.Mode != last.Mode || s.Band != last.Band || s.CellID != last.CellID,
+		math.Abs(s.RSRP-last.RSRP) >= delta || math.Abs(s.SINR-last.SINR) >= delta:
+		m.signal = s
+		m.dispatch(Event{Type: EventSignalChanged, Signal: s})
+	}
+}
+
+// dispatch dispatches the event to the subscriptions.
+func (m *Monitor) dispatch(e Event) {
+	e.Time = time.Now()
+
+	m.Lock()
+	var fns []func(Event)
+	for s := range m.subs {
+		if s.wants(e.Type) {
+			fns = append(fns, s.fn)
+		}
+	}
+	m.Unlock()
+
+	for _, fn := range fns {
+		fn(e)
+	}
+}
+
+// onError calls the error callback.
+func (m *Monitor) onError(err error) {
+	if m.OnError != nil {
+		m.OnError(err)
+	}
+}
+
+// durationOr returns d, or def when d is zero.
+func durationOr(d, def time.Duration) time.Duration {
+	if d == 0 {
+		return def
+	}
+	return d
+}
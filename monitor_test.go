@@ -0,0 +1,30 @@
+package hilink
+
+import (
+	"testing"
+)
+
+func TestMonitorWANIPDialupFallback(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/monitoring/status"] = `<response><ConnectionStatus>901</ConnectionStatus></response>`
+	d.Responses["/api/dialup/connection"] = `<response><ConnectMode>0</ConnectMode><WanIPAddress>10.1.1.1</WanIPAddress></response>`
+
+	m := &Monitor{Client: d.client(t)}
+	var events []Event
+	m.Subscribe(func(e Event) { events = append(events, e) }, EventWANIPChanged)
+
+	m.pollStatus(m.Client.Status())
+	if m.wan.IPv4 != "10.1.1.1" {
+		t.Errorf("expected initial address 10.1.1.1, got: %q", m.wan.IPv4)
+	}
+
+	d.Responses["/api/dialup/connection"] = `<response><ConnectMode>0</ConnectMode><WanIPAddress>10.2.2.2</WanIPAddress></response>`
+	m.pollStatus(m.Client.Status())
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got: %d", len(events))
+	}
+	if e := events[0]; e.Old != "10.1.1.1" || e.New != "10.2.2.2" || e.WANIP.IPv4 != "10.2.2.2" {
+		t.Errorf("expected 10.1.1.1 -> 10.2.2.2, got: %s -> %s (%v)", e.Old, e.New, e.WANIP)
+	}
+}
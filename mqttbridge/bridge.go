@@ -0,0 +1,313 @@
+// Package mqttbridge bridges a Hilink device to an MQTT broker, publishing
+// received SMS messages, the signal information and the connection status,
+// and handling commands to send SMS messages and to toggle the mobile data
+// connection, for example:
+//
+//	conn, err := mqttbridge.Dial("localhost:1883", mqttbridge.Options{})
+//	if err != nil {
+//		// ...
+//	}
+//	b := &mqttbridge.Bridge{Client: client, Conn: conn}
+//	err = b.Run(ctx)
+//
+// Topics, relative to the prefix (ie, "hilink"):
+//
+//	sms/received    received SMS messages, as JSON
+//	signal          the signal information, as JSON (retained)
+//	status          the device status, as JSON (retained)
+//	connection      "connected" or "disconnected" (retained)
+//	command/sms     send an SMS message, as JSON {"to": "...", "message": "..."}
+//	command/data    "on" connects, "off" disconnects the mobile data connection
+//	error           command errors
+//
+// With Discovery set, Home Assistant MQTT discovery configs are published
+// for the signal values, the connection, the mobile data connection and,
+// optionally, sending SMS messages.
+package mqttbridge
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/jpunie/hilink"
+)
+
+// DefaultPrefix is the default topic prefix.
+const DefaultPrefix = "hilink"
+
+// Conn is a connection to an MQTT broker. Client satisfies the interface,
+// which can also be implemented with third party MQTT clients.
+type Conn interface {
+	// Publish publishes the payload to the topic.
+	Publish(topic string, payload []byte, retain bool) error
+
+	// Subscribe subscribes to the topic, calling fn for each message.
+	Subscribe(topic string, fn func(topic string, payload []byte)) error
+}
+
+// Bridge bridges a device to an MQTT broker.
+type Bridge struct {
+	// Client is the client of the device.
+	Client *hilink.Client
+
+	// Conn is the broker connection.
+	Conn Conn
+
+	// Prefix is the topic prefix. Defaults to DefaultPrefix.
+	Prefix string
+
+	// Interval is the signal and status poll interval. Defaults to 30
+	// seconds.
+	Interval time.Duration
+
+	// SmsInterval is the inbox poll interval (see hilink.SmsWatcher).
+	SmsInterval time.Duration
+
+	// Delete deletes published SMS messages from the device.
+	Delete bool
+
+	// Store, when set, persists the last published SMS message (see
+	// hilink.SmsWatcher).
+	Store hilink.Store
+
+	// Discovery publishes Home Assistant MQTT discovery configs when the
+	// bridge starts, so that the device appears in Home Assistant.
+	Discovery bool
+
+	// DiscoveryPrefix is the Home Assistant discovery topic prefix. Defaults
+	// to DefaultDiscoveryPrefix.
+	DiscoveryPrefix string
+
+	// DeviceID is the device identifier of the discovery configs. Defaults
+	// to the serial number of the device.
+	DeviceID string
+
+	// SmsNotifyTo is the phone number SMS messages are sent to by the Home
+	// Assistant notify entity. The entity is only published when set.
+	SmsNotifyTo string
+
+	// OnError is called when the device cannot be polled, or a message
+	// cannot be published.
+	OnError func(error)
+
+	// OnStatus is called after the status is published, ie to publish
+	// additional topics.
+	OnStatus func(*hilink.Status)
+}
+
+// Message is a received SMS message, as published.
+type Message struct {
+	Index   int       `json:"index"`
+	Phone   string    `json:"phone"`
+	Content string    `json:"content"`
+	Date    time.Time `json:"date"`
+}
+
+// Status is the device status, as published.
+type Status struct {
+	Connection     string `json:"connection"`
+	NetworkType    string `json:"network_type"`
+	SignalBars     int    `json:"signal_bars"`
+	Roaming        bool   `json:"roaming"`
+	WanIPAddress   string `json:"wan_ip_address"`
+	WifiClients    int    `json:"wifi_clients"`
+	BatteryPercent int    `json:"battery_percent,omitempty"`
+}
+
+// SmsCommand is the command/sms payload.
+type SmsCommand struct {
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// Topic returns the full topic of the topic name (ie, "signal").
+func (b *Bridge) Topic(name string) string {
+	prefix := b.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + name
+}
+
+// Run runs the bridge until the context is closed.
+func (b *Bridge) Run(ctx context.Context) error {
+	switch {
+	case b.Client == nil:
+		return hilink.ErrNilClient
+	case b.Conn == nil:
+		return ErrNilConn
+	}
+
+	// home assistant discovery
+	if b.Discovery {
+		if err := b.publishDiscovery(); err != nil {
+			return err
+		}
+	}
+
+	// commands, handled in order off the connection's read loop, as they
+	// can take up to a minute (ie, connecting)
+	cmds := make(chan queuedCommand, commandQueueSize)
+	go b.runCommands(ctx, cmds)
+	if err := b.Conn.Subscribe(b.Topic("command/+"), func(topic string, payload []byte) {
+		cmd := queuedCommand{
+			name:    topic[strings.LastIndexByte(topic, '/')+1:],
+			payload: append([]byte(nil), payload...),
+		}
+		select {
+		case cmds <- cmd:
+		default:
+			b.publish("error", []byte(ErrCommandQueueFull.Error()), false)
+		}
+	}); err != nil {
+		return err
+	}
+
+	// sms
+	msgs := make(chan hilink.SmsMessage)
+	w := &hilink.SmsWatcher{
+		Client:   b.Client,
+		C:        msgs,
+		Interval: b.SmsInterval,
+		Delete:   b.Delete,
+		Store:    b.Store,
+		OnError:  b.onError,
+	}
+	go w.Run(ctx)
+
+	interval := b.Interval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	b.poll()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case m, ok := <-msgs:
+			if !ok {
+				msgs = nil
+				continue
+			}
+			b.publishJSON("sms/received", Message{m.Index, m.Phone, m.Content, m.Date}, false)
+		case <-t.C:
+			b.poll()
+		}
+	}
+}
+
+// commandQueueSize is the number of received commands queued while a command
+// is handled.
+const commandQueueSize = 16
+
+// queuedCommand is a received command.
+type queuedCommand struct {
+	name    string
+	payload []byte
+}
+
+// runCommands handles the queued commands until the context is closed.
+func (b *Bridge) runCommands(ctx context.Context, cmds <-chan queuedCommand) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case cmd := <-cmds:
+			if err := b.command(ctx, cmd.name, cmd.payload); err != nil {
+				b.publish("error", []byte(err.Error()), false)
+			}
+		}
+	}
+}
+
+// poll publishes the signal information and the device status.
+func (b *Bridge) poll() {
+	if sig, err := b.Client.Signal(); err != nil {
+		b.onError(err)
+	} else {
+		b.publishJSON("signal", sig, true)
+	}
+
+	s, err := b.Client.Status()
+	if err != nil {
+		b.onError(err)
+		return
+	}
+	conn := "disconnected"
+	if s.ConnectionStatus == hilink.ConnectionStatusConnected {
+		conn = "connected"
+	}
+	b.publishJSON("status", Status{
+		Connection:     s.ConnectionStatus.String(),
+		NetworkType:    s.NetworkType.String(),
+		SignalBars:     s.SignalIcon,
+		Roaming:        s.RoamingStatus != 0,
+		WanIPAddress:   s.WanIPAddress,
+		WifiClients:    s.CurrentWifiUser,
+		BatteryPercent: s.BatteryPercent,
+	}, true)
+	b.publish("connection", []byte(conn), true)
+
+	if b.OnStatus != nil {
+		b.OnStatus(s)
+	}
+}
+
+// command handles a command.
+func (b *Bridge) command(ctx context.Context, name string, payload []byte) error {
+	switch name {
+	case "sms":
+		var cmd SmsCommand
+		if err := json.Unmarshal(payload, &cmd); err != nil {
+			return err
+		}
+		_, err := b.Client.SmsSend(cmd.Message, cmd.To)
+		return err
+
+	case "data":
+		var err error
+		switch strings.ToLower(strings.TrimSpace(string(payload))) {
+		case "on", "1", "true":
+			_, err = b.Client.ConnectAndWait(ctx)
+		case "off", "0", "false":
+			_, err = b.Client.DisconnectAndWait(ctx)
+		default:
+			return nil
+		}
+		if err == nil {
+			b.poll()
+		}
+		return err
+	}
+	return nil
+}
+
+// publishJSON publishes v, as JSON, to the topic name.
+func (b *Bridge) publishJSON(name string, v interface{}, retain bool) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		b.onError(err)
+		return
+	}
+	b.publish(name, buf, retain)
+}
+
+// publish publishes the payload to the topic name.
+func (b *Bridge) publish(name string, payload []byte, retain bool) {
+	if err := b.Conn.Publish(b.Topic(name), payload, retain); err != nil {
+		b.onError(err)
+	}
+}
+
+// onError calls the error callback.
+func (b *Bridge) onError(err error) {
+	if b.OnError != nil {
+		b.OnError(err)
+	}
+}
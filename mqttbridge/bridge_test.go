@@ -0,0 +1,99 @@
+package mqttbridge
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jpunie/hilink"
+)
+
+// fakeConn is a fake broker connection.
+type fakeConn struct {
+	mu        sync.Mutex
+	handler   func(string, []byte)
+	published map[string][]byte
+}
+
+// Publish satisfies the Conn interface.
+func (c *fakeConn) Publish(topic string, payload []byte, retain bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.published[topic] = payload
+	return nil
+}
+
+// Subscribe satisfies the Conn interface.
+func (c *fakeConn) Subscribe(topic string, fn func(string, []byte)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handler = fn
+	return nil
+}
+
+func TestBridgeCommandsDoNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	sent := make(chan struct{}, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/webserver/SesTokInfo":
+			w.Write([]byte(`<response><SesInfo>a</SesInfo><TokInfo>b</TokInfo></response>`))
+		case "/api/sms/send-sms":
+			<-release
+			sent <- struct{}{}
+			w.Write([]byte(`<response>OK</response>`))
+		default:
+			w.Write([]byte(`<response></response>`))
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client, err := hilink.NewClient(hilink.URL(srv.URL + "/"))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	conn := &fakeConn{published: make(map[string][]byte)}
+	b := &Bridge{Client: client, Conn: conn, Interval: time.Hour, SmsInterval: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Run(ctx)
+
+	var handler func(string, []byte)
+	for i := 0; handler == nil && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+		conn.mu.Lock()
+		handler = conn.handler
+		conn.mu.Unlock()
+	}
+	if handler == nil {
+		t.Fatal("expected command subscription")
+	}
+
+	// the blocked sends do not block the read loop
+	done := make(chan struct{})
+	go func() {
+		handler("hilink/command/sms", []byte(`{"to": "+6281234", "message": "a"}`))
+		handler("hilink/command/sms", []byte(`{"to": "+6281234", "message": "b"}`))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected command handler to return")
+	}
+
+	release <- struct{}{}
+	release <- struct{}{}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-sent:
+		case <-time.After(time.Second):
+			t.Fatal("expected queued commands to be handled")
+		}
+	}
+}
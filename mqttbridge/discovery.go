@@ -0,0 +1,141 @@
+package mqttbridge
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// DefaultDiscoveryPrefix is the default Home Assistant discovery topic
+// prefix.
+const DefaultDiscoveryPrefix = "homeassistant"
+
+// discoveryDevice is the device of Home Assistant discovery configs.
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model,omitempty"`
+	SwVersion    string   `json:"sw_version,omitempty"`
+}
+
+// discoveryConfig is a Home Assistant discovery config.
+type discoveryConfig struct {
+	Name              string          `json:"name"`
+	UniqueID          string          `json:"unique_id"`
+	StateTopic        string          `json:"state_topic,omitempty"`
+	ValueTemplate     string          `json:"value_template,omitempty"`
+	CommandTopic      string          `json:"command_topic,omitempty"`
+	CommandTemplate   string          `json:"command_template,omitempty"`
+	UnitOfMeasurement string          `json:"unit_of_measurement,omitempty"`
+	DeviceClass       string          `json:"device_class,omitempty"`
+	StateClass        string          `json:"state_class,omitempty"`
+	PayloadOn         string          `json:"payload_on,omitempty"`
+	PayloadOff        string          `json:"payload_off,omitempty"`
+	StateOn           string          `json:"state_on,omitempty"`
+	StateOff          string          `json:"state_off,omitempty"`
+	Device            discoveryDevice `json:"device"`
+}
+
+// publishDiscovery publishes the Home Assistant discovery configs of the
+// device: signal sensors, the connection binary sensor, the mobile data
+// switch and, when SmsNotifyTo is set, the SMS notify entity.
+func (b *Bridge) publishDiscovery() error {
+	d, err := b.Client.DeviceInformation()
+	if err != nil {
+		return err
+	}
+
+	id := b.DeviceID
+	if id == "" {
+		id = d.SerialNumber
+	}
+	if id == "" {
+		id = d.IMEI
+	}
+	id = "hilink_" + strings.ToLower(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, id))
+
+	dev := discoveryDevice{
+		Identifiers:  []string{id},
+		Name:         d.DeviceName,
+		Manufacturer: "Huawei",
+		Model:        d.DeviceName,
+		SwVersion:    d.SoftwareVersion,
+	}
+
+	configs := map[string]discoveryConfig{
+		"sensor/rsrp": {
+			Name:              "RSRP",
+			StateTopic:        b.Topic("signal"),
+			ValueTemplate:     "{{ value_json.RSRP }}",
+			UnitOfMeasurement: "dBm",
+			DeviceClass:       "signal_strength",
+			StateClass:        "measurement",
+		},
+		"sensor/rsrq": {
+			Name:              "RSRQ",
+			StateTopic:        b.Topic("signal"),
+			ValueTemplate:     "{{ value_json.RSRQ }}",
+			UnitOfMeasurement: "dB",
+			StateClass:        "measurement",
+		},
+		"sensor/sinr": {
+			Name:              "SINR",
+			StateTopic:        b.Topic("signal"),
+			ValueTemplate:     "{{ value_json.SINR }}",
+			UnitOfMeasurement: "dB",
+			StateClass:        "measurement",
+		},
+		"sensor/network_type": {
+			Name:          "Network type",
+			StateTopic:    b.Topic("status"),
+			ValueTemplate: "{{ value_json.network_type }}",
+		},
+		"binary_sensor/connection": {
+			Name:        "Connection",
+			StateTopic:  b.Topic("connection"),
+			PayloadOn:   "connected",
+			PayloadOff:  "disconnected",
+			DeviceClass: "connectivity",
+		},
+		"switch/data": {
+			Name:         "Mobile data",
+			StateTopic:   b.Topic("connection"),
+			CommandTopic: b.Topic("command/data"),
+			PayloadOn:    "on",
+			PayloadOff:   "off",
+			StateOn:      "connected",
+			StateOff:     "disconnected",
+		},
+	}
+	if b.SmsNotifyTo != "" {
+		to, _ := json.Marshal(b.SmsNotifyTo)
+		configs["notify/sms"] = discoveryConfig{
+			Name:            "SMS",
+			CommandTopic:    b.Topic("command/sms"),
+			CommandTemplate: `{"to": ` + string(to) + `, "message": {{ value | tojson }}}`,
+		}
+	}
+
+	prefix := b.DiscoveryPrefix
+	if prefix == "" {
+		prefix = DefaultDiscoveryPrefix
+	}
+	for name, cfg := range configs {
+		i := strings.IndexByte(name, '/')
+		cfg.UniqueID = id + "_" + name[i+1:]
+		cfg.Device = dev
+		buf, err := json.Marshal(cfg)
+		if err != nil {
+			return err
+		}
+		if err := b.Conn.Publish(prefix+"/"+name[:i]+"/"+id+"/"+name[i+1:]+"/config", buf, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
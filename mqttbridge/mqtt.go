@@ -0,0 +1,332 @@
+package mqttbridge
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MQTT 3.1.1 packet types.
+const (
+	packetConnect     = 0x10
+	packetConnack     = 0x20
+	packetPublish     = 0x30
+	packetSubscribe   = 0x82
+	packetPingreq     = 0xc0
+	packetDisconnect  = 0xe0
+	packetTypeMask    = 0xf0
+	maxRemainingBytes = 268435455
+)
+
+// Error values.
+var (
+	// ErrConnectionRefused is the connection refused error, returned when
+	// the broker rejects the connection.
+	ErrConnectionRefused = errors.New("connection refused")
+
+	// ErrClosed is the connection closed error.
+	ErrClosed = errors.New("connection closed")
+
+	// ErrNilConn is the nil broker connection error.
+	ErrNilConn = errors.New("nil broker connection")
+
+	// ErrPacketTooLarge is the packet too large error.
+	ErrPacketTooLarge = errors.New("packet too large")
+
+	// ErrCommandQueueFull is the command queue full error, published when
+	// commands are received faster than they are handled.
+	ErrCommandQueueFull = errors.New("command queue full")
+)
+
+// Options are the connection options of a Client.
+type Options struct {
+	// ClientID is the client identifier. Defaults to "hilink".
+	ClientID string
+
+	// Username and Password are the broker credentials, if any.
+	Username string
+	Password string
+
+	// KeepAlive is the keep alive interval. Defaults to 1 minute.
+	KeepAlive time.Duration
+}
+
+// Client is a minimal MQTT 3.1.1 client, publishing and subscribing with
+// QoS 0, that satisfies the Conn interface.
+type Client struct {
+	conn net.Conn
+	subs map[string]func(topic string, payload []byte)
+	id   uint16
+	done chan struct{}
+	err  error
+	once sync.Once
+	sync.Mutex
+}
+
+// Dial connects to the MQTT broker at the TCP address (ie,
+// "localhost:1883").
+func Dial(addr string, opts Options) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewClient(conn, opts)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewClient creates a client over an established connection to the broker
+// (ie, a TLS connection).
+func NewClient(conn net.Conn, opts Options) (*Client, error) {
+	if opts.ClientID == "" {
+		opts.ClientID = "hilink"
+	}
+	if opts.KeepAlive == 0 {
+		opts.KeepAlive = time.Minute
+	}
+
+	// connect
+	flags := byte(0x02) // clean session
+	payload := appendString(nil, opts.ClientID)
+	if opts.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, opts.Username)
+	}
+	if opts.Password != "" {
+		flags |= 0x40
+		payload = appendString(payload, opts.Password)
+	}
+	keepAlive := uint16(opts.KeepAlive / time.Second)
+	b := appendString(nil, "MQTT")
+	b = append(b, 4, flags, byte(keepAlive>>8), byte(keepAlive))
+	if err := writePacket(conn, packetConnect, append(b, payload...)); err != nil {
+		return nil, err
+	}
+
+	// connack
+	r := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	typ, ack, err := readPacket(r)
+	if err != nil {
+		return nil, err
+	}
+	if typ&packetTypeMask != packetConnack || len(ack) != 2 {
+		return nil, fmt.Errorf("mqtt: unexpected packet 0x%02x", typ)
+	}
+	if ack[1] != 0 {
+		return nil, fmt.Errorf("%w (code %d)", ErrConnectionRefused, ack[1])
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	c := &Client{
+		conn: conn,
+		subs: make(map[string]func(string, []byte)),
+		done: make(chan struct{}),
+	}
+	go c.read(r)
+	go c.ping(opts.KeepAlive / 2)
+
+	return c, nil
+}
+
+// Publish satisfies the Conn interface.
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	typ := byte(packetPublish)
+	if retain {
+		typ |= 0x01
+	}
+	return c.write(typ, append(appendString(nil, topic), payload...))
+}
+
+// Subscribe satisfies the Conn interface. The topic may contain the + and #
+// wildcards.
+func (c *Client) Subscribe(topic string, fn func(topic string, payload []byte)) error {
+	c.Lock()
+	c.subs[topic] = fn
+	c.id++
+	id := c.id
+	c.Unlock()
+
+	b := []byte{byte(id >> 8), byte(id)}
+	b = appendString(b, topic)
+	return c.write(packetSubscribe, append(b, 0))
+}
+
+// Done returns a channel that is closed when the connection is lost or
+// closed.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error that closed the connection.
+func (c *Client) Err() error {
+	c.Lock()
+	defer c.Unlock()
+	return c.err
+}
+
+// Close disconnects from the broker.
+func (c *Client) Close() error {
+	_ = c.write(packetDisconnect, nil)
+	c.close(ErrClosed)
+	return nil
+}
+
+// close closes the connection with the error.
+func (c *Client) close(err error) {
+	c.once.Do(func() {
+		c.Lock()
+		c.err = err
+		c.Unlock()
+		c.conn.Close()
+		close(c.done)
+	})
+}
+
+// write writes a packet.
+func (c *Client) write(typ byte, b []byte) error {
+	select {
+	case <-c.done:
+		return c.Err()
+	default:
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	return writePacket(c.conn, typ, b)
+}
+
+// read reads packets, dispatching published messages to the subscriptions.
+func (c *Client) read(r *bufio.Reader) {
+	for {
+		typ, b, err := readPacket(r)
+		if err != nil {
+			c.close(err)
+			return
+		}
+		if typ&packetTypeMask != packetPublish || len(b) < 2 {
+			continue
+		}
+
+		n := int(binary.BigEndian.Uint16(b))
+		if len(b) < 2+n {
+			continue
+		}
+		topic, payload := string(b[2:2+n]), b[2+n:]
+		if (typ>>1)&0x03 != 0 && len(payload) >= 2 {
+			// packet id, as brokers may downgrade to the subscription QoS
+			payload = payload[2:]
+		}
+
+		c.Lock()
+		var fns []func(string, []byte)
+		for filter, fn := range c.subs {
+			if match(filter, topic) {
+				fns = append(fns, fn)
+			}
+		}
+		c.Unlock()
+		for _, fn := range fns {
+			fn(topic, payload)
+		}
+	}
+}
+
+// ping sends keep alive pings until the connection is closed.
+func (c *Client) ping(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+			if err := c.write(packetPingreq, nil); err != nil {
+				c.close(err)
+				return
+			}
+		}
+	}
+}
+
+// match determines if the topic matches the subscription filter.
+func match(filter, topic string) bool {
+	f, t := strings.Split(filter, "/"), strings.Split(topic, "/")
+	for i, s := range f {
+		switch {
+		case s == "#":
+			return true
+		case i >= len(t):
+			return false
+		case s != "+" && s != t[i]:
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
+
+// appendString appends the length prefixed string s to b.
+func appendString(b []byte, s string) []byte {
+	return append(append(b, byte(len(s)>>8), byte(len(s))), s...)
+}
+
+// writePacket writes a packet with the type and flags, and the body.
+func writePacket(w io.Writer, typ byte, b []byte) error {
+	if len(b) > maxRemainingBytes {
+		return ErrPacketTooLarge
+	}
+	buf := make([]byte, 1, 5+len(b))
+	buf[0] = typ
+	n := len(b)
+	for {
+		d := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			d |= 0x80
+		}
+		buf = append(buf, d)
+		if n == 0 {
+			break
+		}
+	}
+	_, err := w.Write(append(buf, b...))
+	return err
+}
+
+// readPacket reads a packet, returning its type and flags, and its body.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	typ, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var n, mult int
+	for mult = 1; ; mult *= 128 {
+		d, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n += int(d&0x7f) * mult
+		if d&0x80 == 0 {
+			break
+		}
+		if mult > 128*128*128 {
+			return 0, nil, ErrPacketTooLarge
+		}
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return 0, nil, err
+	}
+	return typ, b, nil
+}
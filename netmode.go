@@ -0,0 +1,104 @@
+package hilink
+
+// NetworkMode is the network mode setting, as the preferred radio access
+// technologies in order (ie, "0302" for LTE, then 3G).
+type NetworkMode string
+
+// NetworkMode values.
+const (
+	NetworkModeAuto      NetworkMode = "00"
+	NetworkMode2GOnly    NetworkMode = "01"
+	NetworkMode3GOnly    NetworkMode = "02"
+	NetworkModeLTEOnly   NetworkMode = "03"
+	NetworkModeNROnly    NetworkMode = "08"
+	NetworkMode3G2G      NetworkMode = "0201"
+	NetworkModeLTE3G     NetworkMode = "0302"
+	NetworkModeLTE3G2G   NetworkMode = "030201"
+	NetworkModeNRLTE     NetworkMode = "0803"
+	NetworkModeNRLTE3G2G NetworkMode = "08030201"
+)
+
+// String satisfies the fmt.Stringer interface.
+func (m NetworkMode) String() string {
+	switch m {
+	case NetworkModeAuto:
+		return "auto"
+	case NetworkMode2GOnly:
+		return "2G only"
+	case NetworkMode3GOnly:
+		return "3G only"
+	case NetworkModeLTEOnly:
+		return "LTE only"
+	case NetworkModeNROnly:
+		return "5G only"
+	case NetworkMode3G2G:
+		return "3G/2G"
+	case NetworkModeLTE3G:
+		return "LTE/3G"
+	case NetworkModeLTE3G2G:
+		return "LTE/3G/2G"
+	case NetworkModeNRLTE:
+		return "5G/LTE"
+	case NetworkModeNRLTE3G2G:
+		return "5G/LTE/3G/2G"
+	}
+	return string(m)
+}
+
+// netModeXML is the raw network mode settings.
+type netModeXML struct {
+	NetworkMode string `xml:"NetworkMode"`
+	NetworkBand string `xml:"NetworkBand"`
+	LTEBand     string `xml:"LTEBand"`
+	NRBand      string `xml:"NRBand"`
+}
+
+// modeModify retrieves the network mode settings, modifies them with fn,
+// and sets them.
+func (c *Client) modeModify(fn func(*netModeXML)) (bool, error) {
+	var x netModeXML
+	if err := c.doReqXML("api/net/net-mode", nil, &x); err != nil {
+		return false, err
+	}
+	fn(&x)
+
+	// 5G devices
+	if x.NRBand != "" {
+		return c.ModeSetNR(x.NetworkMode, x.NetworkBand, x.LTEBand, x.NRBand)
+	}
+	return c.ModeSet(x.NetworkMode, x.NetworkBand, x.LTEBand)
+}
+
+// NetworkMode retrieves the network mode setting.
+func (c *Client) NetworkMode() (NetworkMode, error) {
+	var x netModeXML
+	if err := c.doReqXML("api/net/net-mode", nil, &x); err != nil {
+		return "", err
+	}
+	return NetworkMode(x.NetworkMode), nil
+}
+
+// NetworkModeSet sets the network mode, retaining the band settings.
+func (c *Client) NetworkModeSet(m NetworkMode) (bool, error) {
+	return c.modeModify(func(x *netModeXML) {
+		x.NetworkMode = string(m)
+	})
+}
+
+// ModeSetAuto sets the network mode to automatic, retaining the band
+// settings.
+func (c *Client) ModeSetAuto() (bool, error) {
+	return c.NetworkModeSet(NetworkModeAuto)
+}
+
+// ModeSetLTEOnly restricts the network mode to LTE, retaining the band
+// settings.
+func (c *Client) ModeSetLTEOnly() (bool, error) {
+	return c.NetworkModeSet(NetworkModeLTEOnly)
+}
+
+// ModeSet3GOnly restricts the network mode to 3G, retaining the band
+// settings.
+func (c *Client) ModeSet3GOnly() (bool, error) {
+	return c.NetworkModeSet(NetworkMode3GOnly)
+}
@@ -0,0 +1,153 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// OnlineUpdateStatus represents the online update states reported in the
+// device notifications.
+type OnlineUpdateStatus int
+
+// OnlineUpdateStatus values.
+const (
+	// OnlineUpdateStatusChecking is reported while checking for a new
+	// firmware version.
+	OnlineUpdateStatusChecking OnlineUpdateStatus = 10
+
+	// OnlineUpdateStatusCheckFailed is reported when the check failed (ie,
+	// no connection to the update server).
+	OnlineUpdateStatusCheckFailed OnlineUpdateStatus = 11
+
+	// OnlineUpdateStatusNewVersion is reported when a new firmware version
+	// is offered (see FirmwareUpdate).
+	OnlineUpdateStatusNewVersion OnlineUpdateStatus = 12
+
+	// OnlineUpdateStatusUpToDate is reported when no new firmware version
+	// is offered.
+	OnlineUpdateStatusUpToDate OnlineUpdateStatus = 14
+
+	// OnlineUpdateStatusDownloading is reported while the new firmware is
+	// downloading.
+	OnlineUpdateStatusDownloading OnlineUpdateStatus = 30
+
+	// OnlineUpdateStatusDownloadFailed is reported when the download of
+	// the new firmware failed.
+	OnlineUpdateStatusDownloadFailed OnlineUpdateStatus = 31
+
+	// OnlineUpdateStatusDownloaded is reported once the new firmware is
+	// downloaded, and ready to be installed.
+	OnlineUpdateStatusDownloaded OnlineUpdateStatus = 40
+
+	// OnlineUpdateStatusUpdating is reported while the new firmware is
+	// being installed.
+	OnlineUpdateStatusUpdating OnlineUpdateStatus = 50
+)
+
+// String satisfies the fmt.Stringer interface.
+func (s OnlineUpdateStatus) String() string {
+	switch s {
+	case OnlineUpdateStatusChecking:
+		return "checking"
+	case OnlineUpdateStatusCheckFailed:
+		return "check failed"
+	case OnlineUpdateStatusNewVersion:
+		return "new version"
+	case OnlineUpdateStatusUpToDate:
+		return "up to date"
+	case OnlineUpdateStatusDownloading:
+		return "downloading"
+	case OnlineUpdateStatusDownloadFailed:
+		return "download failed"
+	case OnlineUpdateStatusDownloaded:
+		return "downloaded"
+	case OnlineUpdateStatusUpdating:
+		return "updating"
+	}
+	return fmt.Sprintf("OnlineUpdateStatus(%d)", int(s))
+}
+
+// Notifications is the device notification status information.
+type Notifications struct {
+	// UnreadMessages is the number of unread SMS messages.
+	UnreadMessages int `xml:"UnreadMessage"`
+
+	// SmsStorageFull is set when the SMS storage is full, and new messages
+	// can no longer be received.
+	SmsStorageFull bool `xml:"SmsStorageFull"`
+
+	// OnlineUpdateStatus is the online update status. Firmware not
+	// reporting it leaves it zero.
+	OnlineUpdateStatus OnlineUpdateStatus `xml:"OnlineUpdateStatus"`
+}
+
+// Notifications retrieves the device notification status information.
+func (c *Client) Notifications() (*Notifications, error) {
+	return decodeNotifications(c.doReqBody("api/monitoring/check-notifications", nil))
+}
+
+// decodeNotifications decodes the notification status information from a
+// response body.
+func decodeNotifications(body []byte, err error) (*Notifications, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	var n Notifications
+	if err := unmarshalXML(body, &n); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// NotificationWatcher polls the notification status of a device.
+type NotificationWatcher struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Interval is the poll interval. Defaults to 30 seconds.
+	Interval time.Duration
+
+	// OnNotifications is called when the notification status changes.
+	OnNotifications func(Notifications)
+
+	// OnError is called when the notification status cannot be retrieved.
+	OnError func(error)
+
+	last *Notifications
+}
+
+// Run runs the watcher until the context is closed.
+func (w *NotificationWatcher) Run(ctx context.Context) error {
+	if w.Client == nil {
+		return ErrNilClient
+	}
+
+	interval := w.Interval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		n, err := w.Client.Notifications()
+		switch {
+		case err != nil && w.OnError != nil:
+			w.OnError(err)
+		case err == nil && (w.last == nil || *n != *w.last):
+			w.last = n
+			if w.OnNotifications != nil {
+				w.OnNotifications(*n)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
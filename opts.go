@@ -1,13 +1,13 @@
 package hilink
 
 import (
-	"crypto/sha256"
-	"encoding/base64"
-	"encoding/hex"
+	"context"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // Option is an option used when creating a new Client.
@@ -31,22 +31,51 @@ func URL(rawurl string) Option {
 
 // Auth is an option specifying the identifier and password to use.
 // The option is ignored if id is an empty string.
+//
+// The password is kept as given, and hashed at login, as changing the
+// password (see PasswordChange) requires the current password.
 func Auth(id, pw string) Option {
 	return func(c *Client) error {
 		if id != "" {
 			c.authID = id
-			h := sha256.Sum256([]byte(pw))
-			c.authPW = id + base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(h[:])))
+			c.authPW = pw
 		}
 		return nil
 	}
 }
 
 // HTTPClient is an option that allows setting the http.Client used by the
-// Client.
+// Client. A shallow copy of client is used, so that the transport options
+// (ie, Proxy, MaxConnsPerHost), the Log option and the session cookie jar do
+// not modify it.
 func HTTPClient(client *http.Client) Option {
 	return func(c *Client) error {
-		c.client = client
+		if client == nil {
+			return ErrNilClient
+		}
+		hc := *client
+		c.client = &hc
+		return nil
+	}
+}
+
+// CookieJar is an option that sets the http.CookieJar used to store the
+// session with the Hilink device, allowing a session to be shared between
+// processes or persisted (ie, with a file backed jar) across restarts. When
+// not specified, a new in-memory jar is created for each session.
+func CookieJar(jar http.CookieJar) Option {
+	return func(c *Client) error {
+		c.jar = jar
+		return nil
+	}
+}
+
+// Location is an option that sets the time zone location of the device, used
+// when parsing date/time values reported by the device. When not specified,
+// the time zone is retrieved from the device.
+func Location(loc *time.Location) Option {
+	return func(c *Client) error {
+		c.loc = loc
 		return nil
 	}
 }
@@ -58,6 +87,188 @@ func NoSessionStart(c *Client) error {
 	return nil
 }
 
+// PinGuard is an option that causes PIN operations (ie, PinEnter, PinChange,
+// PinEnterPuk, etc) to be refused when only one PIN or PUK attempt remains on
+// the SIM, protecting against accidental PUK lockouts. Use PinEnterForce to
+// explicitly override the guard.
+func PinGuard(c *Client) error {
+	c.pinguard = true
+	return nil
+}
+
+// EnableATCommands is an option that allows AT commands to be sent with
+// ATCommand. AT commands can reconfigure or brick the device, and are
+// refused unless this option is provided.
+func EnableATCommands(c *Client) error {
+	c.atcmd = true
+	return nil
+}
+
+// transportOption creates an option that applies f to the http.Transport used
+// by the Client. The transport options are applied, in order, after all other
+// options have been processed.
+func transportOption(f func(*http.Transport)) Option {
+	return func(c *Client) error {
+		c.transportOpts = append(c.transportOpts, f)
+		return nil
+	}
+}
+
+// applyTransportOpts applies the transport options to a copy of the
+// http.Transport used by the Client's http.Client (or http.DefaultTransport
+// when not set), skipping over the transport injected by the Log option.
+func (c *Client) applyTransportOpts() error {
+	if len(c.transportOpts) == 0 {
+		return nil
+	}
+
+	rt := &c.client.Transport
+	if hl, ok := c.client.Transport.(*httpLogger); ok {
+		rt = &hl.transport
+	}
+
+	var t *http.Transport
+	switch x := (*rt).(type) {
+	case nil:
+		t = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		t = x.Clone()
+	default:
+		return ErrUnsupportedTransport
+	}
+
+	for _, f := range c.transportOpts {
+		f(t)
+	}
+	*rt = t
+
+	return nil
+}
+
+// Proxy is an option that sets the outbound proxy used to reach the Hilink
+// device. The proxy URL may use the http, https or socks5 scheme. Requests to
+// hosts matching an entry in noProxy (using the same rules as the NO_PROXY
+// environment variable) bypass the proxy.
+func Proxy(rawurl string, noProxy ...string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return err
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return ErrInvalidProxyURL
+		}
+
+		np := strings.Join(noProxy, ",")
+		return transportOption(func(t *http.Transport) {
+			t.Proxy = func(req *http.Request) (*url.URL, error) {
+				if matchNoProxy(np, req.URL.Host) {
+					return nil, nil
+				}
+				return u, nil
+			}
+		})(c)
+	}
+}
+
+// ProxyFromEnvironment is an option that uses the proxy configured by the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables (see
+// http.ProxyFromEnvironment).
+func ProxyFromEnvironment(c *Client) error {
+	return transportOption(func(t *http.Transport) {
+		t.Proxy = http.ProxyFromEnvironment
+	})(c)
+}
+
+// DialContext is an option that sets the dial function used to establish
+// connections to the Hilink device, allowing connections to be bound to a
+// specific local address, or made over a custom network (ie, a VPN or tunnel).
+func DialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return transportOption(func(t *http.Transport) {
+		t.DialContext = dial
+	})
+}
+
+// Host is an option that overrides the Host header sent to the Hilink device,
+// for use when the device is reached through a tunnel or port forward (ie,
+// ssh -L 8080:192.168.8.1:80), as the WebUI may reject requests for an
+// unexpected host.
+func Host(host string) Option {
+	return func(c *Client) error {
+		c.host = host
+		return nil
+	}
+}
+
+// TunnelDialer is an option that establishes all connections to the Hilink
+// device using dial (ie, over an existing SSH or WireGuard session),
+// regardless of the URL host. Proxies are not used with a tunnel.
+func TunnelDialer(dial func(ctx context.Context) (net.Conn, error)) Option {
+	return transportOption(func(t *http.Transport) {
+		t.Proxy = nil
+		t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dial(ctx)
+		}
+	})
+}
+
+// matchNoProxy determines if hostport matches an entry of the comma separated
+// noProxy list. Entries may be "*", a host name (also matching its
+// subdomains), a domain with a leading ".", an IP address, or a CIDR, and may
+// optionally include a port.
+func matchNoProxy(noProxy, hostport string) bool {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host, port = hostport, ""
+	}
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, e := range strings.Split(noProxy, ",") {
+		e = strings.ToLower(strings.TrimSpace(e))
+		switch {
+		case e == "":
+			continue
+		case e == "*":
+			return true
+		}
+
+		// cidr
+		if _, n, err := net.ParseCIDR(e); err == nil {
+			if ip != nil && n.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		// split port
+		if h, p, err := net.SplitHostPort(e); err == nil {
+			if p != port {
+				continue
+			}
+			e = h
+		}
+
+		// ip
+		if eip := net.ParseIP(e); eip != nil {
+			if eip.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		// domain
+		e = strings.TrimPrefix(e, ".")
+		if host == e || strings.HasSuffix(host, "."+e) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // httpLogger handles logging http requests and responses.
 type httpLogger struct {
 	transport                 http.RoundTripper
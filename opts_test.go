@@ -0,0 +1,31 @@
+package hilink
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHTTPClientCopied(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+
+	transport := NewTransport()
+	hc := &http.Client{Transport: transport}
+	_, err := NewClient(
+		URL(d.URL+"/"),
+		HTTPClient(hc),
+		MaxConnsPerHost(2),
+		Log(func(string, ...interface{}) {}, func(string, ...interface{}) {}),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// the caller's client and transport are left unchanged
+	if hc.Transport != transport || hc.Jar != nil {
+		t.Errorf("expected client unchanged, got: %+v", hc)
+	}
+	if transport.MaxConnsPerHost != DefaultMaxConnsPerHost {
+		t.Errorf("expected transport unchanged, got: %d", transport.MaxConnsPerHost)
+	}
+}
@@ -0,0 +1,56 @@
+package hilink
+
+// SimState represents the SIM states reported by the device.
+type SimState int
+
+// SimState values.
+const (
+	SimStateNoSim        SimState = 255
+	SimStateCpinFail     SimState = 256
+	SimStateReady        SimState = 257
+	SimStatePinDisabled  SimState = 258
+	SimStatePinValidated SimState = 259
+	SimStatePinRequired  SimState = 260
+	SimStatePukRequired  SimState = 261
+)
+
+// PinStatus is the SIM PIN status information.
+type PinStatus struct {
+	SimState    SimState `xml:"SimState"`
+	PinOptState int      `xml:"PinOptState"`
+
+	// PinTimes and PukTimes are the remaining PIN and PUK attempts.
+	PinTimes int `xml:"SimPinTimes"`
+	PukTimes int `xml:"SimPukTimes"`
+}
+
+// PinStatus retrieves the SIM PIN status, including the remaining PIN and PUK
+// attempts.
+func (c *Client) PinStatus() (*PinStatus, error) {
+	var s PinStatus
+	if err := c.doReqXML("api/pin/status", nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// checkPinAttempts refuses PIN operations that would use up the last
+// remaining PIN (or PUK) attempt.
+func (c *Client) checkPinAttempts(pt PinType) error {
+	s, err := c.PinStatus()
+	if err != nil {
+		return err
+	}
+
+	if pt == PinTypeEnterPuk {
+		if s.PukTimes == 1 {
+			return ErrPukLastAttempt
+		}
+		return nil
+	}
+
+	if s.PinTimes == 1 {
+		return ErrPinLastAttempt
+	}
+	return nil
+}
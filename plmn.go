@@ -0,0 +1,135 @@
+package hilink
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// NetworkScanTimeout is the request timeout of network scans, which take
+// up to a few minutes on most devices.
+const NetworkScanTimeout = 3 * time.Minute
+
+// slowEndpoints are the long running endpoints, and their request timeouts.
+var slowEndpoints = map[string]time.Duration{
+	"api/net/plmn-list": NetworkScanTimeout,
+}
+
+// RAT is a radio access technology, as used for operator selection.
+type RAT int
+
+// RAT values.
+const (
+	RATGSM  RAT = 0
+	RATUMTS RAT = 2
+	RATLTE  RAT = 7
+)
+
+// String satisfies the fmt.Stringer interface.
+func (r RAT) String() string {
+	switch r {
+	case RATGSM:
+		return "GSM"
+	case RATUMTS:
+		return "UMTS"
+	case RATLTE:
+		return "LTE"
+	}
+	return strconv.Itoa(int(r))
+}
+
+// PlmnState is the state of an operator network found by a network scan.
+type PlmnState int
+
+// PlmnState values.
+const (
+	PlmnUnknown PlmnState = iota
+	PlmnAvailable
+	PlmnCurrent
+	PlmnForbidden
+)
+
+// String satisfies the fmt.Stringer interface.
+func (s PlmnState) String() string {
+	switch s {
+	case PlmnAvailable:
+		return "available"
+	case PlmnCurrent:
+		return "current"
+	case PlmnForbidden:
+		return "forbidden"
+	}
+	return "unknown"
+}
+
+// Plmn is an operator network found by a network scan.
+type Plmn struct {
+	// Numeric is the PLMN, as MCC and MNC (ie, "26201").
+	Numeric string
+
+	// FullName and ShortName are the operator names.
+	FullName, ShortName string
+
+	// RAT is the radio access technology of the network.
+	RAT RAT
+
+	// State is the network state.
+	State PlmnState
+}
+
+// plmnXML is the raw network scan entry.
+type plmnXML struct {
+	Numeric   string `xml:"Numeric"`
+	FullName  string `xml:"FullName"`
+	ShortName string `xml:"ShortName"`
+	Rat       string `xml:"Rat"`
+	State     string `xml:"State"`
+}
+
+// NetworkScan scans for the available operator networks. Scans take up to
+// NetworkScanTimeout, and devices generally drop the data connection while
+// scanning.
+func (c *Client) NetworkScan() ([]Plmn, error) {
+	var res struct {
+		Networks []plmnXML `xml:"Networks>Network"`
+	}
+	if err := c.doReqXML("api/net/plmn-list", nil, &res); err != nil {
+		return nil, err
+	}
+
+	networks := make([]Plmn, len(res.Networks))
+	for i, x := range res.Networks {
+		networks[i] = Plmn{
+			Numeric:   x.Numeric,
+			FullName:  x.FullName,
+			ShortName: x.ShortName,
+			RAT:       RAT(parseUint(x.Rat)),
+			State:     PlmnState(parseUint(x.State)),
+		}
+	}
+	return networks, nil
+}
+
+// NetworkRegister manually registers with the operator network plmn (MCC
+// and MNC, ie "26201"), using the radio access technology, as found by
+// NetworkScan. The selection persists until NetworkRegisterAuto is called.
+func (c *Client) NetworkRegister(plmn string, rat RAT) (bool, error) {
+	if (len(plmn) != 5 && len(plmn) != 6) || !isDigits(plmn) {
+		return false, fmt.Errorf("%w %q", ErrInvalidPlmn, plmn)
+	}
+
+	return c.doReqCheckOK("api/net/register", SimpleRequestXML(
+		"Mode", "1",
+		"Plmn", plmn,
+		"Rat", strconv.Itoa(int(rat)),
+	))
+}
+
+// NetworkRegisterAuto returns to automatic operator network selection.
+func (c *Client) NetworkRegisterAuto() (bool, error) {
+	return c.doReqCheckOK("api/net/register", SimpleRequestXML(
+		"Mode", "0",
+		"Plmn", "",
+		"Rat", "",
+	))
+}
@@ -0,0 +1,288 @@
+package hilink
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Condition determines if a condition currently holds for a device.
+type Condition func(ctx context.Context, c *Client) (bool, error)
+
+// Action is a named remediation action performed on a device.
+type Action struct {
+	Name string
+	Do   func(ctx context.Context, c *Client) error
+}
+
+// PolicyRule is a remediation rule of a PolicyEngine: when the When
+// condition holds for the For duration, the Then action is performed.
+type PolicyRule struct {
+	// Name is the rule name.
+	Name string
+
+	// When is the rule condition.
+	When Condition
+
+	// For is the duration the condition must hold before the action is
+	// performed.
+	For time.Duration
+
+	// Then is the action to perform.
+	Then Action
+
+	// Cooldown is the minimum duration between two performances of the
+	// action.
+	Cooldown time.Duration
+}
+
+// PolicyEngine evaluates remediation rules against a device, for example:
+//
+//	&PolicyEngine{
+//		Client: client,
+//		Rules: []PolicyRule{
+//			{Name: "poor signal", When: SignalPoor(), For: 5 * time.Minute, Then: ActionReconnect},
+//			{Name: "no wan", When: NoWAN(), For: 10 * time.Minute, Then: ActionReboot},
+//			{Name: "data cap", When: DataUsageAbove(95), Then: ActionMobileDataDisable},
+//		},
+//	}
+//
+// The engine can be run on its own with Run, or evaluated from another loop
+// (ie, a watchdog) with Evaluate.
+type PolicyEngine struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Rules are the remediation rules.
+	Rules []PolicyRule
+
+	// Interval is the evaluation interval. Defaults to 30 seconds.
+	Interval time.Duration
+
+	// DryRun logs the actions that would be performed, without performing
+	// them.
+	DryRun bool
+
+	// Logf is the audit logger (ie, log.Printf), recording condition changes
+	// and performed actions.
+	Logf func(string, ...interface{})
+
+	rules map[int]*policyState
+}
+
+// policyState is the evaluation state of a rule.
+type policyState struct {
+	since time.Time
+	last  time.Time
+}
+
+// Run evaluates the rules every interval until the context is closed.
+func (e *PolicyEngine) Run(ctx context.Context) error {
+	if e.Client == nil {
+		return ErrNilClient
+	}
+
+	interval := e.Interval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		e.Evaluate(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Evaluate evaluates the rules once, performing the actions of the rules
+// whose conditions have held for long enough.
+func (e *PolicyEngine) Evaluate(ctx context.Context, now time.Time) {
+	if e.rules == nil {
+		e.rules = make(map[int]*policyState)
+	}
+
+	for i, r := range e.Rules {
+		st, ok := e.rules[i]
+		if !ok {
+			st = new(policyState)
+			e.rules[i] = st
+		}
+
+		held, err := r.When(ctx, e.Client)
+		if err != nil {
+			e.logf("policy: rule %q: condition error: %v", r.Name, err)
+			continue
+		}
+
+		if !held {
+			if !st.since.IsZero() {
+				e.logf("policy: rule %q: condition cleared", r.Name)
+			}
+			st.since = time.Time{}
+			continue
+		}
+
+		if st.since.IsZero() {
+			e.logf("policy: rule %q: condition holds", r.Name)
+			st.since = now
+		}
+		if now.Sub(st.since) < r.For || (!st.last.IsZero() && now.Sub(st.last) < r.Cooldown) {
+			continue
+		}
+
+		st.last = now
+		if e.DryRun {
+			e.logf("policy: rule %q: dry-run: would perform %s", r.Name, r.Then.Name)
+			continue
+		}
+		if err := r.Then.Do(ctx, e.Client); err != nil {
+			e.logf("policy: rule %q: %s failed: %v", r.Name, r.Then.Name, err)
+			continue
+		}
+		e.logf("policy: rule %q: performed %s", r.Name, r.Then.Name)
+	}
+}
+
+// logf writes to the audit log.
+func (e *PolicyEngine) logf(s string, v ...interface{}) {
+	if e.Logf != nil {
+		e.Logf(s, v...)
+	}
+}
+
+// SignalPoor is a Condition that holds when the overall signal quality (see
+// QualityScore) is poor.
+func SignalPoor() Condition {
+	return func(ctx context.Context, c *Client) (bool, error) {
+		s, err := c.Signal()
+		if err != nil {
+			return false, err
+		}
+		return QualityScore(*s).Overall == SignalQualityPoor, nil
+	}
+}
+
+// NoWAN is a Condition that holds when the device is not connected.
+func NoWAN() Condition {
+	return func(ctx context.Context, c *Client) (bool, error) {
+		s, err := c.Status()
+		if err != nil {
+			return false, err
+		}
+		return s.ConnectionStatus != ConnectionStatusConnected, nil
+	}
+}
+
+// DataUsageAbove is a Condition that holds when the month data usage is
+// above pct percent of the monthly data limit configured on the device.
+func DataUsageAbove(pct float64) Condition {
+	return func(ctx context.Context, c *Client) (bool, error) {
+		used, limit, err := c.monthUsage()
+		if err != nil || limit == 0 {
+			return false, err
+		}
+		return float64(used) > float64(limit)*pct/100, nil
+	}
+}
+
+// monthUsage returns the month data usage and the monthly data limit, in
+// bytes.
+func (c *Client) monthUsage() (uint64, uint64, error) {
+	var m struct {
+		CurrentMonthDownload string `xml:"CurrentMonthDownload"`
+		CurrentMonthUpload   string `xml:"CurrentMonthUpload"`
+	}
+	if err := c.doReqXML("api/monitoring/month_statistics", nil, &m); err != nil {
+		return 0, 0, err
+	}
+
+	var x startDateXML
+	if err := c.doReqXML("api/monitoring/start_date", nil, &x); err != nil {
+		return 0, 0, err
+	}
+
+	return parseUint(m.CurrentMonthDownload) + parseUint(m.CurrentMonthUpload), parseDataSize(x.DataLimit), nil
+}
+
+// parseDataSize parses a data size as used by the WebUI (ie, "500MB",
+// "10GB"), returning 0 if s cannot be parsed.
+func parseDataSize(s string) uint64 {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	mult := uint64(1)
+	for _, u := range []struct {
+		suffix string
+		mult   uint64
+	}{
+		{"TB", 1 << 40},
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	} {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mult = strings.TrimSuffix(s, u.suffix), u.mult
+			break
+		}
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f < 0 {
+		return 0
+	}
+	return uint64(f * float64(mult))
+}
+
+// Action values.
+var (
+	// ActionReconnect disconnects and reconnects the mobile connection.
+	ActionReconnect = Action{
+		Name: "reconnect",
+		Do: func(ctx context.Context, c *Client) error {
+			if err := checkOK(c.Disconnect()); err != nil {
+				return err
+			}
+			return checkOK(c.Connect())
+		},
+	}
+
+	// ActionMobileDataToggle disables and enables the mobile data switch.
+	ActionMobileDataToggle = Action{
+		Name: "toggle mobile data",
+		Do: func(ctx context.Context, c *Client) error {
+			if err := checkOK(c.MobileDataSet(false)); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+			}
+			return checkOK(c.MobileDataSet(true))
+		},
+	}
+
+	// ActionReboot reboots the device.
+	ActionReboot = Action{
+		Name: "reboot",
+		Do: func(ctx context.Context, c *Client) error {
+			return checkOK(c.DeviceReboot())
+		},
+	}
+
+	// ActionMobileDataDisable disables the mobile data switch.
+	ActionMobileDataDisable = Action{
+		Name: "disable mobile data",
+		Do: func(ctx context.Context, c *Client) error {
+			return checkOK(c.MobileDataSet(false))
+		},
+	}
+)
@@ -0,0 +1,83 @@
+package hilink
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"sort"
+)
+
+// probeEndpoints are the endpoints probed in addition to the snapshot
+// endpoints. Only endpoints that are safe to GET are listed (ie, not the
+// PLMN scan, which drops the connection).
+var probeEndpoints = []string{
+	"device/reboot-schedule",
+	"dhcp/static-addr-info",
+	"lan/HostInfo",
+	"pin/save-pin",
+	"security/virtual-servers",
+	"sms/send-status",
+	"timerule/timerule",
+	"user/state-login",
+	"ussd/status",
+	"webserver/SesTokInfo",
+	"wlan/host-list",
+	"wlan/multi-macfilter-settings",
+	"wlan/security-settings",
+}
+
+// EndpointProbe is the probe result of an endpoint.
+type EndpointProbe struct {
+	// Endpoint is the probed endpoint (ie, "device/signal").
+	Endpoint string `json:"endpoint"`
+
+	// Status is the HTTP status code.
+	Status int `json:"status,omitempty"`
+
+	// Code is the error code returned by the device, if any.
+	Code string `json:"code,omitempty"`
+
+	// Err is the request error, if any.
+	Err error `json:"-"`
+}
+
+// Supported determines if the endpoint is implemented by the firmware.
+// Endpoints returning an error other than "not supported" (ie,
+// unauthorized) are considered implemented.
+func (p EndpointProbe) Supported() bool {
+	return p.Err == nil && p.Status == http.StatusOK && p.Code != "100002"
+}
+
+// ProbeEndpoints issues a GET against each known read endpoint, reporting
+// which endpoints the firmware implements. The response data is discarded.
+func (c *Client) ProbeEndpoints(ctx context.Context) ([]EndpointProbe, error) {
+	endpoints := append(snapshotEndpoints(), probeEndpoints...)
+	sort.Strings(endpoints)
+
+	probes := make([]EndpointProbe, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if err := ctx.Err(); err != nil {
+			return probes, err
+		}
+
+		p := EndpointProbe{Endpoint: endpoint}
+
+		var body []byte
+		p.Status, body, p.Err = c.doReqStatus("api/"+endpoint, nil)
+		if p.Err == nil && p.Status == http.StatusOK {
+			var e struct {
+				XMLName xml.Name
+				Code    string `xml:"code"`
+			}
+			if err := xml.Unmarshal(body, &e); err != nil {
+				p.Err = err
+			} else if e.XMLName.Local == "error" {
+				p.Code = e.Code
+			}
+		}
+
+		probes = append(probes, p)
+	}
+
+	return probes, nil
+}
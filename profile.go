@@ -0,0 +1,162 @@
+package hilink
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AuthMode represents the authentication modes of a connection profile.
+type AuthMode int
+
+// AuthMode values.
+const (
+	AuthModeAuto AuthMode = iota
+	AuthModePAP
+	AuthModeCHAP
+)
+
+// String satisfies the fmt.Stringer interface.
+func (m AuthMode) String() string {
+	switch m {
+	case AuthModePAP:
+		return "PAP"
+	case AuthModeCHAP:
+		return "CHAP"
+	}
+	return "auto"
+}
+
+// IPType represents the IP (PDP) types of a connection profile.
+type IPType int
+
+// IPType values.
+const (
+	IPTypeIPv4 IPType = iota
+	IPTypeIPv6
+	IPTypeIPv4v6
+)
+
+// String satisfies the fmt.Stringer interface.
+func (t IPType) String() string {
+	switch t {
+	case IPTypeIPv6:
+		return "IPv6"
+	case IPTypeIPv4v6:
+		return "IPv4v6"
+	}
+	return "IPv4"
+}
+
+// DefaultDialNumber is the dial number used by profiles without one.
+const DefaultDialNumber = "*99#"
+
+// Profile is a connection (APN) profile.
+type Profile struct {
+	// Index is the profile index, assigned by the device.
+	Index int `xml:"Index"`
+
+	// Name is the profile name.
+	Name string `xml:"Name"`
+
+	// APN is the access point name. When empty, the APN is assigned by the
+	// network.
+	APN string `xml:"ApnName"`
+
+	// DialNumber is the dial number. Defaults to DefaultDialNumber.
+	DialNumber string `xml:"DialupNum"`
+
+	// Username and Password are the APN credentials.
+	Username string `xml:"Username"`
+	Password string `xml:"Password"`
+
+	// AuthMode is the authentication mode of the credentials.
+	AuthMode AuthMode `xml:"AuthMode"`
+
+	// IPAddress is the static IP address. When empty, the address is
+	// assigned by the network.
+	IPAddress string `xml:"IpAddress"`
+
+	// PrimaryDNS and SecondaryDNS are the static DNS servers. When empty,
+	// the servers are assigned by the network.
+	PrimaryDNS   string `xml:"PrimaryDns"`
+	SecondaryDNS string `xml:"SecondaryDns"`
+
+	// IPType is the requested IP (PDP) type. Many carriers require
+	// IPTypeIPv4v6.
+	IPType IPType `xml:"iptype"`
+
+	// ReadOnly is the operator provided (non-editable) profile flag.
+	ReadOnly bool `xml:"ReadOnly"`
+}
+
+// Validate validates the IP type, and the static addresses of the profile.
+func (p Profile) Validate() error {
+	if p.IPType < IPTypeIPv4 || p.IPType > IPTypeIPv4v6 {
+		return fmt.Errorf("%w: ip type %d", ErrInvalidValue, int(p.IPType))
+	}
+	for _, ip := range []string{p.IPAddress, p.PrimaryDNS, p.SecondaryDNS} {
+		if ip == "" {
+			continue
+		}
+		if err := ValidateIPv4Address(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// xml returns the profile as a request element.
+func (p Profile) xml() map[string]interface{} {
+	index := ""
+	if p.Index != 0 {
+		index = strconv.Itoa(p.Index)
+	}
+	dialNumber := p.DialNumber
+	if dialNumber == "" {
+		dialNumber = DefaultDialNumber
+	}
+
+	return map[string]interface{}{
+		"Index":        index,
+		"IsValid":      "1",
+		"Name":         p.Name,
+		"ApnIsStatic":  boolToString(p.APN != ""),
+		"ApnName":      p.APN,
+		"DialupNum":    dialNumber,
+		"Username":     p.Username,
+		"Password":     p.Password,
+		"AuthMode":     strconv.Itoa(int(p.AuthMode)),
+		"IpIsStatic":   boolToString(p.IPAddress != ""),
+		"IpAddress":    p.IPAddress,
+		"DnsIsStatic":  boolToString(p.PrimaryDNS != "" || p.SecondaryDNS != ""),
+		"PrimaryDns":   p.PrimaryDNS,
+		"SecondaryDns": p.SecondaryDNS,
+		"ReadOnly":     boolToString(p.ReadOnly),
+		"iptype":       strconv.Itoa(int(p.IPType)),
+	}
+}
+
+// newProfileXML returns the request element of a new profile with the
+// values v, deriving the static flags from the values.
+func newProfileXML(v Resource) map[string]interface{} {
+	p := Profile{
+		Name:         v["Name"],
+		APN:          v["ApnName"],
+		IPAddress:    v["IpAddress"],
+		PrimaryDNS:   v["PrimaryDns"],
+		SecondaryDNS: v["SecondaryDns"],
+	}
+	return mergeXML(p.xml(), v.xml())
+}
+
+// profileReq builds a connection profile request. When adding a profile
+// (modify "1"), setDefault is the flag making the profile the default,
+// otherwise it is the index of the default profile.
+func profileReq(modify, setDefault string, profile map[string]interface{}) XMLData {
+	return XMLData{
+		"Delete":     "0",
+		"SetDefault": setDefault,
+		"Modify":     modify,
+		"Profile":    profile,
+	}
+}
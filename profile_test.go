@@ -0,0 +1,132 @@
+package hilink
+
+import (
+	"testing"
+)
+
+func TestProfileAdd(t *testing.T) {
+	tests := []struct {
+		name       string
+		profile    Profile
+		setDefault bool
+		exp        map[string]string
+	}{
+		{
+			name: "pap",
+			profile: Profile{
+				Name:     "pap",
+				APN:      "internet",
+				Username: "user",
+				Password: "pass",
+				AuthMode: AuthModePAP,
+			},
+			setDefault: true,
+			exp: map[string]string{
+				"SetDefault":  "1",
+				"Modify":      "1",
+				"Index":       "",
+				"Name":        "pap",
+				"ApnIsStatic": "1",
+				"ApnName":     "internet",
+				"DialupNum":   DefaultDialNumber,
+				"Username":    "user",
+				"Password":    "pass",
+				"AuthMode":    "1",
+				"IpIsStatic":  "0",
+				"DnsIsStatic": "0",
+				"iptype":      "0",
+			},
+		},
+		{
+			name: "chap",
+			profile: Profile{
+				Index:      3,
+				Name:       "chap",
+				DialNumber: "*99***1#",
+				AuthMode:   AuthModeCHAP,
+				IPType:     IPTypeIPv4v6,
+			},
+			exp: map[string]string{
+				"SetDefault":  "0",
+				"Modify":      "1",
+				"Index":       "",
+				"ApnIsStatic": "0",
+				"DialupNum":   "*99***1#",
+				"AuthMode":    "2",
+				"iptype":      "2",
+			},
+		},
+		{
+			name: "static",
+			profile: Profile{
+				Name:         "static",
+				IPType:       IPTypeIPv6,
+				IPAddress:    "10.0.0.2",
+				PrimaryDNS:   "1.1.1.1",
+				SecondaryDNS: "8.8.8.8",
+			},
+			exp: map[string]string{
+				"SetDefault":   "0",
+				"AuthMode":     "0",
+				"IpIsStatic":   "1",
+				"IpAddress":    "10.0.0.2",
+				"DnsIsStatic":  "1",
+				"PrimaryDns":   "1.1.1.1",
+				"SecondaryDns": "8.8.8.8",
+				"iptype":       "1",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := newFakeDevice()
+			defer d.Close()
+
+			ok, err := d.client(t).ProfileAdd(test.profile, test.setDefault)
+			if err != nil {
+				t.Fatalf("expected no error, got: %v", err)
+			}
+			if !ok {
+				t.Fatal("expected ok")
+			}
+
+			// a single request, leaving the default unchanged unless set
+			if n := len(d.requests["/api/dialup/profiles"]); n != 1 {
+				t.Fatalf("expected 1 request, got: %d", n)
+			}
+			req := d.request(t, "/api/dialup/profiles")
+			if req["Delete"] != "0" {
+				t.Errorf("expected Delete 0, got: %v", req["Delete"])
+			}
+			p, ok := req["Profile"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected Profile element, got: %v", req)
+			}
+			for k, exp := range test.exp {
+				v, ok := req[k]
+				if !ok {
+					v = p[k]
+				}
+				if v != exp {
+					t.Errorf("expected %s %q, got: %v", k, exp, v)
+				}
+			}
+		})
+	}
+}
+
+func TestProfileAddInvalid(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+
+	tests := []Profile{
+		{Name: "ip", IPAddress: "10.0.0"},
+		{Name: "dns", PrimaryDNS: "example.com"},
+		{Name: "iptype", IPType: IPType(3)},
+	}
+	for _, p := range tests {
+		if _, err := d.client(t).ProfileAdd(p, false); err == nil {
+			t.Errorf("%s: expected error", p.Name)
+		}
+	}
+}
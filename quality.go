@@ -0,0 +1,116 @@
+package hilink
+
+import (
+	"math"
+)
+
+// SignalQuality represents the signal quality bands.
+type SignalQuality int
+
+// SignalQuality values.
+const (
+	SignalQualityUnknown SignalQuality = iota
+	SignalQualityPoor
+	SignalQualityFair
+	SignalQualityGood
+	SignalQualityExcellent
+)
+
+// String satisfies the fmt.Stringer interface.
+func (q SignalQuality) String() string {
+	switch q {
+	case SignalQualityPoor:
+		return "poor"
+	case SignalQualityFair:
+		return "fair"
+	case SignalQualityGood:
+		return "good"
+	case SignalQualityExcellent:
+		return "excellent"
+	}
+	return "unknown"
+}
+
+// Quality is the signal quality assessment of a Signal.
+type Quality struct {
+	// RSRP, RSRQ and SINR are the bands of the individual values.
+	RSRP SignalQuality
+	RSRQ SignalQuality
+	SINR SignalQuality
+
+	// Overall is the band of the composite Score.
+	Overall SignalQuality
+
+	// Score is the composite score, from 0 (no usable signal) to 100.
+	Score int
+}
+
+// QualityScore assesses the signal quality using the commonly used LTE
+// bands:
+//
+//	           RSRP (dBm)   RSRQ (dB)   SINR (dB)
+//	excellent  >= -80       >= -10      >= 20
+//	good       -80 to -90   -10 to -15  13 to 20
+//	fair       -90 to -100  -15 to -20  0 to 13
+//	poor       < -100       < -20       < 0
+//
+// The composite score weights RSRP and SINR at 40% each, and RSRQ at 20%,
+// each scaled linearly over RSRP -120 to -70 dBm, RSRQ -20 to -5 dB, and SINR
+// -5 to 25 dB.
+//
+// The 5G NR values are used when the LTE values are not reported (ie, in
+// standalone mode).
+func QualityScore(s Signal) Quality {
+	rsrp, rsrq, sinr := s.RSRP, s.RSRQ, s.SINR
+	if rsrp == 0 {
+		rsrp, rsrq, sinr = s.NRRSRP, s.NRRSRQ, s.NRSINR
+	}
+
+	// no signal reported
+	if rsrp == 0 {
+		return Quality{}
+	}
+
+	q := Quality{
+		RSRP: band(rsrp, -80, -90, -100),
+		SINR: band(sinr, 20, 13, 0),
+	}
+
+	score, weight := 40*scale(rsrp, -120, -70)+40*scale(sinr, -5, 25), 80.0
+	if rsrq != 0 {
+		q.RSRQ = band(rsrq, -10, -15, -20)
+		score, weight = score+20*scale(rsrq, -20, -5), weight+20
+	}
+	q.Score = int(math.Round(100 * score / weight))
+
+	switch {
+	case q.Score >= 75:
+		q.Overall = SignalQualityExcellent
+	case q.Score >= 50:
+		q.Overall = SignalQualityGood
+	case q.Score >= 25:
+		q.Overall = SignalQualityFair
+	default:
+		q.Overall = SignalQualityPoor
+	}
+
+	return q
+}
+
+// band returns the signal quality band for v.
+func band(v, excellent, good, fair float64) SignalQuality {
+	switch {
+	case v >= excellent:
+		return SignalQualityExcellent
+	case v >= good:
+		return SignalQualityGood
+	case v >= fair:
+		return SignalQualityFair
+	}
+	return SignalQualityPoor
+}
+
+// scale scales v linearly from min..max to 0..1, clamping the result.
+func scale(v, min, max float64) float64 {
+	return math.Max(0, math.Min(1, (v-min)/(max-min)))
+}
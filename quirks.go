@@ -0,0 +1,183 @@
+package hilink
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Quirks are the known deviations of a device model or firmware from the
+// common HiLink API.
+type Quirks struct {
+	// Paths remaps endpoint paths (ie, "api/net/net-mode" to a model
+	// specific path).
+	Paths map[string]string
+
+	// Broken are the endpoints known to be broken or missing, which fail
+	// without sending a request.
+	Broken []string
+
+	// Encrypted are the endpoints requiring RSA encrypted request bodies
+	// (see RSAEncryption).
+	Encrypted []string
+
+	// PasswordType is the login password type: 3 (base64 encoded) or 4
+	// (hashed with the token). Defaults to 4.
+	PasswordType int
+
+	// StaticToken prevents the CSRF token from being updated from response
+	// headers, for firmware that issues tokens only with the session.
+	StaticToken bool
+}
+
+// broken determines if the endpoint path is known to be broken.
+func (q *Quirks) broken(path string) bool {
+	for _, b := range q.Broken {
+		if b == path {
+			return true
+		}
+	}
+	return false
+}
+
+// path returns the remapped endpoint path.
+func (q *Quirks) path(path string) string {
+	if p, ok := q.Paths[path]; ok {
+		return p
+	}
+	return path
+}
+
+// quirksEntry is a quirks registry entry.
+type quirksEntry struct {
+	model    string
+	firmware string
+	quirks   Quirks
+}
+
+// match determines if the entry matches the upper case device model and
+// the firmware version.
+func (e *quirksEntry) match(model, firmware string) bool {
+	return strings.HasPrefix(model, strings.ToUpper(e.model)) && strings.HasPrefix(firmware, e.firmware)
+}
+
+// quirksRegistry is the quirks registry. Registered entries are searched
+// in reverse registration order, before the built-in entries.
+var quirksRegistry = struct {
+	sync.RWMutex
+	entries []quirksEntry
+	builtin []quirksEntry
+}{
+	builtin: []quirksEntry{
+		// USB sticks, no WiFi or Ethernet WAN
+		{"E3372", "", Quirks{Broken: []string{
+			"api/cradle/status-info",
+			"api/net/nr-mode",
+			"api/wlan/basic-settings",
+			"api/wlan/wifi-feature-switch",
+		}}},
+		{"E3372", "21.", Quirks{PasswordType: 3, StaticToken: true, Broken: []string{
+			"api/cradle/status-info",
+			"api/net/nr-mode",
+			"api/wlan/basic-settings",
+			"api/wlan/wifi-feature-switch",
+		}}},
+
+		// WiFi sticks and mobile hotspots, no Ethernet WAN
+		{"E8372", "", Quirks{Broken: []string{
+			"api/cradle/status-info",
+			"api/net/nr-mode",
+		}}},
+		{"E5573", "", Quirks{Broken: []string{
+			"api/cradle/status-info",
+			"api/net/nr-mode",
+		}}},
+
+		// LTE routers
+		{"E5186", "", Quirks{Broken: []string{
+			"api/net/nr-mode",
+		}}},
+		{"B315", "", Quirks{Broken: []string{
+			"api/net/nr-mode",
+		}}},
+		{"B525", "", Quirks{Broken: []string{
+			"api/net/nr-mode",
+		}}},
+		{"B818", "", Quirks{Broken: []string{
+			"api/net/nr-mode",
+		}}},
+	},
+}
+
+// RegisterQuirks registers the quirks of a device model (ie, "E3372"), and
+// optionally a firmware version prefix (ie, "21.180"). Models and firmware
+// versions are matched by prefix, with later registrations taking
+// precedence over earlier ones, and any registration taking precedence over
+// the built-in entries.
+func RegisterQuirks(model, firmware string, q Quirks) {
+	quirksRegistry.Lock()
+	defer quirksRegistry.Unlock()
+
+	quirksRegistry.entries = append(quirksRegistry.entries, quirksEntry{model, firmware, q})
+}
+
+// LookupQuirks returns the quirks of a device model and firmware version, as
+// reported by DeviceInformation (DeviceName and SoftwareVersion).
+func LookupQuirks(model, firmware string) (Quirks, bool) {
+	quirksRegistry.RLock()
+	defer quirksRegistry.RUnlock()
+
+	model = strings.ToUpper(model)
+
+	for i := len(quirksRegistry.entries) - 1; i >= 0; i-- {
+		if e := &quirksRegistry.entries[i]; e.match(model, firmware) {
+			return e.quirks, true
+		}
+	}
+
+	// prefer built-in entries matching the firmware
+	var match *quirksEntry
+	for i := range quirksRegistry.builtin {
+		e := &quirksRegistry.builtin[i]
+		if !e.match(model, firmware) {
+			continue
+		}
+		if match == nil || (match.firmware == "" && e.firmware != "") {
+			match = e
+		}
+	}
+	if match == nil {
+		return Quirks{}, false
+	}
+	return match.quirks, true
+}
+
+// WithQuirks is an option that sets the quirks of the device.
+func WithQuirks(q Quirks) Option {
+	return func(c *Client) error {
+		c.quirks = &q
+		return nil
+	}
+}
+
+// AutoQuirks is an option that detects the quirks of the device from its
+// model and firmware version (see LookupQuirks), once the session is
+// started. Devices without registered quirks use the common API.
+func AutoQuirks(c *Client) error {
+	c.autoquirks = true
+	return nil
+}
+
+// detectQuirks detects and sets the quirks of the device.
+func (c *Client) detectQuirks() error {
+	d, err := c.DeviceInformation()
+	if err != nil {
+		return fmt.Errorf("unable to detect device quirks: %w", err)
+	}
+
+	if q, ok := LookupQuirks(d.DeviceName, d.SoftwareVersion); ok {
+		c.quirks = &q
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package hilink
+
+import (
+	"testing"
+)
+
+func TestLookupQuirks(t *testing.T) {
+	quirksRegistry.Lock()
+	entries := quirksRegistry.entries
+	quirksRegistry.Unlock()
+	defer func() {
+		quirksRegistry.Lock()
+		quirksRegistry.entries = entries
+		quirksRegistry.Unlock()
+	}()
+
+	// built-in entries matching the firmware are preferred
+	q, ok := LookupQuirks("E3372h-320", "21.180.01.00.00")
+	if !ok || q.PasswordType != 3 {
+		t.Fatalf("expected firmware specific built-in quirks, got: %v %+v", ok, q)
+	}
+	q, ok = LookupQuirks("E3372h-320", "22.200.15.00.00")
+	if !ok || q.PasswordType != 0 {
+		t.Fatalf("expected generic built-in quirks, got: %v %+v", ok, q)
+	}
+	if _, ok := LookupQuirks("X1000", "1.0"); ok {
+		t.Fatal("expected no quirks")
+	}
+
+	// registered entries take precedence over the built-in entries, and
+	// later registrations over earlier ones
+	RegisterQuirks("E3372", "21.", Quirks{PasswordType: 4})
+	q, _ = LookupQuirks("E3372h-320", "21.180.01.00.00")
+	if q.PasswordType != 4 {
+		t.Errorf("expected registered firmware specific quirks, got: %+v", q)
+	}
+	RegisterQuirks("E3372", "", Quirks{StaticToken: true})
+	q, _ = LookupQuirks("E3372h-320", "21.180.01.00.00")
+	if !q.StaticToken || q.PasswordType != 0 {
+		t.Errorf("expected later registered generic quirks, got: %+v", q)
+	}
+}
@@ -0,0 +1,137 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RebootSchedule is the reboot schedule of a device, where the firmware
+// supports it (see ProbeEndpoints, "device/reboot-schedule"). Devices without
+// the endpoint can be rebooted by a RebootScheduler instead.
+type RebootSchedule struct {
+	// Enabled enables the scheduled reboot.
+	Enabled bool
+
+	// Time is the daily reboot time ("15:04"), in the device's time zone.
+	Time string
+}
+
+// RebootSchedule retrieves the reboot schedule of the device.
+func (c *Client) RebootSchedule() (*RebootSchedule, error) {
+	d, err := c.Do("api/device/reboot-schedule", nil)
+	if err != nil {
+		return nil, err
+	}
+	str := func(k string) string {
+		s, _ := d[k].(string)
+		return s
+	}
+	return &RebootSchedule{
+		Enabled: str("Enable") == "1",
+		Time:    str("Time"),
+	}, nil
+}
+
+// RebootScheduleSet sets the reboot schedule of the device, retaining the
+// other settings reported by the device.
+func (c *Client) RebootScheduleSet(s RebootSchedule) (bool, error) {
+	if s.Enabled || s.Time != "" {
+		if _, err := time.Parse("15:04", s.Time); err != nil {
+			return false, fmt.Errorf("%w: reboot time must be HH:MM", ErrInvalidValue)
+		}
+	}
+
+	d, err := c.Do("api/device/reboot-schedule", nil)
+	if err != nil {
+		return false, err
+	}
+	d["Enable"] = boolToString(s.Enabled)
+	if s.Time != "" {
+		d["Time"] = s.Time
+	}
+
+	return c.doReqCheckOK("api/device/reboot-schedule", d)
+}
+
+// RebootScheduler periodically reboots the device (see DeviceReboot), for
+// devices without a reboot schedule (see RebootSchedule), for example:
+//
+//	&RebootScheduler{
+//		Client:   client,
+//		Schedule: "0 4 * * 1",
+//		Jitter:   30 * time.Minute,
+//	}
+type RebootScheduler struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Schedule is the cron spec of the reboots (see ParseCronSchedule).
+	Schedule string
+
+	// Jitter is the maximum random delay added to each scheduled reboot, ie
+	// so a fleet of devices does not reboot at once.
+	Jitter time.Duration
+
+	// BeforeReboot, when set, is called before each reboot. An error skips
+	// the reboot, and is reported to OnError.
+	BeforeReboot func(context.Context) error
+
+	// OnReboot is called with the time of each reboot.
+	OnReboot func(time.Time)
+
+	// OnError is called when a reboot fails or is skipped.
+	OnError func(error)
+}
+
+// Run runs the scheduler until the context is closed.
+func (r *RebootScheduler) Run(ctx context.Context) error {
+	if r.Client == nil {
+		return ErrNilClient
+	}
+
+	sched, err := ParseCronSchedule(r.Schedule)
+	if err != nil {
+		return err
+	}
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for {
+		next := sched.Next(time.Now())
+		if next.IsZero() {
+			return ErrInvalidCronSpec
+		}
+		if r.Jitter > 0 {
+			next = next.Add(time.Duration(rnd.Int63n(int64(r.Jitter))))
+		}
+
+		t := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		if err := r.Reboot(ctx); err != nil && r.OnError != nil {
+			r.OnError(err)
+		}
+	}
+}
+
+// Reboot calls the pre-reboot hook, and reboots the device.
+func (r *RebootScheduler) Reboot(ctx context.Context) error {
+	if r.BeforeReboot != nil {
+		if err := r.BeforeReboot(ctx); err != nil {
+			return fmt.Errorf("reboot skipped: %w", err)
+		}
+	}
+	if err := checkOK(r.Client.DeviceReboot()); err != nil {
+		return err
+	}
+	if r.OnReboot != nil {
+		r.OnReboot(time.Now())
+	}
+	return nil
+}
@@ -0,0 +1,597 @@
+package hilink
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Resource is a configurable device resource (ie, a port forward), as its
+// element values.
+type Resource map[string]string
+
+// ResourceClient provides create, read, update and delete operations on a
+// type of configurable device resource.
+//
+// Resources are identified by a stable ID derived from their natural key
+// (ie, the MAC address of a static lease), rather than by their position on
+// the device. Operations are idempotent: creating a resource identical to an
+// existing one, or deleting a missing one, succeeds without changes.
+type ResourceClient interface {
+	// List retrieves all resources, keyed by ID.
+	List() (map[string]Resource, error)
+
+	// Read retrieves the resource with the ID.
+	Read(id string) (Resource, error)
+
+	// Create creates the resource, returning its ID.
+	Create(r Resource) (string, error)
+
+	// Update updates the values of the resource with the ID. Values not
+	// present in r are retained.
+	Update(id string, r Resource) error
+
+	// Delete deletes the resource with the ID.
+	Delete(id string) error
+}
+
+// PortForwardResources returns the ResourceClient of the port forwards
+// (virtual servers), identified by protocol and WAN port (ie, "6:8080").
+func (c *Client) PortForwardResources() ResourceClient {
+	return &listResource{
+		c:        c,
+		endpoint: "api/security/virtual-servers",
+		list:     "Servers",
+		item:     "Server",
+		key:      []string{"VirtualServerProtocol", "VirtualServerWanPort"},
+		defaults: Resource{
+			"VirtualServerIPName":     "",
+			"VirtualServerStatus":     "1",
+			"VirtualServerRemoteIP":   "",
+			"VirtualServerWanPort":    "",
+			"VirtualServerWanEndPort": "",
+			"VirtualServerLanPort":    "",
+			"VirtualServerLanEndPort": "",
+			"VirtualServerIPAddress":  "",
+			"VirtualServerProtocol":   "6",
+		},
+	}
+}
+
+// StaticLeaseResources returns the ResourceClient of the DHCP static leases,
+// identified by MAC address.
+func (c *Client) StaticLeaseResources() ResourceClient {
+	return &listResource{
+		c:        c,
+		endpoint: "api/dhcp/static-addr-info",
+		list:     "Hosts",
+		item:     "Host",
+		key:      []string{"HostHw"},
+		index:    "HostIndex",
+		defaults: Resource{
+			"HostIndex":   "",
+			"HostHw":      "",
+			"HostIp":      "",
+			"HostEnabled": "1",
+		},
+	}
+}
+
+// TimeRuleResources returns the ResourceClient of the access time rules
+// (parental control), identified by name, where firmware supports it.
+func (c *Client) TimeRuleResources() ResourceClient {
+	return &listResource{
+		c:        c,
+		endpoint: "api/timerule/timerule",
+		list:     "TimeRules",
+		item:     "TimeRule",
+		key:      []string{"Name"},
+		index:    "Index",
+		defaults: Resource{
+			"Index":     "",
+			"Name":      "",
+			"Enable":    "1",
+			"StartTime": "",
+			"EndTime":   "",
+			"DayOfWeek": "",
+			"MacList":   "",
+		},
+	}
+}
+
+// MACFilterResources returns the ResourceClient of the MAC filter entries
+// of the primary SSID, identified by MAC address. Entries have the single
+// value "Mac".
+func (c *Client) MACFilterResources() ResourceClient {
+	return &macFilterResource{c: c}
+}
+
+// ProfileResources returns the ResourceClient of the connection (APN)
+// profiles, identified by name.
+func (c *Client) ProfileResources() ResourceClient {
+	return &profileResource{c: c}
+}
+
+// listResource is a ResourceClient for resources stored as a list element,
+// that is updated by posting the full list.
+type listResource struct {
+	c *Client
+
+	// endpoint is the endpoint of the list.
+	endpoint string
+
+	// list and item are the names of the list and item elements.
+	list, item string
+
+	// key are the values forming the resource ID.
+	key []string
+
+	// index is the value renumbered (starting at 1) when posting the list,
+	// if any.
+	index string
+
+	// defaults are the default values of created resources.
+	defaults Resource
+}
+
+// id returns the ID of r.
+func (l *listResource) id(r Resource) string {
+	v := make([]string, len(l.key))
+	for i, k := range l.key {
+		v[i] = strings.ToLower(r[k])
+	}
+	return strings.Join(v, ":")
+}
+
+// read retrieves the list.
+func (l *listResource) read() ([]Resource, error) {
+	d, err := l.c.Do(l.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []Resource
+	if m, ok := d[l.list].(map[string]interface{}); ok {
+		for _, item := range xmlList(m[l.item]) {
+			rs = append(rs, toResource(item))
+		}
+	}
+	return rs, nil
+}
+
+// write posts the list.
+func (l *listResource) write(rs []Resource) error {
+	items := make([]interface{}, len(rs))
+	for i, r := range rs {
+		m := r.xml()
+		if l.index != "" {
+			m[l.index] = strconv.Itoa(i + 1)
+		}
+		items[i] = m
+	}
+
+	var list interface{} = ""
+	if len(items) != 0 {
+		list = map[string]interface{}{l.item: items}
+	}
+
+	return checkOK(l.c.doReqCheckOK(l.endpoint, XMLData{l.list: list}))
+}
+
+// find returns the position of the resource with the ID.
+func (l *listResource) find(rs []Resource, id string) int {
+	for i, r := range rs {
+		if l.id(r) == strings.ToLower(id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// List satisfies the ResourceClient interface.
+func (l *listResource) List() (map[string]Resource, error) {
+	rs, err := l.read()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]Resource, len(rs))
+	for _, r := range rs {
+		m[l.id(r)] = r
+	}
+	return m, nil
+}
+
+// Read satisfies the ResourceClient interface.
+func (l *listResource) Read(id string) (Resource, error) {
+	rs, err := l.read()
+	if err != nil {
+		return nil, err
+	}
+
+	i := l.find(rs, id)
+	if i == -1 {
+		return nil, fmt.Errorf("%w %q", ErrResourceNotFound, id)
+	}
+	return rs[i], nil
+}
+
+// Create satisfies the ResourceClient interface.
+func (l *listResource) Create(r Resource) (string, error) {
+	rs, err := l.read()
+	if err != nil {
+		return "", err
+	}
+
+	id := l.id(r)
+	if i := l.find(rs, id); i != -1 {
+		if !rs[i].matches(r) {
+			return "", fmt.Errorf("%w %q", ErrResourceExists, id)
+		}
+		return id, nil
+	}
+
+	if err := l.write(append(rs, l.defaults.merge(r))); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Update satisfies the ResourceClient interface.
+func (l *listResource) Update(id string, r Resource) error {
+	rs, err := l.read()
+	if err != nil {
+		return err
+	}
+
+	i := l.find(rs, id)
+	switch {
+	case i == -1:
+		return fmt.Errorf("%w %q", ErrResourceNotFound, id)
+	case rs[i].matches(r):
+		return nil
+	}
+
+	rs[i] = rs[i].merge(r)
+	return l.write(rs)
+}
+
+// Delete satisfies the ResourceClient interface.
+func (l *listResource) Delete(id string) error {
+	rs, err := l.read()
+	if err != nil {
+		return err
+	}
+
+	i := l.find(rs, id)
+	if i == -1 {
+		return nil
+	}
+	return l.write(append(rs[:i], rs[i+1:]...))
+}
+
+// macFilterSlots is the number of MAC filter entries per SSID.
+const macFilterSlots = 10
+
+// macFilterResource is the ResourceClient for MAC filter entries, which are
+// stored in fixed slots of the SSID settings.
+type macFilterResource struct {
+	c *Client
+}
+
+// read retrieves the SSID settings, and the MAC filter entries of the
+// primary SSID.
+func (f *macFilterResource) read() (XMLData, map[string]interface{}, error) {
+	d, err := f.c.Do("api/wlan/multi-macfilter-settings", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if m, ok := d["Ssids"].(map[string]interface{}); ok {
+		if l := xmlList(m["Ssid"]); len(l) != 0 {
+			return d, l[0], nil
+		}
+	}
+	return nil, nil, ErrInvalidResponse
+}
+
+// write posts the SSID settings, with the primary SSID replaced by ssid.
+func (f *macFilterResource) write(d XMLData, ssid map[string]interface{}) error {
+	ssids := xmlList(d["Ssids"].(map[string]interface{})["Ssid"])
+
+	l := make([]interface{}, len(ssids))
+	l[0] = ssid
+	for i := 1; i < len(ssids); i++ {
+		l[i] = ssids[i]
+	}
+
+	return checkOK(f.c.doReqCheckOK("api/wlan/multi-macfilter-settings", XMLData{
+		"Ssids": map[string]interface{}{"Ssid": l},
+	}))
+}
+
+// slot returns the MAC filter slot holding mac, or the first empty slot when
+// mac is empty.
+func (f *macFilterResource) slot(ssid map[string]interface{}, mac string) int {
+	for i := 0; i < macFilterSlots; i++ {
+		v, _ := ssid[fmt.Sprintf("WifiMacFilterMac%d", i)].(string)
+		if strings.EqualFold(v, mac) {
+			return i
+		}
+	}
+	return -1
+}
+
+// List satisfies the ResourceClient interface.
+func (f *macFilterResource) List() (map[string]Resource, error) {
+	_, ssid, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]Resource)
+	for i := 0; i < macFilterSlots; i++ {
+		if v, _ := ssid[fmt.Sprintf("WifiMacFilterMac%d", i)].(string); v != "" {
+			m[strings.ToLower(v)] = Resource{"Mac": v}
+		}
+	}
+	return m, nil
+}
+
+// Read satisfies the ResourceClient interface.
+func (f *macFilterResource) Read(id string) (Resource, error) {
+	m, err := f.List()
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := m[strings.ToLower(id)]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrResourceNotFound, id)
+	}
+	return r, nil
+}
+
+// Create satisfies the ResourceClient interface.
+func (f *macFilterResource) Create(r Resource) (string, error) {
+	mac := r["Mac"]
+	if err := ValidateMACAddress(mac); err != nil {
+		return "", err
+	}
+
+	d, ssid, err := f.read()
+	if err != nil {
+		return "", err
+	}
+
+	id := strings.ToLower(mac)
+	if f.slot(ssid, mac) != -1 {
+		return id, nil
+	}
+
+	i := f.slot(ssid, "")
+	if i == -1 {
+		return "", ErrResourceFull
+	}
+	ssid[fmt.Sprintf("WifiMacFilterMac%d", i)] = mac
+
+	if err := f.write(d, ssid); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Update satisfies the ResourceClient interface.
+func (f *macFilterResource) Update(id string, r Resource) error {
+	mac, ok := r["Mac"]
+	if !ok || strings.EqualFold(mac, id) {
+		_, err := f.Read(id)
+		return err
+	}
+	if err := ValidateMACAddress(mac); err != nil {
+		return err
+	}
+
+	d, ssid, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	i := f.slot(ssid, id)
+	if i == -1 {
+		return fmt.Errorf("%w %q", ErrResourceNotFound, id)
+	}
+	ssid[fmt.Sprintf("WifiMacFilterMac%d", i)] = mac
+
+	return f.write(d, ssid)
+}
+
+// Delete satisfies the ResourceClient interface.
+func (f *macFilterResource) Delete(id string) error {
+	d, ssid, err := f.read()
+	if err != nil {
+		return err
+	}
+
+	i := f.slot(ssid, id)
+	if i == -1 || id == "" {
+		return nil
+	}
+	ssid[fmt.Sprintf("WifiMacFilterMac%d", i)] = ""
+
+	return f.write(d, ssid)
+}
+
+// profileResource is the ResourceClient for connection profiles.
+type profileResource struct {
+	c *Client
+}
+
+// read retrieves the profiles and the index of the default profile.
+func (p *profileResource) read() ([]Resource, string, error) {
+	d, err := p.c.ProfileInfo()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var rs []Resource
+	if m, ok := d["Profiles"].(map[string]interface{}); ok {
+		for _, item := range xmlList(m["Profile"]) {
+			rs = append(rs, toResource(item))
+		}
+	}
+
+	def, _ := d["CurrentProfile"].(string)
+	return rs, def, nil
+}
+
+// find returns the profile named id.
+func (p *profileResource) find(rs []Resource, id string) Resource {
+	for _, r := range rs {
+		if r["Name"] == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// List satisfies the ResourceClient interface.
+func (p *profileResource) List() (map[string]Resource, error) {
+	rs, _, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]Resource, len(rs))
+	for _, r := range rs {
+		m[r["Name"]] = r
+	}
+	return m, nil
+}
+
+// Read satisfies the ResourceClient interface.
+func (p *profileResource) Read(id string) (Resource, error) {
+	rs, _, err := p.read()
+	if err != nil {
+		return nil, err
+	}
+
+	r := p.find(rs, id)
+	if r == nil {
+		return nil, fmt.Errorf("%w %q", ErrResourceNotFound, id)
+	}
+	return r, nil
+}
+
+// Create satisfies the ResourceClient interface.
+func (p *profileResource) Create(r Resource) (string, error) {
+	rs, _, err := p.read()
+	if err != nil {
+		return "", err
+	}
+
+	id := r["Name"]
+	if cur := p.find(rs, id); cur != nil {
+		if !cur.matches(r) {
+			return "", fmt.Errorf("%w %q", ErrResourceExists, id)
+		}
+		return id, nil
+	}
+
+	profile := newProfileXML(r)
+	if err := checkOK(p.c.doReqCheckOK("api/dialup/profiles", profileReq("1", "0", profile))); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Update satisfies the ResourceClient interface.
+func (p *profileResource) Update(id string, r Resource) error {
+	rs, def, err := p.read()
+	if err != nil {
+		return err
+	}
+
+	cur := p.find(rs, id)
+	switch {
+	case cur == nil:
+		return fmt.Errorf("%w %q", ErrResourceNotFound, id)
+	case cur.matches(r):
+		return nil
+	}
+
+	return checkOK(p.c.doReqCheckOK("api/dialup/profiles", profileReq("2", def, cur.merge(r).xml())))
+}
+
+// Delete satisfies the ResourceClient interface.
+func (p *profileResource) Delete(id string) error {
+	rs, def, err := p.read()
+	if err != nil {
+		return err
+	}
+
+	cur := p.find(rs, id)
+	if cur == nil {
+		return nil
+	}
+
+	// move default to another profile when deleting the default
+	if cur["Index"] == def {
+		def = ""
+		for _, r := range rs {
+			if r["Index"] != cur["Index"] {
+				def = r["Index"]
+				break
+			}
+		}
+	}
+
+	return checkOK(p.c.ProfileDelete(cur["Index"], def))
+}
+
+// toResource converts a decoded XML element to a Resource.
+func toResource(m map[string]interface{}) Resource {
+	r := make(Resource, len(m))
+	for k, v := range m {
+		switch x := v.(type) {
+		case string:
+			r[k] = x
+		case nil:
+			r[k] = ""
+		default:
+			r[k] = fmt.Sprint(x)
+		}
+	}
+	return r
+}
+
+// matches determines if the values of v are equal to those of r.
+func (r Resource) matches(v Resource) bool {
+	for k, x := range v {
+		if r[k] != x {
+			return false
+		}
+	}
+	return true
+}
+
+// merge returns a copy of r with the values of v merged in.
+func (r Resource) merge(v Resource) Resource {
+	m := make(Resource, len(r)+len(v))
+	for k, x := range r {
+		m[k] = x
+	}
+	for k, x := range v {
+		m[k] = x
+	}
+	return m
+}
+
+// xml returns r as a XML element.
+func (r Resource) xml() map[string]interface{} {
+	m := make(map[string]interface{}, len(r))
+	for k, v := range r {
+		m[k] = v
+	}
+	return m
+}
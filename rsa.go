@@ -0,0 +1,207 @@
+package hilink
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// rsaEndpoints are the endpoints encrypted by default with the
+// RSAEncryption option, or when the device advertises RSA encryption.
+var rsaEndpoints = []string{
+	"api/sms/send-sms",
+	"api/user/login",
+	"api/user/password",
+	"api/wlan/multi-security-settings",
+	"api/wlan/security-settings",
+}
+
+// rsaKey is the public key of the device used to encrypt request bodies.
+type rsaKey struct {
+	key  *rsa.PublicKey
+	oaep bool
+}
+
+// rsaState is the RSA encryption state of a client.
+type rsaState struct {
+	endpoints map[string]bool
+	key       *rsaKey
+
+	// detect is the detected encryption advertisement (see rsaAdvertised)
+	detect int32
+	sync.Mutex
+}
+
+// RSAEncryption is an option that encrypts the request bodies sent to the
+// endpoints (ie, "api/sms/send-sms") with the public key of the device
+// (api/webserver/publickey), as required by some firmware for protected
+// endpoints, which otherwise fail with an invalid token error. When no
+// endpoints are given, the login, password, SMS send and WiFi security
+// endpoints are encrypted. Endpoints listed in the device quirks (see
+// Quirks) are encrypted without the option, as are the default endpoints
+// when the device advertises encryption (encrypt_enabled, see
+// GlobalFeatures).
+//
+// The public key is retrieved on first use, and cached.
+func RSAEncryption(endpoints ...string) Option {
+	return func(c *Client) error {
+		if len(endpoints) == 0 {
+			endpoints = rsaEndpoints
+		}
+		if c.rsa.endpoints == nil {
+			c.rsa.endpoints = make(map[string]bool)
+		}
+		for _, endpoint := range endpoints {
+			c.rsa.endpoints[endpoint] = true
+		}
+		return nil
+	}
+}
+
+// encrypted determines if requests to the endpoint path are encrypted.
+func (c *Client) encrypted(path string) bool {
+	if c.rsa.endpoints[path] {
+		return true
+	}
+	if c.quirks != nil {
+		for _, p := range c.quirks.Encrypted {
+			if p == path {
+				return true
+			}
+		}
+	}
+	for _, p := range rsaEndpoints {
+		if p == path {
+			return c.rsaAdvertised()
+		}
+	}
+	return false
+}
+
+// rsaState detect values.
+const (
+	rsaUnknown int32 = iota
+	rsaPlain
+	rsaEncrypted
+)
+
+// rsaAdvertised determines if the device advertises RSA encryption, as
+// detected on first use. A failed detection is retried with the next
+// request, unless the device reported an error.
+func (c *Client) rsaAdvertised() bool {
+	if d := atomic.LoadInt32(&c.rsa.detect); d != rsaUnknown {
+		return d == rsaEncrypted
+	}
+
+	var sw struct {
+		Encrypt string `xml:"encrypt_enabled"`
+	}
+	err := c.doReqXML("api/global/module-switch", nil, &sw)
+	var apiErr *APIError
+	if err != nil && !errors.As(err, &apiErr) {
+		return false
+	}
+
+	d := rsaPlain
+	if err == nil && sw.Encrypt == "1" {
+		d = rsaEncrypted
+	}
+	atomic.StoreInt32(&c.rsa.detect, d)
+	return d == rsaEncrypted
+}
+
+// publicKey returns the cached public key of the device, retrieving it when
+// not yet cached.
+func (c *Client) publicKey() (*rsaKey, error) {
+	c.rsa.Lock()
+	defer c.rsa.Unlock()
+
+	if c.rsa.key != nil {
+		return c.rsa.key, nil
+	}
+
+	var pub struct {
+		N string `xml:"encpubkeyn"`
+		E string `xml:"encpubkeye"`
+	}
+	if err := c.doReqXML("api/webserver/publickey", nil, &pub); err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Int).SetString(pub.N, 16)
+	if !ok {
+		return nil, ErrInvalidPublicKey
+	}
+	e, err := strconv.ParseInt(pub.E, 16, 32)
+	if err != nil || e < 3 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	// padding advertised with the login state, OAEP unless set to 0
+	var state struct {
+		Padding string `xml:"rsapadingtype"`
+	}
+	_ = c.doReqXML("api/user/state-login", nil, &state)
+
+	c.rsa.key = &rsaKey{
+		key:  &rsa.PublicKey{N: n, E: int(e)},
+		oaep: state.Padding != "0",
+	}
+	return c.rsa.key, nil
+}
+
+// encrypt encrypts the request body, as the WebUI does: the base64 encoded
+// body is encrypted in blocks, and sent hex encoded.
+func (k *rsaKey) encrypt(req *http.Request) error {
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body.Close()
+
+	data := []byte(base64.StdEncoding.EncodeToString(buf))
+
+	// block size, less the padding overhead
+	size := k.key.Size() - 11
+	if k.oaep {
+		size = k.key.Size() - 2*sha1.Size - 2
+	}
+
+	var out []byte
+	for len(data) > 0 {
+		n := size
+		if n > len(data) {
+			n = len(data)
+		}
+
+		var block []byte
+		if k.oaep {
+			block, err = rsa.EncryptOAEP(sha1.New(), rand.Reader, k.key, data[:n], nil)
+		} else {
+			block, err = rsa.EncryptPKCS1v15(rand.Reader, k.key, data[:n])
+		}
+		if err != nil {
+			return err
+		}
+		out = append(out, block...)
+		data = data[n:]
+	}
+
+	body := hex.EncodeToString(out)
+	req.Body = ioutil.NopCloser(strings.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = nil
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8;enc")
+	req.Header.Set("encrypt_transmit", "encrypt_transmit")
+
+	return nil
+}
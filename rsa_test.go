@@ -0,0 +1,55 @@
+package hilink
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"strconv"
+	"testing"
+)
+
+func TestRSAAdvertised(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		features  string
+		encrypted bool
+	}{
+		{"advertised", `<response><encrypt_enabled>1</encrypt_enabled></response>`, true},
+		{"disabled", `<response><encrypt_enabled>0</encrypt_enabled></response>`, false},
+		{"unsupported", `<error><code>100002</code><message></message></error>`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := newFakeDevice()
+			defer d.Close()
+			d.Responses["/api/global/module-switch"] = test.features
+			d.Responses["/api/webserver/publickey"] = `<response><encpubkeyn>` + key.N.Text(16) + `</encpubkeyn><encpubkeye>` + strconv.FormatInt(int64(key.E), 16) + `</encpubkeye></response>`
+			d.Responses["/api/user/state-login"] = `<response><rsapadingtype>1</rsapadingtype></response>`
+
+			c := d.client(t)
+			for i := 0; i < 2; i++ {
+				if _, err := c.SmsSend("hello", "+15551234"); err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+			}
+
+			// detected once, the SMS send request being hex encoded when
+			// encrypted
+			if n := d.count("/api/global/module-switch"); n != 1 {
+				t.Errorf("expected 1 detection request, got: %d", n)
+			}
+			d.mu.Lock()
+			body := d.requests["/api/sms/send-sms"][0]
+			d.mu.Unlock()
+			_, err := hex.DecodeString(string(body))
+			if encrypted := err == nil; encrypted != test.encrypted {
+				t.Errorf("expected encrypted %t, got: %s", test.encrypted, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Watcher is a background component (ie, SignalWatcher, BatteryPolicy, or
+// BackupScheduler), running until its context is closed.
+type Watcher interface {
+	Run(ctx context.Context) error
+}
+
+// WatcherFunc is a func satisfying the Watcher interface.
+type WatcherFunc func(ctx context.Context) error
+
+// Run satisfies the Watcher interface.
+func (f WatcherFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Runner runs a Watcher in the background, recovering from panics and
+// restarting the watcher with an exponential backoff when it fails. A
+// watcher that returns nil is not restarted.
+type Runner struct {
+	// Watcher is the watcher run.
+	Watcher Watcher
+
+	// MinBackoff is the delay before the first restart. Defaults to 1
+	// second.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between restarts. The delay is reset
+	// once the watcher has run for longer than MaxBackoff. Defaults to 1
+	// minute.
+	MaxBackoff time.Duration
+
+	// OnError is called when the watcher fails, or panics, before it is
+	// restarted.
+	OnError func(error)
+
+	client *Client
+	cancel context.CancelFunc
+	done   chan struct{}
+	sync.Mutex
+}
+
+// Start starts the watcher in the background, until Stop is called or the
+// context is closed.
+func (r *Runner) Start(ctx context.Context) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.Watcher == nil {
+		return ErrNilWatcher
+	}
+	if r.done != nil {
+		return ErrRunnerStarted
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+	go r.loop(ctx, r.done)
+
+	if r.client != nil {
+		r.client.addRunner(r)
+	}
+
+	return nil
+}
+
+// Stop stops the watcher, waiting for it to return. Stop may be called
+// multiple times, and the runner started again afterwards.
+func (r *Runner) Stop() {
+	r.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.Unlock()
+
+	if done == nil {
+		return
+	}
+	cancel()
+	<-done
+
+	if r.client != nil {
+		r.client.removeRunner(r)
+	}
+}
+
+// loop runs the watcher until the context is closed.
+func (r *Runner) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	min, max := r.MinBackoff, r.MaxBackoff
+	if min == 0 {
+		min = time.Second
+	}
+	if max == 0 {
+		max = time.Minute
+	}
+
+	backoff := min
+	for {
+		start := time.Now()
+		err := r.run(ctx)
+		switch {
+		case ctx.Err() != nil, err == nil:
+			return
+		case r.OnError != nil:
+			r.OnError(err)
+		}
+
+		if time.Since(start) > max {
+			backoff = min
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+
+		if backoff *= 2; backoff > max {
+			backoff = max
+		}
+	}
+}
+
+// run runs the watcher once, converting panics to errors.
+func (r *Runner) run(ctx context.Context) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("%w: %v", ErrWatcherPanic, v)
+		}
+	}()
+	return r.Watcher.Run(ctx)
+}
+
+// Go starts the watcher in the background with a Runner (see Runner), that
+// is stopped with Stop or when the client is closed.
+func (c *Client) Go(ctx context.Context, w Watcher) (*Runner, error) {
+	r := &Runner{
+		Watcher: w,
+		client:  c,
+	}
+	if err := r.Start(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// addRunner adds a started runner.
+func (c *Client) addRunner(r *Runner) {
+	c.runmu.Lock()
+	defer c.runmu.Unlock()
+
+	if c.runners == nil {
+		c.runners = make(map[*Runner]bool)
+	}
+	c.runners[r] = true
+}
+
+// removeRunner removes a stopped runner.
+func (c *Client) removeRunner(r *Runner) {
+	c.runmu.Lock()
+	defer c.runmu.Unlock()
+
+	delete(c.runners, r)
+}
+
+// Close stops the watchers started with Go, and closes the idle
+// connections to the device.
+func (c *Client) Close() error {
+	c.runmu.Lock()
+	runners := make([]*Runner, 0, len(c.runners))
+	for r := range c.runners {
+		runners = append(runners, r)
+	}
+	c.runmu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, r := range runners {
+		wg.Add(1)
+		go func(r *Runner) {
+			defer wg.Done()
+			r.Stop()
+		}(r)
+	}
+	wg.Wait()
+
+	c.client.CloseIdleConnections()
+
+	return nil
+}
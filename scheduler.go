@@ -0,0 +1,292 @@
+package hilink
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Scheduler coordinates the polling of read-only endpoints on a device by
+// multiple consumers (ie, an exporter, a watchdog, and an SMS watcher), for
+// example:
+//
+//	s := &Scheduler{Client: client, Budget: 2}
+//	e := &exporter.Exporter{Client: client, Scheduler: s}
+//	w := &Watchdog{Client: client, Scheduler: s}
+//	go e.Run(ctx)
+//	go w.Run(ctx)
+//	err := s.Run(ctx)
+//
+// Polls are batched on a shared tick, polls of the same endpoint falling
+// due in the same tick share a single request, and the number of requests
+// sent per tick is bounded by a budget. The Exporter, Monitor, SignalWatcher,
+// SmsWatcher and Watchdog poll through the scheduler when given one, which
+// must be run for their polls to be sent.
+type Scheduler struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Tick is the scheduling tick, to which poll intervals are rounded.
+	// Defaults to 1 second.
+	Tick time.Duration
+
+	// Budget is the maximum number of requests sent per tick. Polls over
+	// budget are deferred to the next tick, oldest first. Zero means no
+	// limit.
+	Budget int
+
+	polls map[*schedulerPoll]bool
+	sync.Mutex
+}
+
+// schedulerPoll is a poll registered with a Scheduler.
+type schedulerPoll struct {
+	path     string
+	interval time.Duration
+	next     time.Time
+	fn       func([]byte, error)
+}
+
+// Poll registers fn to be called with the response of the endpoint path
+// (see Do) every interval, once the scheduler runs. The returned func
+// removes the poll.
+func (s *Scheduler) Poll(path string, interval time.Duration, fn func(XMLData, error)) func() {
+	return s.poll(path, interval, func(body []byte, err error) {
+		fn(decodeData(body, err))
+	})
+}
+
+// PollStatus registers fn to be called with the status information (see
+// Client.Status) every interval. The returned func removes the poll.
+func (s *Scheduler) PollStatus(interval time.Duration, fn func(*Status, error)) func() {
+	return s.poll("api/monitoring/status", interval, func(body []byte, err error) {
+		fn(decodeStatus(body, err))
+	})
+}
+
+// PollSignal registers fn to be called with the signal information (see
+// Client.Signal) every interval. The returned func removes the poll.
+func (s *Scheduler) PollSignal(interval time.Duration, fn func(*Signal, error)) func() {
+	return s.poll("api/device/signal", interval, func(body []byte, err error) {
+		fn(decodeSignal(body, err))
+	})
+}
+
+// PollNotifications registers fn to be called with the notification status
+// information (see Client.Notifications) every interval. The returned func
+// removes the poll.
+func (s *Scheduler) PollNotifications(interval time.Duration, fn func(*Notifications, error)) func() {
+	return s.poll("api/monitoring/check-notifications", interval, func(body []byte, err error) {
+		fn(decodeNotifications(body, err))
+	})
+}
+
+// poll registers fn to be called with the response body of the endpoint
+// path every interval. The returned func removes the poll.
+func (s *Scheduler) poll(path string, interval time.Duration, fn func([]byte, error)) func() {
+	p := &schedulerPoll{
+		path:     path,
+		interval: interval,
+		fn:       fn,
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.polls == nil {
+		s.polls = make(map[*schedulerPoll]bool)
+	}
+	s.polls[p] = true
+
+	return func() {
+		s.Lock()
+		defer s.Unlock()
+
+		delete(s.polls, p)
+	}
+}
+
+// Run runs the scheduler until the context is closed.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.Client == nil {
+		return ErrNilClient
+	}
+
+	tick := s.Tick
+	if tick == 0 {
+		tick = time.Second
+	}
+
+	t := time.NewTicker(tick)
+	defer t.Stop()
+
+	for {
+		s.send(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// send sends the requests of the polls due at now, within the budget.
+func (s *Scheduler) send(ctx context.Context, now time.Time) {
+	s.Lock()
+
+	// group due polls by endpoint
+	due := make(map[string][]*schedulerPoll)
+	var paths []string
+	for p := range s.polls {
+		if p.next.After(now) {
+			continue
+		}
+		if _, ok := due[p.path]; !ok {
+			paths = append(paths, p.path)
+		}
+		due[p.path] = append(due[p.path], p)
+	}
+
+	// oldest first, within budget
+	oldest := func(path string) time.Time {
+		t := due[path][0].next
+		for _, p := range due[path][1:] {
+			if p.next.Before(t) {
+				t = p.next
+			}
+		}
+		return t
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return oldest(paths[i]).Before(oldest(paths[j]))
+	})
+	if s.Budget > 0 && len(paths) > s.Budget {
+		paths = paths[:s.Budget]
+	}
+
+	for _, path := range paths {
+		for _, p := range due[path] {
+			p.next = now.Add(p.interval)
+		}
+	}
+	s.Unlock()
+
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			return
+		}
+
+		body, err := s.Client.doReqBody(path, nil)
+		for _, p := range due[path] {
+			p.fn(body, err)
+		}
+	}
+}
+
+// poller polls an endpoint for a consumer, through a Scheduler when given
+// one, or on its own ticker otherwise. Consumers wait for C, and then fetch
+// the response.
+type poller struct {
+	client *Client
+	path   string
+
+	t     *time.Ticker
+	first chan time.Time
+
+	c    chan time.Time
+	stop func()
+
+	body []byte
+	err  error
+	sync.Mutex
+}
+
+// newPoller creates a poller of the endpoint path, every interval. The poll
+// is removed with close.
+func newPoller(s *Scheduler, c *Client, path string, interval time.Duration) *poller {
+	p := &poller{client: c, path: path}
+	if s == nil {
+		p.t = time.NewTicker(interval)
+		p.first = make(chan time.Time, 1)
+		p.first <- time.Now()
+		return p
+	}
+
+	// the pending response is replaced by a newer one
+	p.c = make(chan time.Time, 1)
+	p.stop = s.poll(path, interval, func(body []byte, err error) {
+		p.Lock()
+		defer p.Unlock()
+
+		p.drain()
+		p.body, p.err = body, err
+		p.c <- time.Now()
+	})
+	return p
+}
+
+// C returns the channel signaling the poll is due, or that its response was
+// received from the scheduler. A nil poller never signals.
+func (p *poller) C() <-chan time.Time {
+	switch {
+	case p == nil:
+		return nil
+	case p.c != nil:
+		return p.c
+	case p.first != nil:
+		return p.first
+	}
+	return p.t.C
+}
+
+// fetch returns the response of the poll, once C signaled.
+func (p *poller) fetch() ([]byte, error) {
+	if p.c == nil {
+		p.first = nil
+		return p.client.doReqBody(p.path, nil)
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.drain()
+	body, err := p.body, p.err
+	p.body, p.err = nil, nil
+	return body, err
+}
+
+// reset discards the pending response received from the scheduler, ie one
+// requested before a remediation step.
+func (p *poller) reset() {
+	if p.c == nil {
+		return
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.drain()
+	p.body, p.err = nil, nil
+}
+
+// drain drains the pending signal of the response received from the
+// scheduler.
+func (p *poller) drain() {
+	select {
+	case <-p.c:
+	default:
+	}
+}
+
+// close stops the poller.
+func (p *poller) close() {
+	switch {
+	case p == nil:
+	case p.stop != nil:
+		p.stop()
+	default:
+		p.t.Stop()
+	}
+}
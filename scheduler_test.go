@@ -0,0 +1,118 @@
+package hilink
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSchedulerShared(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/monitoring/status"] = `<response><ConnectionStatus>901</ConnectionStatus></response>`
+
+	s := &Scheduler{Client: d.client(t)}
+	var statuses, data int
+	s.PollStatus(time.Minute, func(st *Status, err error) {
+		if err != nil || st.ConnectionStatus != ConnectionStatusConnected {
+			t.Errorf("expected connected status, got: %+v %v", st, err)
+		}
+		statuses++
+	})
+	s.Poll("api/monitoring/status", time.Minute, func(v XMLData, err error) {
+		if err != nil || v["ConnectionStatus"] != "901" {
+			t.Errorf("expected connected status, got: %v %v", v, err)
+		}
+		data++
+	})
+
+	// polls of the same endpoint share a request, until due again
+	now := time.Now()
+	s.send(context.Background(), now)
+	s.send(context.Background(), now.Add(time.Second))
+	if statuses != 1 || data != 1 {
+		t.Errorf("expected 1 poll each, got: %d %d", statuses, data)
+	}
+	if n := d.count("/api/monitoring/status"); n != 1 {
+		t.Errorf("expected 1 request, got: %d", n)
+	}
+
+	s.send(context.Background(), now.Add(time.Minute))
+	if n := d.count("/api/monitoring/status"); n != 2 {
+		t.Errorf("expected 2 requests, got: %d", n)
+	}
+}
+
+func TestSchedulerBudget(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+
+	s := &Scheduler{Client: d.client(t), Budget: 2}
+	paths := []string{"api/device/signal", "api/monitoring/status", "api/monitoring/traffic-statistics"}
+	for _, path := range paths {
+		s.Poll(path, time.Minute, func(XMLData, error) {})
+	}
+
+	count := func() int {
+		var n int
+		for _, path := range paths {
+			n += d.count("/" + path)
+		}
+		return n
+	}
+
+	// polls over budget are deferred to the next tick
+	now := time.Now()
+	s.send(context.Background(), now)
+	if n := count(); n != 2 {
+		t.Fatalf("expected 2 requests, got: %d", n)
+	}
+	s.send(context.Background(), now.Add(time.Second))
+	if n := count(); n != 3 {
+		t.Fatalf("expected 3 requests, got: %d", n)
+	}
+	for _, path := range paths {
+		if n := d.count("/" + path); n != 1 {
+			t.Errorf("expected 1 request to %s, got: %d", path, n)
+		}
+	}
+}
+
+func TestSignalWatcherScheduler(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/device/signal"] = `<response><rsrp>-95dBm</rsrp></response>`
+
+	c := d.client(t)
+	s := &Scheduler{Client: c, Tick: 10 * time.Millisecond}
+	signals := make(chan *Signal, 1)
+	w := &SignalWatcher{
+		Client:    c,
+		Scheduler: s,
+		OnSignal: func(sig *Signal) {
+			select {
+			case signals <- sig:
+			default:
+			}
+		},
+	}
+
+	// the watcher polls nothing itself
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+	time.Sleep(50 * time.Millisecond)
+	if n := d.count("/api/device/signal"); n != 0 {
+		t.Fatalf("expected no request before the scheduler runs, got: %d", n)
+	}
+
+	go s.Run(ctx)
+	select {
+	case sig := <-signals:
+		if sig.RSRP != -95 {
+			t.Errorf("expected RSRP -95, got: %v", sig.RSRP)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected signal")
+	}
+}
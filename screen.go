@@ -0,0 +1,66 @@
+package hilink
+
+import (
+	"strconv"
+	"time"
+)
+
+// ScreenShowPassword determines if the WiFi password is shown on the screen
+// of E5-series (mobile hotspot) devices.
+func (c *Client) ScreenShowPassword() (bool, error) {
+	s, err := c.doReqString("api/wlan/oled-showpassword", nil, "oledshowpassword")
+	if err != nil {
+		return false, err
+	}
+	return s == "1", nil
+}
+
+// ScreenShowPasswordSet sets whether the WiFi password is shown on the screen
+// of E5-series (mobile hotspot) devices.
+func (c *Client) ScreenShowPasswordSet(show bool) (bool, error) {
+	return c.doReqCheckOK("api/wlan/oled-showpassword", SimpleRequestXML(
+		"oledshowpassword", boolToString(show),
+	))
+}
+
+// ScreenShowSSID determines if the SSID is shown on the screen of E5-series
+// (mobile hotspot) devices, where firmware supports it.
+func (c *Client) ScreenShowSSID() (bool, error) {
+	s, err := c.doReqString("api/wlan/oled-showssid", nil, "oledshowssid")
+	if err != nil {
+		return false, err
+	}
+	return s == "1", nil
+}
+
+// ScreenShowSSIDSet sets whether the SSID is shown on the screen of E5-series
+// (mobile hotspot) devices, where firmware supports it.
+func (c *Client) ScreenShowSSIDSet(show bool) (bool, error) {
+	return c.doReqCheckOK("api/wlan/oled-showssid", SimpleRequestXML(
+		"oledshowssid", boolToString(show),
+	))
+}
+
+// ScreenTimeout retrieves the screen timeout of E5-series (mobile hotspot)
+// devices, where firmware supports it.
+func (c *Client) ScreenTimeout() (time.Duration, error) {
+	s, err := c.doReqString("api/device/screen-setting", nil, "screentimeout")
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, ErrInvalidResponse
+	}
+
+	return time.Duration(i) * time.Second, nil
+}
+
+// ScreenTimeoutSet sets the screen timeout of E5-series (mobile hotspot)
+// devices, with second precision, where firmware supports it.
+func (c *Client) ScreenTimeoutSet(d time.Duration) (bool, error) {
+	return c.doReqCheckOK("api/device/screen-setting", SimpleRequestXML(
+		"screentimeout", strconv.Itoa(int(d/time.Second)),
+	))
+}
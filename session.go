@@ -0,0 +1,139 @@
+package hilink
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+// sessionFlight coordinates session refreshes, so that concurrent callers
+// share a single refresh.
+type sessionFlight struct {
+	gen  uint64
+	wait chan struct{}
+	ok   bool
+	err  error
+	sync.Mutex
+}
+
+// RefreshSession starts a new session with the server, logging in again
+// when credentials were provided. Concurrent calls share a single refresh,
+// with callers arriving while a refresh is in progress waiting for, and
+// receiving, its result. This prevents concurrent requests failing with an
+// expired session from each logging in, which can lock the account on some
+// devices.
+func (c *Client) RefreshSession() (bool, error) {
+	return c.refreshSession(c.sessionGen())
+}
+
+// sessionGen returns the current session generation, to be passed to
+// refreshSession when a request made with the session fails.
+func (c *Client) sessionGen() uint64 {
+	c.session.Lock()
+	defer c.session.Unlock()
+
+	return c.session.gen
+}
+
+// refreshSession refreshes the session of generation gen. When the session
+// was already refreshed since, the result of the refresh is reused.
+func (c *Client) refreshSession(gen uint64) (bool, error) {
+	s := &c.session
+
+	s.Lock()
+
+	// wait for refresh in progress
+	for s.wait != nil {
+		wait := s.wait
+		s.Unlock()
+		<-wait
+		s.Lock()
+	}
+
+	// reuse result when refreshed since
+	if s.gen != gen {
+		ok, err := s.ok, s.err
+		s.Unlock()
+		return ok, err
+	}
+
+	wait := make(chan struct{})
+	s.wait = wait
+	s.Unlock()
+
+	ok, err := c.restartSession()
+
+	s.Lock()
+	s.gen++
+	s.ok, s.err = ok, err
+	s.wait = nil
+	s.Unlock()
+	close(wait)
+
+	return ok, err
+}
+
+// restartSession starts a new session with a client sharing the http.Client
+// (and its session cookie), so that the requests of the session start are
+// not themselves retried. The new token is set on the client.
+func (c *Client) restartSession() (bool, error) {
+	s := c.sessionClient()
+	ok, err := s.startSession()
+	if err != nil {
+		return false, err
+	}
+
+	c.Lock()
+	c.token = s.token
+	c.flushTokens()
+	c.Unlock()
+
+	return ok, nil
+}
+
+// sessionClient returns a client sharing the http.Client and configuration,
+// used to start sessions.
+func (c *Client) sessionClient() *Client {
+	c.Lock()
+	defer c.Unlock()
+
+	return &Client{
+		rawurl:     c.rawurl,
+		url:        c.url,
+		host:       c.host,
+		authID:     c.authID,
+		authPW:     c.authPW,
+		nostart:    c.nostart,
+		norelogin:  true,
+		client:     c.client,
+		token:      c.token,
+		jar:        c.jar,
+		loc:        c.loc,
+		quirks:     c.quirks,
+		autoquirks: c.autoquirks,
+		auth:       c.auth,
+		rsa: rsaState{
+			endpoints: c.rsa.endpoints,
+			key:       c.rsa.key,
+			detect:    atomic.LoadInt32(&c.rsa.detect),
+		},
+	}
+}
+
+// NoAutoRelogin is an option that disables the automatic session refresh
+// and retry of requests failing with an invalid session or token error.
+func NoAutoRelogin(c *Client) error {
+	c.norelogin = true
+	return nil
+}
+
+// sessionExpired determines if the response body is an invalid session or
+// token error.
+func sessionExpired(body []byte) bool {
+	if !bytes.Contains(body, []byte("<error>")) {
+		return false
+	}
+
+	code, _ := ErrorCode(unmarshalXML(body, &struct{}{}))
+	return code == ErrorCodeInvalidSession || code == ErrorCodeInvalidSessionToken
+}
@@ -0,0 +1,229 @@
+package hilink
+
+import (
+	"encoding/xml"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Signal is the network signal information, with the units (ie, "dBm",
+// "dB") stripped from the values reported by the device.
+//
+// Values not reported by the device (ie, the NR values on LTE only devices)
+// are left as zero.
+type Signal struct {
+	// Mode is the radio access mode (ie, 7 for LTE).
+	Mode int
+
+	// LTE (or serving cell) values.
+	RSRP   float64
+	RSRQ   float64
+	RSSI   float64
+	SINR   float64
+	Band   int
+	EARFCN string
+	PCI    int
+	CellID string
+
+	// UMTS values.
+	RSCP float64
+	ECIO float64
+
+	// 5G NR values.
+	NRRSRP  float64
+	NRRSRQ  float64
+	NRSINR  float64
+	NRBand  int
+	NRARFCN string
+	NRPCI   int
+
+	// Carriers are the aggregated component carriers, with the primary cell
+	// first, on devices that report carrier aggregation information.
+	Carriers []Carrier
+}
+
+// Carrier is a carrier aggregation component carrier.
+type Carrier struct {
+	Primary   bool
+	Band      int
+	Bandwidth float64
+	EARFCN    string
+	PCI       int
+}
+
+// signalXML is the raw signal information returned by the device.
+type signalXML struct {
+	Mode     string `xml:"mode"`
+	RSRP     string `xml:"rsrp"`
+	RSRQ     string `xml:"rsrq"`
+	RSSI     string `xml:"rssi"`
+	SINR     string `xml:"sinr"`
+	Band     string `xml:"band"`
+	EARFCN   string `xml:"earfcn"`
+	PCI      string `xml:"pci"`
+	CellID   string `xml:"cell_id"`
+	RSCP     string `xml:"rscp"`
+	ECIO     string `xml:"ecio"`
+	NRRSRP   string `xml:"nrrsrp"`
+	NRRSRQ   string `xml:"nrrsrq"`
+	NRSINR   string `xml:"nrsinr"`
+	NRBand   string `xml:"nrband"`
+	NREARFCN string `xml:"nrearfcn"`
+	NRPCI    string `xml:"nrpci"`
+
+	DLBandwidth string       `xml:"dlbandwidth"`
+	Other       []xmlElement `xml:",any"`
+}
+
+// xmlElement is a generic XML element.
+type xmlElement struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// Signal retrieves the network signal information as numeric values.
+func (c *Client) Signal() (*Signal, error) {
+	return decodeSignal(c.doReqBody("api/device/signal", nil))
+}
+
+// decodeSignal decodes the signal information from a response body.
+func decodeSignal(body []byte, err error) (*Signal, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	var x signalXML
+	if err := unmarshalXML(body, &x); err != nil {
+		return nil, err
+	}
+
+	sig := &Signal{
+		Mode:    parseInt(x.Mode),
+		RSRP:    parseSignalValue(x.RSRP),
+		RSRQ:    parseSignalValue(x.RSRQ),
+		RSSI:    parseSignalValue(x.RSSI),
+		SINR:    parseSignalValue(x.SINR),
+		Band:    parseInt(x.Band),
+		EARFCN:  x.EARFCN,
+		PCI:     parseInt(x.PCI),
+		CellID:  x.CellID,
+		RSCP:    parseSignalValue(x.RSCP),
+		ECIO:    parseSignalValue(x.ECIO),
+		NRRSRP:  parseSignalValue(x.NRRSRP),
+		NRRSRQ:  parseSignalValue(x.NRRSRQ),
+		NRSINR:  parseSignalValue(x.NRSINR),
+		NRBand:  parseInt(x.NRBand),
+		NRARFCN: x.NREARFCN,
+		NRPCI:   parseInt(x.NRPCI),
+	}
+	sig.Carriers = x.carriers()
+
+	return sig, nil
+}
+
+// carriers builds the component carriers from the primary cell values and
+// the numbered secondary cell values (ie, <scc1_band/>, <scc1_dlbandwidth/>,
+// <scc1_earfcn/>, <scc1_pci/>) reported by devices with carrier aggregation.
+func (x *signalXML) carriers() []Carrier {
+	scc := make(map[int]*Carrier)
+	for _, e := range x.Other {
+		name := e.XMLName.Local
+		if !strings.HasPrefix(name, "scc") {
+			continue
+		}
+		i := strings.IndexByte(name, '_')
+		if i == -1 {
+			continue
+		}
+		n, err := strconv.Atoi(name[3:i])
+		if err != nil {
+			continue
+		}
+		c, ok := scc[n]
+		if !ok {
+			c = new(Carrier)
+			scc[n] = c
+		}
+		switch name[i+1:] {
+		case "band":
+			c.Band = parseInt(e.Value)
+		case "dlbandwidth":
+			c.Bandwidth = parseSignalValue(e.Value)
+		case "earfcn":
+			c.EARFCN = e.Value
+		case "pci":
+			c.PCI = parseInt(e.Value)
+		}
+	}
+
+	// no carrier aggregation
+	if len(scc) == 0 {
+		return nil
+	}
+
+	carriers := []Carrier{{
+		Primary:   true,
+		Band:      parseInt(x.Band),
+		Bandwidth: parseSignalValue(x.DLBandwidth),
+		EARFCN:    x.EARFCN,
+		PCI:       parseInt(x.PCI),
+	}}
+	var nums []int
+	for n := range scc {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	for _, n := range nums {
+		carriers = append(carriers, *scc[n])
+	}
+
+	return carriers
+}
+
+// HasNR determines if the signal information includes 5G NR values.
+func (s *Signal) HasNR() bool {
+	return s.NRRSRP != 0 || s.NRBand != 0
+}
+
+// Bars returns a signal quality estimate from 0 (no signal) to 5 bars,
+// derived from the RSRP on LTE and 5G NR, the RSCP on UMTS, or the RSSI
+// otherwise.
+func (s *Signal) Bars() int {
+	bars := func(v float64, steps ...float64) int {
+		if v == 0 {
+			return 0
+		}
+		for i, step := range steps {
+			if v >= step {
+				return 5 - i
+			}
+		}
+		return 0
+	}
+
+	b := bars(s.RSRP, -85, -95, -105, -115, -120)
+	if nr := bars(s.NRRSRP, -85, -95, -105, -115, -120); nr > b {
+		b = nr
+	}
+	switch {
+	case b != 0 || s.RSRP != 0 || s.NRRSRP != 0:
+		return b
+	case s.RSCP != 0:
+		return bars(s.RSCP, -75, -85, -95, -105, -110)
+	}
+	return bars(s.RSSI, -65, -75, -85, -95, -105)
+}
+
+// parseSignalValue parses a signal value as reported by the device (ie,
+// "-97dBm", ">=-3dB", "<-115dBm"), returning 0 if the value cannot be parsed.
+func parseSignalValue(s string) float64 {
+	s = strings.TrimSpace(s)
+	s = strings.TrimLeft(s, "<>=")
+	s = strings.TrimRight(s, "dBmMHz ")
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
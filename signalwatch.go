@@ -0,0 +1,233 @@
+package hilink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SignalMetric extracts a value from a Signal, for use with a SignalRule.
+type SignalMetric func(*Signal) float64
+
+// SignalMetric values.
+var (
+	MetricRSRP SignalMetric = func(s *Signal) float64 { return s.RSRP }
+	MetricRSRQ SignalMetric = func(s *Signal) float64 { return s.RSRQ }
+	MetricSINR SignalMetric = func(s *Signal) float64 { return s.SINR }
+
+	// MetricScore is the composite QualityScore.
+	MetricScore SignalMetric = func(s *Signal) float64 { return float64(QualityScore(*s).Score) }
+)
+
+// SignalRule is a threshold based signal alert rule, for example:
+//
+//	SignalRule{Name: "weak", Metric: MetricRSRP, Threshold: -110, For: time.Minute, Hysteresis: 3}
+//
+// fires when RSRP stays below -110 dBm for a minute, and resolves once RSRP
+// rises back above -107 dBm.
+type SignalRule struct {
+	// Name is the rule name.
+	Name string
+
+	// Metric is the signal value the rule applies to.
+	Metric SignalMetric
+
+	// Threshold is the value at which the rule triggers.
+	Threshold float64
+
+	// Above triggers the rule when the value is above the threshold, instead
+	// of below.
+	Above bool
+
+	// For is the duration the threshold must be crossed before the alert
+	// fires.
+	For time.Duration
+
+	// Hysteresis is the margin past the threshold the value must return by
+	// before the alert resolves.
+	Hysteresis float64
+}
+
+// crossed determines if v crosses the rule threshold, offset by margin.
+func (r SignalRule) crossed(v, margin float64) bool {
+	if r.Above {
+		return v > r.Threshold-margin
+	}
+	return v < r.Threshold+margin
+}
+
+// SignalAlert is the event emitted when a SignalRule fires or resolves.
+type SignalAlert struct {
+	Rule   string    `json:"rule"`
+	Firing bool      `json:"firing"`
+	Value  float64   `json:"value"`
+	Time   time.Time `json:"time"`
+	Signal *Signal   `json:"signal"`
+}
+
+// SignalWatcher polls the signal information of a device, evaluating the
+// alert Rules on each poll.
+type SignalWatcher struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Interval is the poll interval. Defaults to 10 seconds.
+	Interval time.Duration
+
+	// Scheduler, when set, polls the signal information along with the
+	// other consumers of the device (see Scheduler).
+	Scheduler *Scheduler
+
+	// Rules are the alert rules.
+	Rules []SignalRule
+
+	// OnSignal is called with each retrieved Signal.
+	OnSignal func(*Signal)
+
+	// OnAlert is called when a rule fires or resolves.
+	OnAlert func(SignalAlert)
+
+	// OnError is called when the signal information cannot be retrieved.
+	OnError func(error)
+
+	rules map[int]*ruleState
+}
+
+// ruleState is the evaluation state of a rule.
+type ruleState struct {
+	since  time.Time
+	firing bool
+}
+
+// Run runs the watcher until the context is closed.
+func (w *SignalWatcher) Run(ctx context.Context) error {
+	if w.Client == nil {
+		return ErrNilClient
+	}
+
+	interval := w.Interval
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+
+	p := newPoller(w.Scheduler, w.Client, "api/device/signal", interval)
+	defer p.close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.C():
+		}
+
+		s, err := decodeSignal(p.fetch())
+		switch {
+		case err != nil && w.OnError != nil:
+			w.OnError(err)
+		case err == nil:
+			if w.OnSignal != nil {
+				w.OnSignal(s)
+			}
+			w.eval(s, time.Now())
+		}
+	}
+}
+
+// eval evaluates the rules against s.
+func (w *SignalWatcher) eval(s *Signal, now time.Time) {
+	if w.rules == nil {
+		w.rules = make(map[int]*ruleState)
+	}
+
+	for i, r := range w.Rules {
+		st, ok := w.rules[i]
+		if !ok {
+			st = new(ruleState)
+			w.rules[i] = st
+		}
+
+		v := r.Metric(s)
+		switch {
+		case !st.firing && r.crossed(v, 0):
+			if st.since.IsZero() {
+				st.since = now
+			}
+			if now.Sub(st.since) >= r.For {
+				st.firing = true
+				w.alert(r, true, v, s, now)
+			}
+
+		case !st.firing:
+			st.since = time.Time{}
+
+		case st.firing && !r.crossed(v, r.Hysteresis):
+			st.firing, st.since = false, time.Time{}
+			w.alert(r, false, v, s, now)
+		}
+	}
+}
+
+// alert emits an alert.
+func (w *SignalWatcher) alert(r SignalRule, firing bool, v float64, s *Signal, now time.Time) {
+	if w.OnAlert != nil {
+		w.OnAlert(SignalAlert{
+			Rule:   r.Name,
+			Firing: firing,
+			Value:  v,
+			Time:   now,
+			Signal: s,
+		})
+	}
+}
+
+// SignalAlertWebhook returns a SignalWatcher OnAlert func that POSTs each
+// alert as JSON to rawurl, within DefaultWebhookTimeout. Delivery errors are
+// passed to onError, if not nil.
+func SignalAlertWebhook(rawurl string, onError func(error)) func(SignalAlert) {
+	return func(a SignalAlert) {
+		if err := postWebhook(rawurl, a); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// DefaultWebhookTimeout is the timeout of the webhook deliveries of the
+// watchers (ie, SignalAlertWebhook), that block the watcher while pending.
+const DefaultWebhookTimeout = 30 * time.Second
+
+// webhookClient is the HTTP client of the webhook deliveries.
+var webhookClient = &http.Client{Timeout: DefaultWebhookTimeout}
+
+// postWebhook POSTs v as JSON to the webhook rawurl.
+func postWebhook(rawurl string, v interface{}) error {
+	return postJSON(context.Background(), webhookClient, rawurl, v)
+}
+
+// postJSON POSTs v as JSON to rawurl.
+func postJSON(ctx context.Context, client *http.Client, rawurl string, v interface{}) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", rawurl, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%w: %s", ErrBadStatusCode, res.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,368 @@
+package hilink
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// gsm7Basic and gsm7Extension are the characters of the GSM 03.38 default
+// alphabet and its extension table. Extension characters are encoded as an
+// escape followed by the character, taking two septets.
+const (
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extension = "\f^{}\\[~]|€"
+)
+
+// IsGSM7 determines if msg can be encoded with the GSM 03.38 default
+// alphabet. Messages with other characters are sent as UCS-2.
+func IsGSM7(msg string) bool {
+	for _, r := range msg {
+		if !strings.ContainsRune(gsm7Basic, r) && !strings.ContainsRune(gsm7Extension, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// SmsLength returns the length of msg as calculated by the device: the
+// number of septets for GSM-7 messages (counting extension characters
+// twice), or the number of UTF-16 code units for UCS-2 messages.
+func SmsLength(msg string) int {
+	if !IsGSM7(msg) {
+		return len(utf16.Encode([]rune(msg)))
+	}
+
+	n := 0
+	for _, r := range msg {
+		n++
+		if strings.ContainsRune(gsm7Extension, r) {
+			n++
+		}
+	}
+	return n
+}
+
+// SmsEncoding is the encoding of an SMS message.
+type SmsEncoding int
+
+// SmsEncoding values.
+const (
+	SmsEncodingGSM7 SmsEncoding = iota
+	SmsEncodingUCS2
+)
+
+// String satisfies the fmt.Stringer interface.
+func (e SmsEncoding) String() string {
+	switch e {
+	case SmsEncodingGSM7:
+		return "GSM-7"
+	case SmsEncodingUCS2:
+		return "UCS-2"
+	}
+	return fmt.Sprintf("SmsEncoding(%d)", int(e))
+}
+
+// SmsEncodingOf returns the encoding msg is sent with: GSM-7 when all its
+// characters are in the GSM 03.38 default alphabet (see IsGSM7), otherwise
+// UCS-2 (ie, Cyrillic or emoji content).
+func SmsEncodingOf(msg string) SmsEncoding {
+	if IsGSM7(msg) {
+		return SmsEncodingGSM7
+	}
+	return SmsEncodingUCS2
+}
+
+// SmsSegments returns the number of SMS segments msg is sent as: 1 for
+// messages of up to 160 GSM-7 septets (or 70 UCS-2 characters), and
+// otherwise the number of 153 septet (or 67 character) segments of the
+// concatenated SMS.
+func SmsSegments(msg string) int {
+	return len(smsSplit(msg))
+}
+
+// SmsMaxSegments is the maximum number of segments of a message sent with
+// SmsSend.
+const SmsMaxSegments = 10
+
+// smsSplit splits msg into the segments of a concatenated SMS: a single
+// segment of up to 160 GSM-7 septets (or 70 UCS-2 code units), or segments
+// of 153 septets (or 67 code units), leaving room for the concatenation
+// header. Extension characters and surrogate pairs are not split.
+func smsSplit(msg string) []string {
+	gsm7 := SmsEncodingOf(msg) == SmsEncodingGSM7
+	single, part := 160, 153
+	if !gsm7 {
+		single, part = 70, 67
+	}
+	if SmsLength(msg) <= single {
+		return []string{msg}
+	}
+
+	var segs []string
+	start, n := 0, 0
+	for i, r := range msg {
+		w := 1
+		switch {
+		case gsm7 && strings.ContainsRune(gsm7Extension, r):
+			w = 2
+		case !gsm7 && r >= 0x10000:
+			w = 2
+		}
+		if n+w > part {
+			segs = append(segs, msg[start:i])
+			start, n = i, 0
+		}
+		n += w
+	}
+	return append(segs, msg[start:])
+}
+
+// SmsMessage is an SMS message.
+type SmsMessage struct {
+	// Index is the message index, increasing with each stored message.
+	Index int
+
+	// Phone is the phone number of the sender (or recipient, for sent
+	// messages).
+	Phone string
+
+	// Content is the message text.
+	Content string
+
+	// Date is the message date, in the device's time zone (see ParseTime).
+	Date time.Time
+
+	// Status is the message status (Smstat), ie 0 for unread, and 1 for
+	// read inbox messages.
+	Status int
+
+	// SmsType is the message type reported by the device.
+	SmsType int
+
+	// Part and Parts are the part number, starting at 1, and the number of
+	// parts of a segment of a concatenated message, when the device lists
+	// the segments separately with a "(1/3)" style marker. The marker is
+	// removed from the content. Both are zero for other messages.
+	Part, Parts int
+}
+
+// smsPartMarker matches the part marker of a concatenated message segment.
+var smsPartMarker = regexp.MustCompile(`^\(([1-9][0-9]?)/([1-9][0-9]?)\)\s*`)
+
+// smsMessageXML is the raw SMS message returned by the device.
+type smsMessageXML struct {
+	Index   string `xml:"Index"`
+	Phone   string `xml:"Phone"`
+	Content string `xml:"Content"`
+	Date    string `xml:"Date"`
+	Smstat  string `xml:"Smstat"`
+	SmsType string `xml:"SmsType"`
+}
+
+// message converts the raw message, parsing the date in the device's time
+// zone. Unparsable dates are left zero.
+func (x smsMessageXML) message(c *Client) SmsMessage {
+	m := SmsMessage{
+		Index:   parseInt(x.Index),
+		Phone:   x.Phone,
+		Content: x.Content,
+		Status:  parseInt(x.Smstat),
+		SmsType: parseInt(x.SmsType),
+	}
+	m.Date, _ = c.ParseTime(x.Date)
+
+	// part marker
+	if sm := smsPartMarker.FindStringSubmatch(m.Content); sm != nil {
+		part, parts := parseInt(sm[1]), parseInt(sm[2])
+		if part <= parts && parts > 1 {
+			m.Part, m.Parts = part, parts
+			m.Content = m.Content[len(sm[0]):]
+		}
+	}
+
+	return m
+}
+
+// SmsMaxReadCount is the maximum number of messages per page of SmsList.
+const SmsMaxReadCount = 50
+
+// SmsListOptions are the options of SmsList.
+type SmsListOptions struct {
+	// BoxType is the box listed. Defaults to SmsBoxTypeInbox.
+	BoxType SmsBoxType
+
+	// Page is the page listed, starting at 1. Defaults to 1.
+	Page uint
+
+	// Count is the number of messages per page, up to SmsMaxReadCount.
+	// Defaults to 20.
+	Count uint
+
+	// SortByName sorts by phone number, instead of by date.
+	SortByName bool
+
+	// Ascending sorts oldest (or lowest) first.
+	Ascending bool
+
+	// UnreadPreferred lists unread messages first.
+	UnreadPreferred bool
+}
+
+// request returns the sms-list request, applying the defaults.
+func (o SmsListOptions) request() []byte {
+	if o.BoxType == 0 {
+		o.BoxType = SmsBoxTypeInbox
+	}
+	if o.Page == 0 {
+		o.Page = 1
+	}
+	if o.Count == 0 {
+		o.Count = 20
+	}
+
+	// note: the order is important!
+	return SimpleRequestXML(
+		"PageIndex", fmt.Sprintf("%d", o.Page),
+		"ReadCount", fmt.Sprintf("%d", o.Count),
+		"BoxType", fmt.Sprintf("%d", o.BoxType),
+		"SortType", boolToString(o.SortByName),
+		"Ascending", boolToString(o.Ascending),
+		"UnreadPreferred", boolToString(o.UnreadPreferred),
+	)
+}
+
+// SmsMessages retrieves list of SMS in an inbox (see SmsList), as typed
+// messages, with the dates parsed in the device's time zone.
+func (c *Client) SmsMessages(opts SmsListOptions) ([]SmsMessage, error) {
+	var res struct {
+		Messages []smsMessageXML `xml:"Messages>Message"`
+	}
+	if err := c.doReqXML("api/sms/sms-list", opts.request(), &res); err != nil {
+		return nil, err
+	}
+
+	msgs := make([]SmsMessage, len(res.Messages))
+	for i, x := range res.Messages {
+		msgs[i] = x.message(c)
+	}
+	return msgs, nil
+}
+
+// smsIndexes returns the Index request pairs of the message ids.
+func smsIndexes(ids []string) []string {
+	pairs := make([]string, 0, 2*len(ids))
+	for _, id := range ids {
+		pairs = append(pairs, "Index", id)
+	}
+	return pairs
+}
+
+// SmsDeleteAll deletes all messages of a box, returning the number of
+// deleted messages. Messages are deleted SmsMaxReadCount at a time.
+func (c *Client) SmsDeleteAll(box SmsBoxType) (int, error) {
+	msgs, err := c.SmsListAll(box)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for len(msgs) > 0 {
+		batch := msgs
+		if len(batch) > SmsMaxReadCount {
+			batch = batch[:SmsMaxReadCount]
+		}
+		msgs = msgs[len(batch):]
+
+		ids := make([]string, len(batch))
+		for i, m := range batch {
+			ids[i] = strconv.Itoa(m.Index)
+		}
+		if err := checkOK(c.SmsDelete(ids...)); err != nil {
+			return n, err
+		}
+		n += len(batch)
+	}
+	return n, nil
+}
+
+// SmsListAll retrieves all messages of a box, newest first, paging through
+// the box (see SmsIter).
+func (c *Client) SmsListAll(box SmsBoxType) ([]SmsMessage, error) {
+	var msgs []SmsMessage
+	it := c.SmsIter(SmsListOptions{BoxType: box})
+	for it.Next() {
+		msgs = append(msgs, it.Message())
+	}
+	return msgs, it.Err()
+}
+
+// SmsIter returns an iterator over the messages of a box, retrieving the
+// pages as needed, starting at the page of the options, for example:
+//
+//	it := client.SmsIter(SmsListOptions{BoxType: SmsBoxTypeInbox})
+//	for it.Next() {
+//		m := it.Message()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+//
+// Count defaults to, and is capped at, SmsMaxReadCount, as the device
+// returns at most SmsMaxReadCount messages per page. Messages stored or
+// deleted while iterating may shift the pages, so that messages are skipped
+// or repeated.
+func (c *Client) SmsIter(opts SmsListOptions) *SmsIterator {
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	if opts.Count == 0 || opts.Count > SmsMaxReadCount {
+		opts.Count = SmsMaxReadCount
+	}
+	return &SmsIterator{client: c, opts: opts}
+}
+
+// SmsIterator is an iterator over the messages of a box (see SmsIter).
+type SmsIterator struct {
+	client *Client
+	opts   SmsListOptions
+	msgs   []SmsMessage
+	msg    SmsMessage
+	done   bool
+	err    error
+}
+
+// Next advances to the next message, retrieving the next page when needed.
+// It returns false when there are no more messages, or on error (see Err).
+func (it *SmsIterator) Next() bool {
+	if len(it.msgs) == 0 && !it.done {
+		it.msgs, it.err = it.client.SmsMessages(it.opts)
+		if it.err != nil {
+			it.done, it.msgs = true, nil
+			return false
+		}
+		// a short page is the last
+		it.done = uint(len(it.msgs)) < it.opts.Count
+		it.opts.Page++
+	}
+	if len(it.msgs) == 0 {
+		return false
+	}
+	it.msg, it.msgs = it.msgs[0], it.msgs[1:]
+	return true
+}
+
+// Message returns the current message.
+func (it *SmsIterator) Message() SmsMessage {
+	return it.msg
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SmsIterator) Err() error {
+	return it.err
+}
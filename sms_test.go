@@ -0,0 +1,68 @@
+package hilink
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSmsSendEscapes(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+
+	ok, err := d.client(t).SmsSend("AT&T <3", "+6281234", "+6285678")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected ok")
+	}
+
+	req := d.request(t, "/api/sms/send-sms")
+	if v := req["Content"]; v != "AT&T <3" {
+		t.Errorf("expected content %q, got: %v", "AT&T <3", v)
+	}
+	phones, ok := req["Phones"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected Phones element, got: %v", req)
+	}
+	if l, ok := phones["Phone"].([]interface{}); !ok || len(l) != 2 || l[0] != "+6281234" {
+		t.Errorf("expected 2 phones, got: %v", phones["Phone"])
+	}
+}
+
+func TestSmsIterCount(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+
+	// a full page of SmsMaxReadCount messages, then a short page
+	page := func(n int) string {
+		var b strings.Builder
+		b.WriteString("<response><Count>" + strconv.Itoa(n) + "</Count><Messages>")
+		for i := 0; i < n; i++ {
+			b.WriteString("<Message><Index>" + strconv.Itoa(40000+i) + "</Index></Message>")
+		}
+		b.WriteString("</Messages></response>")
+		return b.String()
+	}
+	d.Sequences["/api/sms/sms-list"] = []string{page(SmsMaxReadCount), page(10)}
+
+	it := d.client(t).SmsIter(SmsListOptions{Count: 100})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if n != SmsMaxReadCount+10 {
+		t.Errorf("expected %d messages, got: %d", SmsMaxReadCount+10, n)
+	}
+	if c := d.count("/api/sms/sms-list"); c != 2 {
+		t.Errorf("expected 2 pages, got: %d", c)
+	}
+	req := d.request(t, "/api/sms/sms-list")
+	if req["ReadCount"] != strconv.Itoa(SmsMaxReadCount) || req["PageIndex"] != "2" {
+		t.Errorf("expected page 2 of %d messages, got: %v", SmsMaxReadCount, req)
+	}
+}
@@ -0,0 +1,121 @@
+package hilink
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SmsDraftSave saves an SMS to the drafts, without sending it.
+func (c *Client) SmsDraftSave(msg string, to ...string) (bool, error) {
+	return c.smsDraftSave("-1", msg, to...)
+}
+
+// SmsDraftUpdate replaces the content and recipients of the draft.
+func (c *Client) SmsDraftUpdate(index int, msg string, to ...string) (bool, error) {
+	return c.smsDraftSave(strconv.Itoa(index), msg, to...)
+}
+
+// smsDraftSave saves a draft.
+func (c *Client) smsDraftSave(index, msg string, to ...string) (bool, error) {
+	if SmsSegments(msg) > SmsMaxSegments {
+		return false, ErrMessageTooLong
+	}
+	return c.smsRequest("api/sms/save-sms", index, msg, to...)
+}
+
+// SmsDrafts retrieves all drafts, newest first.
+func (c *Client) SmsDrafts() ([]SmsMessage, error) {
+	return c.SmsListAll(SmsBoxTypeDraft)
+}
+
+// SmsDraftSend sends the draft to its recipients. The device moves the sent
+// message to the outbox.
+func (c *Client) SmsDraftSend(index int) (bool, error) {
+	drafts, err := c.SmsDrafts()
+	if err != nil {
+		return false, err
+	}
+
+	for _, d := range drafts {
+		if d.Index != index {
+			continue
+		}
+		var to []string
+		for _, phone := range strings.Split(d.Phone, ";") {
+			if phone = strings.TrimSpace(phone); phone != "" {
+				to = append(to, phone)
+			}
+		}
+		if SmsSegments(d.Content) > SmsMaxSegments {
+			return false, ErrMessageTooLong
+		}
+		return c.smsRequest("api/sms/send-sms", strconv.Itoa(index), d.Content, to...)
+	}
+	return false, fmt.Errorf("%w %q", ErrResourceNotFound, strconv.Itoa(index))
+}
+
+// SmsOutbox retrieves all sent messages, newest first.
+func (c *Client) SmsOutbox() ([]SmsMessage, error) {
+	return c.SmsListAll(SmsBoxTypeOutbox)
+}
+
+// SmsCopyFromSim copies the messages stored on the SIM to the device
+// storage, ie before moving the SIM to another modem. The WebUI API has no
+// endpoint to copy messages to the SIM.
+func (c *Client) SmsCopyFromSim() (bool, error) {
+	return c.smsBackupSim(false)
+}
+
+// SmsMoveFromSim moves the messages stored on the SIM to the device storage,
+// freeing the SIM storage (see SmsCopyFromSim).
+func (c *Client) SmsMoveFromSim() (bool, error) {
+	return c.smsBackupSim(true)
+}
+
+// smsBackupSim copies or moves the SIM messages to the device storage.
+func (c *Client) smsBackupSim(move bool) (bool, error) {
+	return c.doReqCheckOK("api/sms/backup-sim", SimpleRequestXML(
+		"IsMove", boolToString(move),
+		"Date", time.Now().In(c.location()).Format(TimeLayout),
+	))
+}
+
+// SmsCapacity is the message capacity of an SMS storage.
+type SmsCapacity struct {
+	// Used is the number of stored messages.
+	Used int
+
+	// Max is the maximum number of stored messages.
+	Max int
+}
+
+// Free returns the number of messages that can still be stored.
+func (s SmsCapacity) Free() int {
+	if s.Used >= s.Max {
+		return 0
+	}
+	return s.Max - s.Used
+}
+
+// SmsSimCapacity retrieves the message capacity of the SIM storage (see
+// SmsCounts).
+func (c *Client) SmsSimCapacity() (*SmsCapacity, error) {
+	s, err := c.SmsCounts()
+	if err != nil {
+		return nil, err
+	}
+	return &SmsCapacity{Used: s.SimUsed, Max: s.SimMax}, nil
+}
+
+// SmsLocalCapacity retrieves the message capacity of the device storage (see
+// SmsCounts).
+func (c *Client) SmsLocalCapacity() (*SmsCapacity, error) {
+	s, err := c.SmsCounts()
+	if err != nil {
+		return nil, err
+	}
+	used := s.LocalInbox + s.LocalOutbox + s.LocalDraft + s.LocalDeleted
+	return &SmsCapacity{Used: used, Max: s.LocalMax}, nil
+}
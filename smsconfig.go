@@ -0,0 +1,72 @@
+package hilink
+
+import (
+	"strconv"
+)
+
+// SmsValidityMax is the maximum SMS validity period, as encoded by the
+// device.
+const SmsValidityMax = 10752
+
+// SmsSettings are the SMS settings of a device.
+type SmsSettings struct {
+	// SCA is the service center address (number). Empty uses the number
+	// stored on the SIM.
+	SCA string
+
+	// DeliveryReport requests delivery reports for sent messages.
+	DeliveryReport bool
+
+	// SaveMode is the storage policy of received messages (ie, whether the
+	// oldest messages are overwritten when the storage is full), in the
+	// device's encoding, where the firmware supports it.
+	SaveMode int
+
+	// Validity is the validity period of sent messages, in the device's
+	// encoding (ie, SmsValidityMax).
+	Validity int
+}
+
+// SmsSettings retrieves the SMS settings (see SmsConfig).
+func (c *Client) SmsSettings() (*SmsSettings, error) {
+	d, err := c.SmsConfig()
+	if err != nil {
+		return nil, err
+	}
+	str := func(k string) string {
+		s, _ := d[k].(string)
+		return s
+	}
+	return &SmsSettings{
+		SCA:            str("Sca"),
+		DeliveryReport: str("UseSReport") == "1",
+		SaveMode:       parseInt(str("SaveMode")),
+		Validity:       parseInt(str("Validity")),
+	}, nil
+}
+
+// SmsConfigSet sets the SMS settings, retaining the other settings of the
+// SMS configuration (see SmsConfig).
+func (c *Client) SmsConfigSet(s SmsSettings) (bool, error) {
+	if s.SCA != "" {
+		if err := ValidatePhoneNumber(s.SCA); err != nil {
+			return false, err
+		}
+	}
+	if s.Validity < 0 || s.SaveMode < 0 {
+		return false, ErrInvalidValue
+	}
+
+	d, err := c.SmsConfig()
+	if err != nil {
+		return false, err
+	}
+	d["Sca"] = s.SCA
+	d["UseSReport"] = boolToString(s.DeliveryReport)
+	d["SaveMode"] = strconv.Itoa(s.SaveMode)
+	if s.Validity != 0 {
+		d["Validity"] = strconv.Itoa(s.Validity)
+	}
+
+	return c.doReqCheckOK("api/sms/config", d)
+}
@@ -0,0 +1,218 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSmsSendTimeout is the default timeout of SmsSendAndConfirm,
+	// when the context has no deadline.
+	DefaultSmsSendTimeout = time.Minute
+
+	// smsSendPollInterval is the send status poll interval of
+	// SmsSendAndConfirm.
+	smsSendPollInterval = time.Second
+)
+
+// SmsSendResult is the per-recipient result of a sent SMS message.
+type SmsSendResult struct {
+	// Sent are the recipients the message was sent to (SucPhone).
+	Sent []string
+
+	// Failed are the recipients the message could not be sent to
+	// (FailPhone).
+	Failed []string
+
+	// Pending are the recipients without a result when waiting stopped.
+	Pending []string
+}
+
+// OK determines if the message was sent to all recipients.
+func (r *SmsSendResult) OK() bool {
+	return len(r.Failed) == 0 && len(r.Pending) == 0
+}
+
+// smsSendStatusXML is the send status returned by the device.
+type smsSendStatusXML struct {
+	Phone      string `xml:"Phone"`
+	SucPhone   string `xml:"SucPhone"`
+	FailPhone  string `xml:"FailPhone"`
+	TotalCount string `xml:"TotalCount"`
+	CurIndex   string `xml:"CurIndex"`
+}
+
+// SmsSendAndConfirm sends an SMS (see SmsSend), and polls the send status
+// until the device reports the result for every recipient. When the context
+// has no deadline, DefaultSmsSendTimeout applies.
+//
+// A send status left by an earlier message is ignored, until the status
+// changes. While the send status is unchanged, or not supported by the
+// firmware, the outbox is polled instead, the recipients of the message
+// stored in the outbox being reported as sent.
+//
+// The result is returned with the context error when waiting stops before
+// all results are reported, listing the remaining recipients as pending.
+func (c *Client) SmsSendAndConfirm(ctx context.Context, msg string, to ...string) (*SmsSendResult, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultSmsSendTimeout)
+		defer cancel()
+	}
+
+	w, err := c.smsSendWatch(msg, to)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOK(c.SmsSend(msg, to...)); err != nil {
+		return nil, err
+	}
+
+	t := time.NewTicker(smsSendPollInterval)
+	defer t.Stop()
+
+	res := &SmsSendResult{Pending: to}
+	for {
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-t.C:
+		}
+
+		r, err := w.poll()
+		if err != nil {
+			return res, err
+		}
+		if r != nil {
+			res = r
+		}
+		if len(res.Pending) == 0 {
+			return res, nil
+		}
+	}
+}
+
+// smsSendWatch is the state of the send status and outbox polls of a sent
+// message.
+type smsSendWatch struct {
+	c   *Client
+	msg string
+	to  []string
+
+	// status is set when the send status is supported, prev being the
+	// status before sending, and started set once the status changed
+	status  bool
+	prev    smsSendStatusXML
+	started bool
+
+	// outbox is the newest outbox message index before sending, or -1
+	// when the outbox cannot be listed
+	outbox int
+}
+
+// smsSendWatch retrieves the send status and the newest outbox message,
+// before sending msg.
+func (c *Client) smsSendWatch(msg string, to []string) (*smsSendWatch, error) {
+	w := &smsSendWatch{c: c, msg: msg, to: to, status: true, outbox: -1}
+
+	var apiErr *APIError
+	switch err := c.doReqXML("api/sms/send-status", nil, &w.prev); {
+	case errors.As(err, &apiErr):
+		w.status = false
+	case err != nil:
+		return nil, err
+	}
+
+	// newest first
+	if msgs, err := c.SmsMessages(SmsListOptions{BoxType: SmsBoxTypeOutbox, Count: 1}); err == nil {
+		w.outbox = 0
+		for _, m := range msgs {
+			if m.Index > w.outbox {
+				w.outbox = m.Index
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// poll polls the send status, or the outbox, returning the result, or nil
+// when unknown.
+func (w *smsSendWatch) poll() (*SmsSendResult, error) {
+	if w.status {
+		var x smsSendStatusXML
+		err := w.c.doReqXML("api/sms/send-status", nil, &x)
+		var apiErr *APIError
+		switch {
+		case errors.As(err, &apiErr):
+			w.status = false
+		case err != nil:
+			return nil, err
+		case w.started || x != w.prev:
+			w.started = true
+			return smsSendResult(x, w.to), nil
+		}
+	}
+	if w.outbox < 0 {
+		return nil, nil
+	}
+
+	// the segments of long messages may be stored separately
+	msgs, err := w.c.SmsMessages(SmsListOptions{BoxType: SmsBoxTypeOutbox})
+	if err != nil {
+		return nil, err
+	}
+	sent := make(map[string]bool)
+	for _, m := range msgs {
+		if m.Index > w.outbox && m.Content != "" && strings.Contains(w.msg, m.Content) {
+			sent[m.Phone] = true
+		}
+	}
+
+	res := new(SmsSendResult)
+	for _, phone := range w.to {
+		if sent[phone] {
+			res.Sent = append(res.Sent, phone)
+		} else {
+			res.Pending = append(res.Pending, phone)
+		}
+	}
+	return res, nil
+}
+
+// smsSendResult builds the result for the recipients from the send status.
+// When the device reports the send as complete, recipients missing from
+// both lists are considered failed.
+func smsSendResult(x smsSendStatusXML, to []string) *SmsSendResult {
+	sent, failed := smsPhones(x.SucPhone), smsPhones(x.FailPhone)
+	total := parseInt(x.TotalCount)
+	done := total != 0 && len(sent)+len(failed) >= total
+
+	res := new(SmsSendResult)
+	for _, phone := range to {
+		switch {
+		case sent[phone]:
+			res.Sent = append(res.Sent, phone)
+		case failed[phone], done:
+			res.Failed = append(res.Failed, phone)
+		default:
+			res.Pending = append(res.Pending, phone)
+		}
+	}
+	return res
+}
+
+// smsPhones splits a ";" or "," separated phone list.
+func smsPhones(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, phone := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ';' || r == ','
+	}) {
+		if phone = strings.TrimSpace(phone); phone != "" {
+			m[phone] = true
+		}
+	}
+	return m
+}
@@ -0,0 +1,80 @@
+package hilink
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSmsSendAndConfirmStale(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+
+	// the status of an earlier send is reported until this send starts
+	stale := `<response><Phone>+15550000</Phone><SucPhone>+15550000</SucPhone><FailPhone></FailPhone><TotalCount>1</TotalCount><CurIndex>1</CurIndex></response>`
+	d.Sequences["/api/sms/send-status"] = []string{
+		stale,
+		stale,
+		`<response><Phone>+15551111;+15552222</Phone><SucPhone></SucPhone><FailPhone></FailPhone><TotalCount>2</TotalCount><CurIndex>0</CurIndex></response>`,
+		`<response><Phone>+15551111;+15552222</Phone><SucPhone>+15551111</SucPhone><FailPhone>+15552222</FailPhone><TotalCount>2</TotalCount><CurIndex>2</CurIndex></response>`,
+	}
+	d.Responses["/api/sms/sms-list"] = `<response><Count>0</Count><Messages></Messages></response>`
+
+	res, err := d.client(t).SmsSendAndConfirm(context.Background(), "hello", "+15551111", "+15552222")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp := &SmsSendResult{Sent: []string{"+15551111"}, Failed: []string{"+15552222"}}
+	if !reflect.DeepEqual(res, exp) {
+		t.Errorf("expected %+v, got: %+v", exp, res)
+	}
+}
+
+func TestSmsSendAndConfirmOutbox(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+
+	// firmware without send status, the sent message being stored in the
+	// outbox
+	d.Responses["/api/sms/send-status"] = `<error><code>100002</code><message></message></error>`
+	d.Sequences["/api/sms/sms-list"] = []string{
+		`<response><Count>1</Count><Messages>` +
+			`<Message><Index>5</Index><Phone>+15551111</Phone><Content>hello</Content></Message>` +
+			`</Messages></response>`,
+		`<response><Count>3</Count><Messages>` +
+			`<Message><Index>7</Index><Phone>+15552222</Phone><Content>hello</Content></Message>` +
+			`<Message><Index>6</Index><Phone>+15551111</Phone><Content>hello</Content></Message>` +
+			`<Message><Index>5</Index><Phone>+15551111</Phone><Content>hello</Content></Message>` +
+			`</Messages></response>`,
+	}
+
+	res, err := d.client(t).SmsSendAndConfirm(context.Background(), "hello", "+15551111", "+15552222")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	exp := &SmsSendResult{Sent: []string{"+15551111", "+15552222"}}
+	if !reflect.DeepEqual(res, exp) {
+		t.Errorf("expected %+v, got: %+v", exp, res)
+	}
+}
+
+func TestSmsSendAndConfirmPending(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+
+	// an unchanged completed status is never taken as the result
+	d.Responses["/api/sms/send-status"] = `<response><Phone>+15551111</Phone><SucPhone></SucPhone><FailPhone>+15551111</FailPhone><TotalCount>1</TotalCount><CurIndex>1</CurIndex></response>`
+	d.Responses["/api/sms/sms-list"] = `<response><Count>0</Count><Messages></Messages></response>`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	res, err := d.client(t).SmsSendAndConfirm(ctx, "hello", "+15551111")
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded, got: %v", err)
+	}
+	exp := &SmsSendResult{Pending: []string{"+15551111"}}
+	if !reflect.DeepEqual(res, exp) {
+		t.Errorf("expected %+v, got: %+v", exp, res)
+	}
+}
@@ -0,0 +1,183 @@
+package hilink
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SmsWatcher polls the inbox of a device, delivering newly received SMS
+// messages, oldest first, for example:
+//
+//	msgs := make(chan SmsMessage)
+//	w := &SmsWatcher{Client: client, C: msgs, Delete: true}
+//	go w.Run(ctx)
+//	for m := range msgs {
+//		// ...
+//	}
+//
+// The inbox is listed when the device notifies unread messages (see
+// Notifications), and on the first poll.
+type SmsWatcher struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// C is the channel the new messages are sent to. Sends block until the
+	// message is received, or the context is closed. C is closed when Run
+	// returns.
+	C chan<- SmsMessage
+
+	// Handle, when set, is called with each new message instead of sending
+	// it to C. The message is acknowledged (its index persisted, and marked
+	// read or deleted) only when Handle succeeds; otherwise the error is
+	// reported to OnError, and the message is delivered again on the next
+	// poll, before any newer message.
+	Handle func(context.Context, SmsMessage) error
+
+	// Interval is the poll interval. Defaults to 10 seconds.
+	Interval time.Duration
+
+	// Scheduler, when set, polls the notification status along with the
+	// other consumers of the device (see Scheduler). The inbox is listed by
+	// the watcher.
+	Scheduler *Scheduler
+
+	// Count is the number of inbox messages listed per poll. Defaults to
+	// 50.
+	Count uint
+
+	// MarkRead marks delivered messages as read.
+	MarkRead bool
+
+	// Delete deletes delivered messages from the device, preventing the
+	// storage from filling up.
+	Delete bool
+
+	// Store, when set, persists the index of the last delivered message, so
+	// that messages are not delivered again after a restart. Otherwise,
+	// the messages already in the inbox when the watcher starts are
+	// delivered.
+	Store Store
+
+	// OnError is called when the inbox cannot be polled, a message cannot be
+	// handled, or a delivered message cannot be marked read or deleted.
+	OnError func(error)
+
+	last   int
+	loaded bool
+}
+
+// smsLastIndexKey is the Store key of the last delivered message index.
+const smsLastIndexKey = "sms/last-index"
+
+// Run runs the watcher until the context is closed.
+func (w *SmsWatcher) Run(ctx context.Context) error {
+	if w.Client == nil {
+		return ErrNilClient
+	}
+	if w.C != nil {
+		defer close(w.C)
+	}
+
+	interval := w.Interval
+	if interval == 0 {
+		interval = 10 * time.Second
+	}
+
+	p := newPoller(w.Scheduler, w.Client, "api/monitoring/check-notifications", interval)
+	defer p.close()
+
+	first := true
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.C():
+		}
+
+		n, err := decodeNotifications(p.fetch())
+		switch {
+		case first, err == nil && n.UnreadMessages != 0:
+			err = w.poll(ctx)
+		}
+		if err != nil && ctx.Err() == nil && w.OnError != nil {
+			w.OnError(err)
+		} else if err == nil {
+			first = false
+		}
+	}
+}
+
+// poll lists the inbox and delivers the new messages.
+func (w *SmsWatcher) poll(ctx context.Context) error {
+	// restore last delivered index
+	if !w.loaded && w.Store != nil {
+		v, ok, err := w.Store.Get(smsLastIndexKey)
+		if err != nil {
+			return err
+		}
+		if ok {
+			w.last = parseInt(v)
+		}
+	}
+	w.loaded = true
+
+	count := w.Count
+	if count == 0 {
+		count = 50
+	}
+	msgs, err := w.Client.SmsMessages(SmsListOptions{Count: count})
+	if err != nil {
+		return err
+	}
+
+	// oldest first
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].Index < msgs[j].Index
+	})
+	for _, m := range msgs {
+		if m.Index <= w.last {
+			continue
+		}
+
+		if err := w.deliver(ctx, m); err != nil {
+			return err
+		}
+
+		w.last = m.Index
+		if w.Store != nil {
+			if err := w.Store.Set(smsLastIndexKey, strconv.Itoa(m.Index)); err != nil {
+				return err
+			}
+		}
+
+		id := strconv.Itoa(m.Index)
+		switch {
+		case w.Delete:
+			err = checkOK(w.Client.SmsDelete(id))
+		case w.MarkRead && m.Status == 0:
+			err = checkOK(w.Client.SmsReadSet(id))
+		}
+		if err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+	}
+
+	return nil
+}
+
+// deliver delivers the message to Handle, or C.
+func (w *SmsWatcher) deliver(ctx context.Context, m SmsMessage) error {
+	switch {
+	case w.Handle != nil:
+		return w.Handle(ctx, m)
+	case w.C != nil:
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case w.C <- m:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSmsWatcherHandleAck(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/sms/sms-list"] = `<response><Count>2</Count><Messages>
+<Message><Smstat>0</Smstat><Index>40002</Index><Phone>+6281234</Phone><Content>second</Content><Date>2026-01-02 03:04:06</Date></Message>
+<Message><Smstat>0</Smstat><Index>40001</Index><Phone>+6281234</Phone><Content>first</Content><Date>2026-01-02 03:04:05</Date></Message>
+</Messages></response>`
+
+	var handled []int
+	fail := true
+	store := NewMemoryStore()
+	w := &SmsWatcher{
+		Client: d.client(t),
+		Store:  store,
+		Handle: func(ctx context.Context, m SmsMessage) error {
+			handled = append(handled, m.Index)
+			if fail {
+				return errors.New("delivery failed")
+			}
+			return nil
+		},
+	}
+
+	// failed deliveries are not acknowledged
+	if err := w.poll(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok, _ := store.Get(smsLastIndexKey); ok {
+		t.Error("expected no persisted index")
+	}
+
+	// and are delivered again, in order
+	fail = false
+	if err := w.poll(context.Background()); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if exp := []int{40001, 40001, 40002}; len(handled) != len(exp) || handled[0] != exp[0] || handled[1] != exp[1] || handled[2] != exp[2] {
+		t.Errorf("expected handled %v, got: %v", exp, handled)
+	}
+	if v, _, _ := store.Get(smsLastIndexKey); v != "40002" {
+		t.Errorf("expected persisted index 40002, got: %q", v)
+	}
+}
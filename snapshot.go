@@ -0,0 +1,290 @@
+package hilink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Snapshot is a snapshot of device data, keyed by endpoint (ie,
+// "wlan/basic-settings"). A Snapshot marshals to normalized JSON, with keys
+// in sorted order.
+type Snapshot map[string]XMLData
+
+// settingsEndpoints are the read endpoints of the device settings.
+var settingsEndpoints = []string{
+	"ddns/ddns-list",
+	"device/fastbootswitch",
+	"device/logsetting",
+	"device/powersaveswitch",
+	"dhcp/settings",
+	"dialup/connection",
+	"dialup/dialup-feature-switch",
+	"dialup/mobile-dataswitch",
+	"dialup/profiles",
+	"global/module-switch",
+	"monitoring/start_date",
+	"monitoring/statistic-feature-switch",
+	"net/net-mode",
+	"net/nr-mode",
+	"security/dmz",
+	"security/firewall-switch",
+	"security/nat",
+	"security/sip",
+	"security/upnp",
+	"sms/config",
+	"sntp/timezone",
+	"wlan/basic-settings",
+	"wlan/wifi-feature-switch",
+}
+
+// stateEndpoints are the read-only endpoints of the device state, in
+// addition to the settings endpoints.
+var stateEndpoints = []string{
+	"cradle/status-info",
+	"cradle/traffic-statistics",
+	"device/basic_information",
+	"device/device-feature-switch",
+	"device/information",
+	"device/signal",
+	"monitoring/check-notifications",
+	"monitoring/converged-status",
+	"monitoring/month_statistics",
+	"monitoring/month_statistics_wlan",
+	"monitoring/status",
+	"monitoring/traffic-statistics",
+	"net/current-plmn",
+	"net/net-mode-list",
+	"net/network",
+	"online-update/status",
+	"pb/pb-count",
+	"pin/simlock",
+	"pin/status",
+	"sms/sms-count",
+	"sms/sms-feature-switch",
+	"sntp/timeinfo",
+}
+
+// snapshotGates are the module switches (see GlobalFeatures) gating the
+// endpoints, by endpoint prefix. Endpoints of disabled modules are skipped.
+var snapshotGates = []struct {
+	prefix string
+	module string
+}{
+	{"cradle/", "cradle_enabled"},
+	{"ddns/", "ddns_enabled"},
+	{"monitoring/month_statistics_wlan", "wifi_enabled"},
+	{"monitoring/month_statistics", "statistic_enabled"},
+	{"monitoring/start_date", "statistic_enabled"},
+	{"online-update/", "ota_enabled"},
+	{"pb/", "pb_enabled"},
+	{"sms/", "sms_enabled"},
+	{"sntp/", "sntp_enabled"},
+	{"wlan/", "wifi_enabled"},
+}
+
+// SettingsSnapshot retrieves a snapshot of the device settings. Endpoints
+// not supported by the device are omitted.
+func (c *Client) SettingsSnapshot() (Snapshot, error) {
+	return c.collectSnapshot(context.Background(), settingsEndpoints)
+}
+
+// Snapshot retrieves a snapshot of the full device state, querying all
+// known read-only endpoints concurrently. Endpoints of modules disabled on
+// the device, or not supported by the device, are omitted.
+func (c *Client) Snapshot(ctx context.Context) (Snapshot, error) {
+	return c.collectSnapshot(ctx, snapshotEndpoints())
+}
+
+// WriteSnapshot streams a snapshot of the full device state (see Snapshot)
+// to w as a single JSON object, writing each endpoint as it is retrieved.
+func (c *Client) WriteSnapshot(ctx context.Context, w io.Writer) error {
+	if _, err := io.WriteString(w, "{"); err != nil {
+		return err
+	}
+
+	n, err := 0, error(nil)
+	serr := c.snapshot(ctx, snapshotEndpoints(), func(endpoint string, d XMLData) {
+		if err != nil {
+			return
+		}
+
+		var k, v []byte
+		if k, err = json.Marshal(endpoint); err != nil {
+			return
+		}
+		if v, err = json.Marshal(d); err != nil {
+			return
+		}
+
+		sep := ",\n"
+		if n == 0 {
+			sep = "\n"
+		}
+		n++
+		_, err = fmt.Fprintf(w, "%s%s:%s", sep, k, v)
+	})
+	switch {
+	case err != nil:
+		return err
+	case serr != nil:
+		return serr
+	}
+
+	_, err = io.WriteString(w, "\n}\n")
+	return err
+}
+
+// snapshotEndpoints returns the settings and state endpoints.
+func snapshotEndpoints() []string {
+	endpoints := append(append([]string(nil), settingsEndpoints...), stateEndpoints...)
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// collectSnapshot retrieves the endpoints into a Snapshot.
+func (c *Client) collectSnapshot(ctx context.Context, endpoints []string) (Snapshot, error) {
+	s := make(Snapshot)
+	if err := c.snapshot(ctx, endpoints, func(endpoint string, d XMLData) {
+		s[endpoint] = d
+	}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// snapshot concurrently retrieves the endpoints supported by the device,
+// calling fn (serially) with each retrieved endpoint. The first error is
+// returned when no endpoint could be retrieved.
+func (c *Client) snapshot(ctx context.Context, endpoints []string, fn func(string, XMLData)) error {
+	// retrieve module switches, absent on some firmware
+	modules, _ := c.GlobalFeatures()
+
+	var paths []string
+	for _, endpoint := range endpoints {
+		if moduleEnabled(modules, endpoint) {
+			paths = append(paths, "api/"+endpoint)
+		}
+	}
+
+	n, firstErr := 0, error(nil)
+	c.fetch(ctx, paths, func(path string, d XMLData, err error) {
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		n++
+		fn(strings.TrimPrefix(path, "api/"), d)
+	})
+
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case n == 0 && firstErr != nil:
+		return firstErr
+	}
+	return nil
+}
+
+// moduleEnabled determines if the module gating the endpoint is enabled.
+// Endpoints without a gate, or whose module switch is absent, are enabled.
+func moduleEnabled(modules XMLData, endpoint string) bool {
+	for _, g := range snapshotGates {
+		if strings.HasPrefix(endpoint, g.prefix) {
+			v, ok := modules[g.module].(string)
+			return !ok || v != "0"
+		}
+	}
+	return true
+}
+
+// ReadSnapshot reads a Snapshot previously marshaled as JSON.
+func ReadSnapshot(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SnapshotChange is a changed value between two snapshots.
+type SnapshotChange struct {
+	// Key is the flattened key of the value (ie,
+	// "wlan/basic-settings.WifiSsid", or "dialup/profiles.Profiles.Profile[0].Name").
+	Key string `json:"key"`
+
+	// Old and New are the old and new values, nil when the value was added
+	// or removed.
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
+
+// String satisfies the fmt.Stringer interface.
+func (c SnapshotChange) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("+ %s: %v", c.Key, c.New)
+	case c.New == nil:
+		return fmt.Sprintf("- %s: %v", c.Key, c.Old)
+	}
+	return fmt.Sprintf("~ %s: %v -> %v", c.Key, c.Old, c.New)
+}
+
+// DiffSnapshots returns the changed values between snapshots a and b, sorted
+// by key.
+func DiffSnapshots(a, b Snapshot) []SnapshotChange {
+	fa, fb := make(map[string]interface{}), make(map[string]interface{})
+	for k, v := range a {
+		flatten(fa, k, map[string]interface{}(v))
+	}
+	for k, v := range b {
+		flatten(fb, k, map[string]interface{}(v))
+	}
+
+	var changes []SnapshotChange
+	for k, v := range fa {
+		if w, ok := fb[k]; !ok {
+			changes = append(changes, SnapshotChange{Key: k, Old: v})
+		} else if !reflect.DeepEqual(v, w) {
+			changes = append(changes, SnapshotChange{Key: k, Old: v, New: w})
+		}
+	}
+	for k, w := range fb {
+		if _, ok := fa[k]; !ok {
+			changes = append(changes, SnapshotChange{Key: k, New: w})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+// flatten flattens the nested maps and slices of v into dst, keyed by their
+// path from prefix.
+func flatten(dst map[string]interface{}, prefix string, v interface{}) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, z := range x {
+			flatten(dst, prefix+"."+k, z)
+		}
+	case XMLData:
+		flatten(dst, prefix, map[string]interface{}(x))
+	case []interface{}:
+		for i, z := range x {
+			flatten(dst, fmt.Sprintf("%s[%d]", prefix, i), z)
+		}
+	case nil:
+		dst[prefix] = ""
+	default:
+		dst[prefix] = strings.TrimSpace(fmt.Sprint(x))
+	}
+}
@@ -0,0 +1,184 @@
+package hilink
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// SpeedTestResult is the result of a speed test.
+type SpeedTestResult struct {
+	// Duration is the measured duration.
+	Duration time.Duration
+
+	// Upload and Download are the bytes transferred during the test, as
+	// counted by the device.
+	Upload   uint64
+	Download uint64
+
+	// AvgUploadRate and AvgDownloadRate are the average rates, in
+	// bytes/second.
+	AvgUploadRate   float64
+	AvgDownloadRate float64
+
+	// PeakUploadRate and PeakDownloadRate are the highest rates, in
+	// bytes/second, observed between two samples.
+	PeakUploadRate   float64
+	PeakDownloadRate float64
+
+	// Samples is the number of samples taken.
+	Samples int
+}
+
+// SpeedTest measures the effective throughput of the device by sampling its
+// traffic counters, while the link is saturated by the Load func (or by the
+// caller).
+type SpeedTest struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Load generates traffic through the device. The test ends when Load
+	// returns, or when Duration has elapsed. When nil, the caller is
+	// expected to saturate the link for the Duration.
+	Load func(ctx context.Context) error
+
+	// Duration is the maximum test duration. Defaults to 10 seconds.
+	Duration time.Duration
+
+	// Interval is the sample interval. Defaults to 1 second.
+	Interval time.Duration
+}
+
+// Measure runs the speed test.
+func (s *SpeedTest) Measure(ctx context.Context) (*SpeedTestResult, error) {
+	if s.Client == nil {
+		return nil, ErrNilClient
+	}
+
+	duration, interval := s.Duration, s.Interval
+	if duration == 0 {
+		duration = 10 * time.Second
+	}
+	if interval == 0 {
+		interval = time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	// initial sample
+	first, err := s.sample()
+	if err != nil {
+		return nil, err
+	}
+
+	// start load
+	loadDone := make(chan error, 1)
+	if s.Load != nil {
+		go func() {
+			loadDone <- s.Load(ctx)
+		}()
+	}
+
+	res := new(SpeedTestResult)
+	prev := first
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+loop:
+	for last := false; !last; {
+		select {
+		case <-ctx.Done():
+			break loop
+		case err := <-loadDone:
+			// load errors caused by the test ending are expected
+			if err != nil && ctx.Err() == nil {
+				return nil, err
+			}
+			// final sample, as the load ended within the interval
+			last = true
+		case <-t.C:
+		}
+
+		cur, err := s.sample()
+		if err != nil {
+			return nil, err
+		}
+		res.Samples++
+
+		// compute peak rates
+		if d := cur.t.Sub(prev.t).Seconds(); d > 0 {
+			if r := float64(delta(prev.up, cur.up)) / d; r > res.PeakUploadRate {
+				res.PeakUploadRate = r
+			}
+			if r := float64(delta(prev.down, cur.down)) / d; r > res.PeakDownloadRate {
+				res.PeakDownloadRate = r
+			}
+		}
+		prev = cur
+	}
+
+	res.Duration = prev.t.Sub(first.t)
+	res.Upload = delta(first.up, prev.up)
+	res.Download = delta(first.down, prev.down)
+	if d := res.Duration.Seconds(); d > 0 {
+		res.AvgUploadRate = float64(res.Upload) / d
+		res.AvgDownloadRate = float64(res.Download) / d
+	}
+
+	return res, nil
+}
+
+// speedSample is a traffic counter sample.
+type speedSample struct {
+	t        time.Time
+	up, down uint64
+}
+
+// sample retrieves the current traffic counters.
+func (s *SpeedTest) sample() (speedSample, error) {
+	var x trafficXML
+	if err := s.Client.doReqXML("api/monitoring/traffic-statistics", nil, &x); err != nil {
+		return speedSample{}, err
+	}
+	return speedSample{
+		t:    time.Now(),
+		up:   parseUint(x.CurrentUpload),
+		down: parseUint(x.CurrentDownload),
+	}, nil
+}
+
+// delta returns the difference between two counter values, treating a
+// counter reset (ie, a reconnect) as no traffic.
+func delta(prev, cur uint64) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return cur - prev
+}
+
+// DownloadLoad returns a SpeedTest Load func that repeatedly downloads
+// rawurl, discarding the data. The host must route rawurl through the device.
+func DownloadLoad(rawurl string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		for ctx.Err() == nil {
+			req, err := http.NewRequestWithContext(ctx, "GET", rawurl, nil)
+			if err != nil {
+				return err
+			}
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	}
+}
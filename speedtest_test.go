@@ -0,0 +1,39 @@
+package hilink
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSpeedTestLoadReturns(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Sequences["/api/monitoring/traffic-statistics"] = []string{
+		`<response><CurrentUpload>1000</CurrentUpload><CurrentDownload>5000</CurrentDownload></response>`,
+		`<response><CurrentUpload>2000</CurrentUpload><CurrentDownload>15000</CurrentDownload></response>`,
+	}
+
+	// the load ends well within the interval
+	s := &SpeedTest{
+		Client:   d.client(t),
+		Interval: time.Hour,
+		Load: func(context.Context) error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		},
+	}
+	res, err := s.Measure(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if res.Samples != 1 {
+		t.Errorf("expected 1 sample, got: %d", res.Samples)
+	}
+	if res.Upload != 1000 || res.Download != 10000 {
+		t.Errorf("expected 1000 up and 10000 down, got: %d and %d", res.Upload, res.Download)
+	}
+	if res.Duration < 50*time.Millisecond || res.AvgDownloadRate <= 0 {
+		t.Errorf("expected the duration of the load, got: %v at %f", res.Duration, res.AvgDownloadRate)
+	}
+}
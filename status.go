@@ -0,0 +1,217 @@
+package hilink
+
+import (
+	"fmt"
+)
+
+// ConnectionStatus represents the connection (dialup) states reported by the
+// device.
+type ConnectionStatus int
+
+// ConnectionStatus values.
+const (
+	ConnectionStatusConnecting    ConnectionStatus = 900
+	ConnectionStatusConnected     ConnectionStatus = 901
+	ConnectionStatusDisconnected  ConnectionStatus = 902
+	ConnectionStatusDisconnecting ConnectionStatus = 903
+	ConnectionStatusFailed        ConnectionStatus = 904
+)
+
+// String satisfies the fmt.Stringer interface.
+func (s ConnectionStatus) String() string {
+	switch s {
+	case ConnectionStatusConnecting:
+		return "connecting"
+	case ConnectionStatusConnected:
+		return "connected"
+	case ConnectionStatusDisconnected:
+		return "disconnected"
+	case ConnectionStatusDisconnecting:
+		return "disconnecting"
+	case ConnectionStatusFailed:
+		return "connection failed"
+	}
+	return fmt.Sprintf("ConnectionStatus(%d)", int(s))
+}
+
+// NetworkType represents the extended network (radio access) types reported
+// by the device (ie, CurrentNetworkTypeEx).
+type NetworkType int
+
+// NetworkType values.
+const (
+	NetworkTypeNoService NetworkType = 0
+	NetworkTypeGSM       NetworkType = 1
+	NetworkTypeGPRS      NetworkType = 2
+	NetworkTypeEDGE      NetworkType = 3
+	NetworkTypeWCDMA     NetworkType = 41
+	NetworkTypeHSDPA     NetworkType = 42
+	NetworkTypeHSUPA     NetworkType = 43
+	NetworkTypeHSPA      NetworkType = 44
+	NetworkTypeHSPAPlus  NetworkType = 45
+	NetworkTypeDCHSPA    NetworkType = 46
+	NetworkTypeTDSCDMA   NetworkType = 61
+	NetworkTypeLTE       NetworkType = 101
+	NetworkTypeLTECA     NetworkType = 1011
+
+	// NetworkTypeNRNSA is 5G NR non-standalone (ie, EN-DC, LTE anchored).
+	NetworkTypeNRNSA NetworkType = 111
+
+	// NetworkTypeNRSA is 5G NR standalone.
+	NetworkTypeNRSA NetworkType = 112
+)
+
+// String satisfies the fmt.Stringer interface.
+func (t NetworkType) String() string {
+	switch t {
+	case NetworkTypeNoService:
+		return "no service"
+	case NetworkTypeGSM:
+		return "GSM"
+	case NetworkTypeGPRS:
+		return "GPRS"
+	case NetworkTypeEDGE:
+		return "EDGE"
+	case NetworkTypeWCDMA:
+		return "WCDMA"
+	case NetworkTypeHSDPA:
+		return "HSDPA"
+	case NetworkTypeHSUPA:
+		return "HSUPA"
+	case NetworkTypeHSPA:
+		return "HSPA"
+	case NetworkTypeHSPAPlus:
+		return "HSPA+"
+	case NetworkTypeDCHSPA:
+		return "DC-HSPA+"
+	case NetworkTypeTDSCDMA:
+		return "TD-SCDMA"
+	case NetworkTypeLTE:
+		return "LTE"
+	case NetworkTypeLTECA:
+		return "LTE+"
+	case NetworkTypeNRNSA:
+		return "5G NSA"
+	case NetworkTypeNRSA:
+		return "5G SA"
+	}
+	return fmt.Sprintf("NetworkType(%d)", int(t))
+}
+
+// IsNR determines if the network type is 5G NR (standalone or
+// non-standalone).
+func (t NetworkType) IsNR() bool {
+	return t == NetworkTypeNRNSA || t == NetworkTypeNRSA
+}
+
+// Status is the general device status information.
+type Status struct {
+	ConnectionStatus     ConnectionStatus `xml:"ConnectionStatus"`
+	WifiConnectionStatus int              `xml:"WifiConnectionStatus"`
+	NetworkType          NetworkType      `xml:"CurrentNetworkTypeEx"`
+	ServiceStatus        int              `xml:"ServiceStatus"`
+	SimStatus            int              `xml:"SimStatus"`
+	RoamingStatus        int              `xml:"RoamingStatus"`
+	SignalIcon           int              `xml:"SignalIcon"`
+	MaxSignal            int              `xml:"maxsignal"`
+	WanIPAddress         string           `xml:"WanIPAddress"`
+	WanIPv6Address       string           `xml:"WanIPv6Address"`
+	PrimaryDNS           string           `xml:"PrimaryDns"`
+	SecondaryDNS         string           `xml:"SecondaryDns"`
+	PrimaryIPv6DNS       string           `xml:"PrimaryIPv6Dns"`
+	SecondaryIPv6DNS     string           `xml:"SecondaryIPv6Dns"`
+	WifiStatus           int              `xml:"WifiStatus"`
+	CurrentWifiUser      int              `xml:"CurrentWifiUser"`
+	BatteryStatus        int              `xml:"BatteryStatus"`
+	BatteryPercent       int              `xml:"BatteryPercent"`
+}
+
+// PDPType returns the negotiated PDP type of the connection, derived from the
+// assigned WAN addresses. False is returned when no address is assigned.
+func (s Status) PDPType() (IPType, bool) {
+	switch {
+	case s.WanIPAddress != "" && s.WanIPv6Address != "":
+		return IPTypeIPv4v6, true
+	case s.WanIPv6Address != "":
+		return IPTypeIPv6, true
+	case s.WanIPAddress != "":
+		return IPTypeIPv4, true
+	}
+	return IPTypeIPv4, false
+}
+
+// PDPInfo is the IP (PDP) information of the mobile connection.
+type PDPInfo struct {
+	// Requested is the IP type of the default profile.
+	Requested IPType
+
+	// Negotiated is the IP type of the connection (see Status.PDPType),
+	// when Connected.
+	Negotiated IPType
+	Connected  bool
+
+	// IPv4Address and IPv6Address are the assigned WAN addresses.
+	IPv4Address string
+	IPv6Address string
+
+	// DNS and IPv6DNS are the assigned DNS servers.
+	DNS     []string
+	IPv6DNS []string
+}
+
+// PDPInfo retrieves the requested and negotiated IP types, and the assigned
+// addresses of the mobile connection. IPv6 DNS servers are only reported by
+// some firmware.
+func (c *Client) PDPInfo() (*PDPInfo, error) {
+	profiles, def, err := c.Profiles()
+	if err != nil {
+		return nil, err
+	}
+	s, err := c.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PDPInfo{
+		IPv4Address: s.WanIPAddress,
+		IPv6Address: s.WanIPv6Address,
+		DNS:         nonEmpty(s.PrimaryDNS, s.SecondaryDNS),
+		IPv6DNS:     nonEmpty(s.PrimaryIPv6DNS, s.SecondaryIPv6DNS),
+	}
+	info.Negotiated, info.Connected = s.PDPType()
+	for _, p := range profiles {
+		if p.Index == def {
+			info.Requested = p.IPType
+		}
+	}
+	return info, nil
+}
+
+// nonEmpty returns the non-empty strings.
+func nonEmpty(strs ...string) []string {
+	var res []string
+	for _, s := range strs {
+		if s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// Status retrieves the general device status information.
+func (c *Client) Status() (*Status, error) {
+	return decodeStatus(c.doReqBody("api/monitoring/status", nil))
+}
+
+// decodeStatus decodes the status information from a response body.
+func decodeStatus(body []byte, err error) (*Status, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	var s Status
+	if err := unmarshalXML(body, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
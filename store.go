@@ -0,0 +1,128 @@
+package hilink
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Store is a persistent key/value store used by watchers to keep state (ie,
+// the last seen SMS index, or the last public IP address) across process
+// restarts. Keys are namespaced by the watcher using them (ie,
+// "sms/last-index").
+type Store interface {
+	// Get retrieves the value of key, returning false when not set.
+	Get(key string) (string, bool, error)
+
+	// Set sets the value of key.
+	Set(key, value string) error
+}
+
+// MemoryStore is an in-memory Store, not persisted across restarts.
+type MemoryStore struct {
+	m map[string]string
+	sync.Mutex
+}
+
+// NewMemoryStore creates a new in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		m: make(map[string]string),
+	}
+}
+
+// Get satisfies the Store interface.
+func (s *MemoryStore) Get(key string) (string, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	v, ok := s.m[key]
+	return v, ok, nil
+}
+
+// Set satisfies the Store interface.
+func (s *MemoryStore) Set(key, value string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.m == nil {
+		s.m = make(map[string]string)
+	}
+	s.m[key] = value
+	return nil
+}
+
+// FileStore is a Store persisted as a JSON file. The file is rewritten
+// atomically on each Set.
+type FileStore struct {
+	path string
+	m    map[string]string
+	sync.Mutex
+}
+
+// OpenFileStore opens the store persisted at path, which is created on the
+// first Set when it does not exist.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path: path,
+		m:    make(map[string]string),
+	}
+
+	buf, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return s, nil
+	case err != nil:
+		return nil, err
+	}
+	if err := json.Unmarshal(buf, &s.m); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Get satisfies the Store interface.
+func (s *FileStore) Get(key string) (string, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	v, ok := s.m[key]
+	return v, ok, nil
+}
+
+// Set satisfies the Store interface.
+func (s *FileStore) Set(key, value string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if v, ok := s.m[key]; ok && v == value {
+		return nil
+	}
+	s.m[key] = value
+
+	buf, err := json.MarshalIndent(s.m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename, so that the store is never left
+	// partially written
+	f, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(append(buf, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path)
+}
@@ -0,0 +1,137 @@
+package hilink
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DoEach sends a request to the server with the provided path (see Do),
+// decoding the response as it is received, and calling fn for each element
+// named el (ie, "Message"), at any depth. Memory use is bounded by the size
+// of a single element, instead of the whole response.
+//
+// The client is busy while the response is decoded: fn must not send
+// requests with the client. An error returned by fn stops the decoding, and
+// is returned.
+func (c *Client) DoEach(path string, v interface{}, el string, fn func(XMLData) error) error {
+	return c.doReqStream(path, v, func(status int, r io.Reader) error {
+		if status != http.StatusOK {
+			return ErrBadStatusCode
+		}
+		return decodeEach(r, el, fn)
+	})
+}
+
+// SmsEach retrieves list of SMS in an inbox (see SmsList), calling fn for
+// each message as it is decoded (see DoEach).
+func (c *Client) SmsEach(opts SmsListOptions, fn func(XMLData) error) error {
+	return c.DoEach("api/sms/sms-list", opts.request(), "Message", fn)
+}
+
+// PhonebookEach retrieves list of phonebook entries from a specified group
+// (see PhonebookList), calling fn for each entry as it is decoded (see
+// DoEach).
+func (c *Client) PhonebookEach(group, page, count uint, sim, sortByName, ascending bool, keyword string, fn func(XMLData) error) error {
+	// execute request -- note: the order is important!
+	return c.DoEach("api/pb/pb-list", SimpleRequestXML(
+		"GroupID", fmt.Sprintf("%d", group),
+		"PageIndex", fmt.Sprintf("%d", page),
+		"ReadCount", fmt.Sprintf("%d", count),
+		"SaveType", boolToString(sim),
+		"SortType", boolToString(sortByName),
+		"Ascending", boolToString(ascending),
+		"KeyWord", keyword,
+	), "Phonebook", fn)
+}
+
+// decodeEach decodes the elements named el from r, calling fn for each.
+func decodeEach(r io.Reader, el string, fn func(XMLData) error) error {
+	d := xml.NewDecoder(r)
+
+	root := true
+	for {
+		tok, err := d.Token()
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		// check if error was returned
+		if root && start.Name.Local == "error" {
+			var e struct {
+				Code    string `xml:"code"`
+				Message string `xml:"message"`
+			}
+			if err := d.DecodeElement(&e, &start); err != nil {
+				return err
+			}
+			return hilinkError(e.Code, e.Message)
+		}
+		root = false
+
+		if start.Name.Local != el {
+			continue
+		}
+
+		m, err := decodeElement(d)
+		if err != nil {
+			return err
+		}
+		x, _ := m.(map[string]interface{})
+		if err := fn(XMLData(x)); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeElement decodes the remainder of the current element, into a string
+// for simple elements, and otherwise into a map of its children (as decoded
+// by decodeXML). Repeated children are collected in a slice.
+func decodeElement(d *xml.Decoder) (interface{}, error) {
+	var text strings.Builder
+	var m map[string]interface{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+
+		case xml.StartElement:
+			v, err := decodeElement(d)
+			if err != nil {
+				return nil, err
+			}
+			if m == nil {
+				m = make(map[string]interface{})
+			}
+			switch x := m[t.Name.Local].(type) {
+			case nil:
+				m[t.Name.Local] = v
+			case []interface{}:
+				m[t.Name.Local] = append(x, v)
+			default:
+				m[t.Name.Local] = []interface{}{x, v}
+			}
+
+		case xml.EndElement:
+			if m != nil {
+				return m, nil
+			}
+			return text.String(), nil
+		}
+	}
+}
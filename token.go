@@ -0,0 +1,154 @@
+package hilink
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+)
+
+// tokenPool is a pool of prefetched CSRF tokens, for firmware issuing
+// single-use tokens.
+type tokenPool struct {
+	// gen is the pool generation, incremented when the pool is flushed
+	// (first for 64-bit alignment)
+	gen uint64
+
+	tokens chan pooledToken
+	refill chan struct{}
+}
+
+// pooledToken is a prefetched token, with the pool generation it was
+// fetched with. Tokens of an earlier generation, fetched while the pool
+// was flushed, belong to an earlier session.
+type pooledToken struct {
+	tok string
+	gen uint64
+}
+
+// TokenPrefetch is an option that keeps n CSRF tokens prefetched from the
+// device (api/webserver/token) in the background, for firmware that issues
+// single-use tokens. Requests use a prefetched token when one is ready,
+// falling back to the token of the last response otherwise, and the pool is
+// replenished asynchronously. The prefetching stops when the client is
+// closed (see Close).
+func TokenPrefetch(n int) Option {
+	return func(c *Client) error {
+		if n > 0 {
+			c.tokens = &tokenPool{
+				tokens: make(chan pooledToken, n),
+				refill: make(chan struct{}, 1),
+			}
+		}
+		return nil
+	}
+}
+
+// nextToken returns the CSRF token for the next request.
+func (c *Client) nextToken() string {
+	if c.tokens == nil {
+		return c.token
+	}
+
+	p := c.tokens
+	for {
+		select {
+		case t := <-p.tokens:
+			select {
+			case p.refill <- struct{}{}:
+			default:
+			}
+			if t.gen == atomic.LoadUint64(&p.gen) {
+				return t.tok
+			}
+			continue
+		default:
+		}
+
+		return c.token
+	}
+}
+
+// flushTokens discards the prefetched tokens, ie when the session changes.
+func (c *Client) flushTokens() {
+	if c.tokens == nil {
+		return
+	}
+
+	atomic.AddUint64(&c.tokens.gen, 1)
+	for {
+		select {
+		case <-c.tokens.tokens:
+		default:
+			select {
+			case c.tokens.refill <- struct{}{}:
+			default:
+			}
+			return
+		}
+	}
+}
+
+// prefetchTokens fills the token pool until the context is closed.
+func (c *Client) prefetchTokens(ctx context.Context) error {
+	p := c.tokens
+	for {
+		for len(p.tokens) < cap(p.tokens) {
+			gen := atomic.LoadUint64(&p.gen)
+			tok, err := c.fetchToken(ctx)
+			if err != nil {
+				return err
+			}
+			select {
+			case p.tokens <- pooledToken{tok, gen}:
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.refill:
+		}
+	}
+}
+
+// fetchToken retrieves a new CSRF token from the device. The request is sent
+// concurrently with other requests, without holding the client lock.
+func (c *Client) fetchToken(ctx context.Context) (string, error) {
+	req, err := c.createRequest(c.rawurl+"api/webserver/token", nil)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", ErrBadStatusCode
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var v struct {
+		Token string `xml:"token"`
+	}
+	if err := unmarshalXML(body, &v); err != nil {
+		return "", err
+	}
+	if v.Token == "" {
+		return "", ErrInvalidResponse
+	}
+
+	// the WebUI skips the leading 32 characters
+	if len(v.Token) > 32 {
+		return v.Token[32:], nil
+	}
+	return v.Token, nil
+}
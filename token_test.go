@@ -0,0 +1,122 @@
+package hilink
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tokenPoolServer is a fake device issuing single-use CSRF tokens, that are
+// not returned with responses.
+type tokenPoolServer struct {
+	mu     sync.Mutex
+	n      int
+	issued map[string]bool
+}
+
+// issue issues a new token.
+func (s *tokenPoolServer) issue() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.n++
+	tok := fmt.Sprintf("token%d", s.n)
+	s.issued[tok] = true
+	return tok
+}
+
+// use consumes the token.
+func (s *tokenPoolServer) use(tok string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ok := s.issued[tok]
+	delete(s.issued, tok)
+	return ok
+}
+
+// ServeHTTP satisfies the http.Handler interface.
+func (s *tokenPoolServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// device latency
+	time.Sleep(500 * time.Microsecond)
+
+	switch {
+	case r.URL.Path == "/api/webserver/SesTokInfo":
+		fmt.Fprintf(w, `<response><SesInfo>session</SesInfo><TokInfo>%s</TokInfo></response>`, s.issue())
+	case r.URL.Path == "/api/webserver/token":
+		fmt.Fprintf(w, `<response><token>%s%s</token></response>`, strings.Repeat("0", 32), s.issue())
+	case !s.use(r.Header.Get(TokenHeader)):
+		fmt.Fprintf(w, `<error><code>%d</code><message></message></error>`, ErrorCodeInvalidSessionToken)
+	default:
+		w.Write([]byte(`<response>OK</response>`))
+	}
+}
+
+func TestTokenPrefetch(t *testing.T) {
+	srv := httptest.NewServer(&tokenPoolServer{issued: make(map[string]bool)})
+	defer srv.Close()
+
+	c, err := NewClient(URL(srv.URL+"/"), TokenPrefetch(2))
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer c.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := checkOK(c.DeviceReboot()); err != nil {
+			t.Fatalf("request %d: expected no error, got: %v", i, err)
+		}
+	}
+}
+
+func BenchmarkTokenPrefetch(b *testing.B) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"Default", nil},
+		{"TokenPrefetch4", []Option{TokenPrefetch(4)}},
+	}
+	for _, test := range tests {
+		b.Run(test.name, func(b *testing.B) {
+			srv := httptest.NewServer(&tokenPoolServer{issued: make(map[string]bool)})
+			defer srv.Close()
+
+			c, err := NewClient(append([]Option{URL(srv.URL + "/")}, test.opts...)...)
+			if err != nil {
+				b.Fatalf("expected no error, got: %v", err)
+			}
+			defer c.Close()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := checkOK(c.DeviceReboot()); err != nil {
+					b.Fatalf("expected no error, got: %v", err)
+				}
+				// requests are issued by pollers, not back to back
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestTokenPrefetchFlush(t *testing.T) {
+	c := &Client{token: "last"}
+	if err := TokenPrefetch(2)(c); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// a token fetched before the flush, and pooled after it, is dropped
+	gen := c.tokens.gen
+	c.flushTokens()
+	c.tokens.tokens <- pooledToken{"stale", gen}
+	c.tokens.tokens <- pooledToken{"fresh", c.tokens.gen}
+	if tok := c.nextToken(); tok != "fresh" {
+		t.Errorf("expected fresh token, got: %s", tok)
+	}
+	if tok := c.nextToken(); tok != "last" {
+		t.Errorf("expected last token, got: %s", tok)
+	}
+}
@@ -0,0 +1,255 @@
+package hilink
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatisticFeatures retrieves the data statistic feature information (ie,
+// whether data statistics and limits are enabled).
+func (c *Client) StatisticFeatures() (XMLData, error) {
+	return c.Do("api/monitoring/statistic-feature-switch", nil)
+}
+
+// StatisticsEnabled determines if the data statistics feature is enabled on
+// the device. Data limits (ie, the monthly data plan) are only enforced when
+// the feature is enabled.
+func (c *Client) StatisticsEnabled() (bool, error) {
+	s, err := c.doReqString("api/monitoring/statistic-feature-switch", nil, "statistic_enable")
+	if err != nil {
+		return false, err
+	}
+	return s == "1", nil
+}
+
+// StatisticsEnabledSet enables or disables the data statistics feature.
+func (c *Client) StatisticsEnabledSet(enabled bool) (bool, error) {
+	return c.doReqCheckOK("api/monitoring/statistic-feature-switch", SimpleRequestXML(
+		"statistic_enable", boolToString(enabled),
+	))
+}
+
+// MonthClear clears the month download statistics.
+func (c *Client) MonthClear() (bool, error) {
+	return c.doReqCheckOK("api/monitoring/clear-month-statistics", XMLData{
+		"ClearMonthStatistics": "1",
+	})
+}
+
+// WlanMonthClear clears the WLAN month download statistics.
+func (c *Client) WlanMonthClear() (bool, error) {
+	return c.doReqCheckOK("api/monitoring/clear-month-statistics-wlan", XMLData{
+		"ClearMonthStatistics": "1",
+	})
+}
+
+// startDateXML is the month statistics start date (ie, data plan) settings.
+type startDateXML struct {
+	StartDay       string `xml:"StartDay"`
+	DataLimit      string `xml:"DataLimit"`
+	MonthThreshold string `xml:"MonthThreshold"`
+	SetMonthData   string `xml:"SetMonthData"`
+}
+
+// MonthResetDay retrieves the day of the month on which the month
+// statistics are reset.
+func (c *Client) MonthResetDay() (int, error) {
+	var x startDateXML
+	if err := c.doReqXML("api/monitoring/start_date", nil, &x); err != nil {
+		return 0, err
+	}
+	return parseInt(x.StartDay), nil
+}
+
+// MonthResetDaySet sets the day of the month (1-31) on which the month
+// statistics are reset, keeping the other data plan settings unchanged.
+func (c *Client) MonthResetDaySet(day uint) (bool, error) {
+	if day < 1 || day > 31 {
+		return false, fmt.Errorf("%w: reset day must be 1 to 31", ErrInvalidValue)
+	}
+
+	var x startDateXML
+	if err := c.doReqXML("api/monitoring/start_date", nil, &x); err != nil {
+		return false, err
+	}
+
+	return c.doReqCheckOK("api/monitoring/start_date", SimpleRequestXML(
+		"StartDay", fmt.Sprintf("%d", day),
+		"DataLimit", x.DataLimit,
+		"MonthThreshold", x.MonthThreshold,
+		"SetMonthData", x.SetMonthData,
+	))
+}
+
+// NextMonthReset returns the time of the next month statistics reset after
+// now, for the reset day. When the month is shorter than the reset day, the
+// reset occurs on the last day of the month.
+func NextMonthReset(day int, now time.Time) time.Time {
+	reset := func(y int, m time.Month) time.Time {
+		d := day
+		if last := time.Date(y, m+1, 0, 0, 0, 0, 0, now.Location()).Day(); d > last {
+			d = last
+		}
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	}
+
+	t := reset(now.Year(), now.Month())
+	if !t.After(now) {
+		t = reset(now.Year(), now.Month()+1)
+	}
+	return t
+}
+
+// InterfaceTraffic is the traffic statistics of a single interface.
+type InterfaceTraffic struct {
+	// Upload and Download are the transferred bytes.
+	Upload   uint64
+	Download uint64
+
+	// UploadRate and DownloadRate are the current rates, in bytes/second,
+	// where reported.
+	UploadRate   uint64
+	DownloadRate uint64
+
+	// Duration is the duration the statistics cover.
+	Duration time.Duration
+}
+
+// TrafficReport is the traffic statistics broken down per interface.
+//
+// Mobile contains the current connection statistics. Wlan contains the WLAN
+// month statistics. Ethernet contains the Ethernet WAN (cradle) statistics.
+// Interfaces for which the firmware does not provide statistics are nil.
+type TrafficReport struct {
+	Mobile   *InterfaceTraffic
+	Wlan     *InterfaceTraffic
+	Ethernet *InterfaceTraffic
+}
+
+// trafficXML is the raw traffic statistics returned by the device.
+type trafficXML struct {
+	CurrentConnectTime  string `xml:"CurrentConnectTime"`
+	CurrentUpload       string `xml:"CurrentUpload"`
+	CurrentDownload     string `xml:"CurrentDownload"`
+	CurrentDownloadRate string `xml:"CurrentDownloadRate"`
+	CurrentUploadRate   string `xml:"CurrentUploadRate"`
+}
+
+// interfaceTraffic converts the raw traffic statistics.
+func (x trafficXML) interfaceTraffic() *InterfaceTraffic {
+	return &InterfaceTraffic{
+		Upload:       parseUint(x.CurrentUpload),
+		Download:     parseUint(x.CurrentDownload),
+		UploadRate:   parseUint(x.CurrentUploadRate),
+		DownloadRate: parseUint(x.CurrentDownloadRate),
+		Duration:     time.Duration(parseUint(x.CurrentConnectTime)) * time.Second,
+	}
+}
+
+// TrafficReport retrieves the traffic statistics per interface.
+func (c *Client) TrafficReport() (*TrafficReport, error) {
+	var r TrafficReport
+
+	// mobile
+	var m trafficXML
+	if err := c.doReqXML("api/monitoring/traffic-statistics", nil, &m); err != nil {
+		return nil, err
+	}
+	r.Mobile = m.interfaceTraffic()
+
+	// wlan (optional)
+	var w struct {
+		CurrentMonthDownload string `xml:"CurrentMonthDownload"`
+		CurrentMonthUpload   string `xml:"CurrentMonthUpload"`
+		MonthDuration        string `xml:"MonthDuration"`
+	}
+	if err := c.doReqXML("api/monitoring/month_statistics_wlan", nil, &w); err == nil {
+		r.Wlan = &InterfaceTraffic{
+			Upload:   parseUint(w.CurrentMonthUpload),
+			Download: parseUint(w.CurrentMonthDownload),
+			Duration: time.Duration(parseUint(w.MonthDuration)) * time.Second,
+		}
+	}
+
+	// ethernet (optional)
+	var e trafficXML
+	if err := c.doReqXML("api/cradle/traffic-statistics", nil, &e); err == nil {
+		r.Ethernet = e.interfaceTraffic()
+	}
+
+	return &r, nil
+}
+
+// TrafficStats is the parsed traffic statistics of the mobile connection,
+// merged with the month statistics.
+type TrafficStats struct {
+	// CurrentUpload and CurrentDownload are the bytes transferred during the
+	// current connection.
+	CurrentUpload   uint64
+	CurrentDownload uint64
+
+	// CurrentUploadRate and CurrentDownloadRate are the current rates, in
+	// bytes/second.
+	CurrentUploadRate   uint64
+	CurrentDownloadRate uint64
+
+	// CurrentConnectTime is the duration of the current connection.
+	CurrentConnectTime time.Duration
+
+	// TotalUpload and TotalDownload are the bytes transferred since the
+	// statistics were last cleared.
+	TotalUpload   uint64
+	TotalDownload uint64
+
+	// TotalConnectTime is the total connection time since the statistics
+	// were last cleared.
+	TotalConnectTime time.Duration
+
+	// MonthUpload and MonthDownload are the bytes transferred during the
+	// current month, where reported.
+	MonthUpload   uint64
+	MonthDownload uint64
+
+	// MonthDuration is the connection time during the current month, where
+	// reported.
+	MonthDuration time.Duration
+}
+
+// TrafficStats retrieves the traffic statistics of the mobile connection,
+// merged with the month statistics, where the firmware provides them.
+func (c *Client) TrafficStats() (*TrafficStats, error) {
+	var x struct {
+		trafficXML
+		TotalConnectTime string `xml:"TotalConnectTime"`
+		TotalUpload      string `xml:"TotalUpload"`
+		TotalDownload    string `xml:"TotalDownload"`
+	}
+	if err := c.doReqXML("api/monitoring/traffic-statistics", nil, &x); err != nil {
+		return nil, err
+	}
+
+	s := &TrafficStats{
+		CurrentUpload:       parseUint(x.CurrentUpload),
+		CurrentDownload:     parseUint(x.CurrentDownload),
+		CurrentUploadRate:   parseUint(x.CurrentUploadRate),
+		CurrentDownloadRate: parseUint(x.CurrentDownloadRate),
+		CurrentConnectTime:  time.Duration(parseUint(x.CurrentConnectTime)) * time.Second,
+		TotalUpload:         parseUint(x.TotalUpload),
+		TotalDownload:       parseUint(x.TotalDownload),
+		TotalConnectTime:    time.Duration(parseUint(x.TotalConnectTime)) * time.Second,
+	}
+
+	// month (optional)
+	var m struct {
+		CurrentMonthDownload string `xml:"CurrentMonthDownload"`
+		CurrentMonthUpload   string `xml:"CurrentMonthUpload"`
+		MonthDuration        string `xml:"MonthDuration"`
+	}
+	if err := c.doReqXML("api/monitoring/month_statistics", nil, &m); err == nil {
+		s.MonthUpload = parseUint(m.CurrentMonthUpload)
+		s.MonthDownload = parseUint(m.CurrentMonthDownload)
+		s.MonthDuration = time.Duration(parseUint(m.MonthDuration)) * time.Second
+	}
+
+	return s, nil
+}
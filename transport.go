@@ -0,0 +1,103 @@
+package hilink
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Transport defaults, tuned for the small embedded web server of Hilink
+// devices, which handles few concurrent connections and is slow to accept
+// new ones.
+const (
+	// DefaultMaxConnsPerHost is the default maximum number of connections
+	// to the device.
+	DefaultMaxConnsPerHost = 4
+
+	// DefaultMaxIdleConnsPerHost is the default number of idle connections
+	// to the device kept for reuse.
+	DefaultMaxIdleConnsPerHost = 4
+
+	// DefaultIdleConnTimeout is the default duration an idle connection is
+	// kept for reuse, below the keep-alive timeout of most devices.
+	DefaultIdleConnTimeout = 30 * time.Second
+
+	// DefaultDialTimeout is the default connection timeout.
+	DefaultDialTimeout = 5 * time.Second
+
+	// DefaultTLSHandshakeTimeout is the default TLS handshake timeout.
+	DefaultTLSHandshakeTimeout = 5 * time.Second
+)
+
+// NewTransport creates the http.Transport used by default, with keep-alives
+// enabled and connection limits tuned for Hilink devices. Transport options
+// (ie, Proxy, MaxIdleConnsPerHost) are applied to a copy of it.
+func NewTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   DefaultDialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          DefaultMaxIdleConnsPerHost,
+		MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
+		MaxConnsPerHost:       DefaultMaxConnsPerHost,
+		IdleConnTimeout:       DefaultIdleConnTimeout,
+		TLSHandshakeTimeout:   DefaultTLSHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// MaxConnsPerHost is an option that limits the number of connections to the
+// device, queuing requests beyond the limit. Zero means no limit.
+func MaxConnsPerHost(n int) Option {
+	return transportOption(func(t *http.Transport) {
+		t.MaxConnsPerHost = n
+	})
+}
+
+// MaxIdleConnsPerHost is an option that sets the number of idle connections
+// to the device kept for reuse.
+func MaxIdleConnsPerHost(n int) Option {
+	return transportOption(func(t *http.Transport) {
+		t.MaxIdleConns = n
+		t.MaxIdleConnsPerHost = n
+	})
+}
+
+// IdleConnTimeout is an option that sets the duration an idle connection is
+// kept for reuse. Devices closing idle connections sooner than the timeout
+// cause failed requests to be retried on new connections.
+func IdleConnTimeout(d time.Duration) Option {
+	return transportOption(func(t *http.Transport) {
+		t.IdleConnTimeout = d
+	})
+}
+
+// DialTimeout is an option that sets the connection timeout. The option
+// replaces the dial function, and should be given before the DialContext or
+// TunnelDialer options when combined.
+func DialTimeout(d time.Duration) Option {
+	return transportOption(func(t *http.Transport) {
+		t.DialContext = (&net.Dialer{
+			Timeout:   d,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	})
+}
+
+// TLSHandshakeTimeout is an option that sets the TLS handshake timeout, for
+// devices reached over HTTPS.
+func TLSHandshakeTimeout(d time.Duration) Option {
+	return transportOption(func(t *http.Transport) {
+		t.TLSHandshakeTimeout = d
+	})
+}
+
+// DisableKeepAlives is an option that disables connection reuse, for
+// devices mishandling keep-alive connections.
+func DisableKeepAlives(c *Client) error {
+	return transportOption(func(t *http.Transport) {
+		t.DisableKeepAlives = true
+	})(c)
+}
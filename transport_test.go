@@ -0,0 +1,61 @@
+package hilink
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func BenchmarkTransport(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<response><ConnectionStatus>901</ConnectionStatus></response>`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		transport *http.Transport
+	}{
+		{"NewTransport", NewTransport()},
+		{"DefaultTransport", http.DefaultTransport.(*http.Transport)},
+	}
+	get := func(b *testing.B, hc *http.Client) {
+		res, err := hc.Get(srv.URL + "/api/monitoring/status")
+		if err != nil {
+			b.Errorf("expected no error, got: %v", err)
+			return
+		}
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		res.Body.Close()
+	}
+	for _, test := range tests {
+		hc := &http.Client{Transport: test.transport}
+
+		b.Run(test.name, func(b *testing.B) {
+			defer test.transport.CloseIdleConnections()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				get(b, hc)
+			}
+		})
+
+		// concurrent requests, ie of a watcher and an exporter, beyond the
+		// idle connections kept by default
+		b.Run(test.name+"Parallel", func(b *testing.B) {
+			defer test.transport.CloseIdleConnections()
+
+			b.ReportAllocs()
+			b.SetParallelism(4)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					get(b, hc)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultFirmwareCheckTimeout is the default timeout of
+	// FirmwareUpdateCheckAndWait, when the context has no deadline.
+	DefaultFirmwareCheckTimeout = time.Minute
+
+	// DefaultFirmwareUpdateTimeout is the default timeout of
+	// FirmwareUpdateAndWait, when the context has no deadline.
+	DefaultFirmwareUpdateTimeout = 30 * time.Minute
+
+	// firmwareUpdatePollInterval is the status poll interval of the online
+	// update wait helpers.
+	firmwareUpdatePollInterval = 2 * time.Second
+)
+
+// FirmwareUpdate is the online update status information.
+type FirmwareUpdate struct {
+	// Status is the online update component status.
+	Status int `xml:"CurrentComponentStatus"`
+
+	// Version is the version string of the offered firmware, empty when no
+	// new version is offered.
+	Version string `xml:"NewVersion"`
+
+	// Size is the size of the offered firmware, in bytes.
+	Size uint64 `xml:"FileSize"`
+
+	// ReleaseNote is the release note of the offered firmware, where
+	// firmware supports it.
+	ReleaseNote string `xml:"ReleaseNote"`
+
+	// Progress is the download progress of the offered firmware, in
+	// percent, where firmware supports it.
+	Progress int `xml:"DownloadProgress"`
+}
+
+// State returns the online update state (see OnlineUpdateStatus).
+func (u FirmwareUpdate) State() OnlineUpdateStatus {
+	return OnlineUpdateStatus(u.Status)
+}
+
+// Available determines if a new firmware version is offered.
+func (u FirmwareUpdate) Available() bool {
+	return u.Version != ""
+}
+
+// FirmwareUpdateCheck causes the device to check for a new firmware version.
+// The result is retrieved with FirmwareUpdate once the check completes.
+func (c *Client) FirmwareUpdateCheck() (bool, error) {
+	return c.doReqCheckOK("api/online-update/check-new-version", SimpleRequestXML())
+}
+
+// FirmwareUpdate retrieves the online update status information.
+func (c *Client) FirmwareUpdate() (*FirmwareUpdate, error) {
+	var u FirmwareUpdate
+	if err := c.doReqXML("api/online-update/status", nil, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// FirmwareVersion are the current and latest firmware versions of a device.
+type FirmwareVersion struct {
+	// Current is the version of the installed firmware.
+	Current string
+
+	// Latest is the version of the offered firmware, or Current when no new
+	// version is offered.
+	Latest string
+}
+
+// FirmwareVersion retrieves the current and latest firmware versions. The
+// latest version is the one reported by the last check (see
+// FirmwareUpdateCheck).
+func (c *Client) FirmwareVersion() (*FirmwareVersion, error) {
+	d, err := c.DeviceInformation()
+	if err != nil {
+		return nil, err
+	}
+	u, err := c.FirmwareUpdate()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &FirmwareVersion{Current: d.SoftwareVersion, Latest: d.SoftwareVersion}
+	if u.Available() {
+		v.Latest = u.Version
+	}
+	return v, nil
+}
+
+// FirmwareUpdateCheckAndWait causes the device to check for a new firmware
+// version (see FirmwareUpdateCheck), and polls the status until the check
+// completes. When the context has no deadline, DefaultFirmwareCheckTimeout
+// applies.
+//
+// The result of an earlier check is not taken as the result, until the
+// status changes (ie, to checking).
+func (c *Client) FirmwareUpdateCheckAndWait(ctx context.Context) (*FirmwareUpdate, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultFirmwareCheckTimeout)
+		defer cancel()
+	}
+
+	prev, err := c.FirmwareUpdate()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOK(c.FirmwareUpdateCheck()); err != nil {
+		return nil, err
+	}
+	return c.firmwareUpdateWait(ctx, prev.State(), nil, func(s OnlineUpdateStatus) (bool, error) {
+		switch s {
+		case OnlineUpdateStatusCheckFailed:
+			return true, fmt.Errorf("%w: check failed", ErrFirmwareUpdateFailed)
+		case OnlineUpdateStatusNewVersion, OnlineUpdateStatusUpToDate:
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
+// FirmwareUpdateStart causes the device to download and install the offered
+// firmware (see FirmwareUpdate). The device reboots once the firmware is
+// installed.
+func (c *Client) FirmwareUpdateStart() (bool, error) {
+	return c.doReqCheckOK("api/online-update/ack-newversion", SimpleRequestXML(
+		"userAckNewVersion", "1",
+	))
+}
+
+// FirmwareUpdateCancel cancels the download of the offered firmware.
+func (c *Client) FirmwareUpdateCancel() (bool, error) {
+	return c.doReqCheckOK("api/online-update/cancel-downloading", SimpleRequestXML())
+}
+
+// FirmwareUpdateAndWait starts the firmware update (see FirmwareUpdateStart),
+// and polls the status until the firmware is downloaded and being installed,
+// calling progress (if not nil) with each status. When the context has no
+// deadline, DefaultFirmwareUpdateTimeout applies. A failed download left by
+// an earlier update is ignored, until the status changes.
+//
+// The device is unreachable while the firmware is installed, and reboots
+// once installed.
+func (c *Client) FirmwareUpdateAndWait(ctx context.Context, progress func(FirmwareUpdate)) (*FirmwareUpdate, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultFirmwareUpdateTimeout)
+		defer cancel()
+	}
+
+	prev, err := c.FirmwareUpdate()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOK(c.FirmwareUpdateStart()); err != nil {
+		return nil, err
+	}
+	return c.firmwareUpdateWait(ctx, prev.State(), progress, func(s OnlineUpdateStatus) (bool, error) {
+		switch s {
+		case OnlineUpdateStatusDownloadFailed:
+			return true, fmt.Errorf("%w: download failed", ErrFirmwareUpdateFailed)
+		case OnlineUpdateStatusDownloaded, OnlineUpdateStatusUpdating:
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
+// firmwareUpdateWait polls the online update status until done reports the
+// state as final. The state prev, left by an earlier check or update, is
+// ignored until the state changes.
+func (c *Client) firmwareUpdateWait(ctx context.Context, prev OnlineUpdateStatus, progress func(FirmwareUpdate), done func(OnlineUpdateStatus) (bool, error)) (*FirmwareUpdate, error) {
+	t := time.NewTicker(firmwareUpdatePollInterval)
+	defer t.Stop()
+
+	started := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+
+		u, err := c.FirmwareUpdate()
+		if err != nil {
+			return nil, err
+		}
+		if progress != nil {
+			progress(*u)
+		}
+		if u.State() != prev {
+			started = true
+		}
+		if !started {
+			continue
+		}
+		if ok, err := done(u.State()); ok {
+			return u, err
+		}
+	}
+}
+
+// FirmwareWatcher periodically checks for a new firmware version, and
+// notifies when one is offered.
+type FirmwareWatcher struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Interval is the check interval. Defaults to 24 hours.
+	Interval time.Duration
+
+	// Wait is the duration the device is given to complete a check before
+	// the result is retrieved. Defaults to 30 seconds.
+	Wait time.Duration
+
+	// OnUpdate is called once for each new firmware version offered.
+	OnUpdate func(FirmwareUpdate)
+
+	// OnError is called when a check fails.
+	OnError func(error)
+
+	// Store, when set, persists the last notified version, so that a
+	// version is not notified again after a restart.
+	Store Store
+
+	last string
+}
+
+// Run runs the watcher until the context is closed.
+func (w *FirmwareWatcher) Run(ctx context.Context) error {
+	if w.Client == nil {
+		return ErrNilClient
+	}
+
+	interval := w.Interval
+	if interval == 0 {
+		interval = 24 * time.Hour
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if err := w.check(ctx); err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// check triggers a check and notifies when a new version is offered.
+func (w *FirmwareWatcher) check(ctx context.Context) error {
+	if err := checkOK(w.Client.FirmwareUpdateCheck()); err != nil {
+		return err
+	}
+
+	wait := w.Wait
+	if wait == 0 {
+		wait = 30 * time.Second
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-time.After(wait):
+	}
+
+	u, err := w.Client.FirmwareUpdate()
+	if err != nil {
+		return err
+	}
+
+	// restore last notified version
+	if w.last == "" && w.Store != nil {
+		if w.last, _, err = w.Store.Get("firmware/last-version"); err != nil {
+			return err
+		}
+	}
+	if !u.Available() || u.Version == w.last {
+		return nil
+	}
+
+	w.last = u.Version
+	if w.Store != nil {
+		if err := w.Store.Set("firmware/last-version", u.Version); err != nil {
+			return err
+		}
+	}
+	if w.OnUpdate != nil {
+		w.OnUpdate(*u)
+	}
+
+	return nil
+}
+
+// FirmwareUpdateWebhook returns a FirmwareWatcher OnUpdate func that POSTs
+// each offered update as JSON to rawurl, within DefaultWebhookTimeout.
+// Delivery errors are passed to onError, if not nil.
+func FirmwareUpdateWebhook(rawurl string, onError func(error)) func(FirmwareUpdate) {
+	return func(u FirmwareUpdate) {
+		v := struct {
+			Version     string `json:"version"`
+			Size        uint64 `json:"size"`
+			ReleaseNote string `json:"release_note,omitempty"`
+		}{u.Version, u.Size, u.ReleaseNote}
+		if err := postWebhook(rawurl, v); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
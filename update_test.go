@@ -0,0 +1,27 @@
+package hilink
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFirmwareUpdateCheckAndWaitStale(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+
+	// the up to date result of an earlier check is reported until the
+	// check completes
+	d.Sequences["/api/online-update/status"] = []string{
+		`<response><CurrentComponentStatus>14</CurrentComponentStatus></response>`,
+		`<response><CurrentComponentStatus>14</CurrentComponentStatus></response>`,
+		`<response><CurrentComponentStatus>12</CurrentComponentStatus><NewVersion>21.333.01.00.00</NewVersion></response>`,
+	}
+
+	u, err := d.client(t).FirmwareUpdateCheckAndWait(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if u.State() != OnlineUpdateStatusNewVersion || u.Version != "21.333.01.00.00" {
+		t.Errorf("expected new version, got: %+v", u)
+	}
+}
@@ -0,0 +1,135 @@
+package hilink
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf16"
+)
+
+const (
+	// DefaultUssdTimeout is the default timeout of UssdSendAndWait, when the
+	// context has no deadline.
+	DefaultUssdTimeout = 30 * time.Second
+
+	// ussdPollInterval is the status poll interval of UssdSendAndWait.
+	ussdPollInterval = 500 * time.Millisecond
+)
+
+// UssdSendAndWait sends a USSD code (see UssdCode), waits for the network
+// response, and retrieves it (see UssdContent). When the context has no
+// deadline, DefaultUssdTimeout applies. The USSD session is released when
+// no response is received.
+//
+// Menu responses can be replied to with UssdSendAndWait, or with a
+// UssdSession.
+func (c *Client) UssdSendAndWait(ctx context.Context, code string) (string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultUssdTimeout)
+		defer cancel()
+	}
+
+	content, err := c.ussdSendAndWait(ctx, code)
+	if err != nil {
+		_, _ = c.UssdRelease()
+		return "", err
+	}
+	return content, nil
+}
+
+// ussdSendAndWait sends a USSD code, and waits for the response.
+func (c *Client) ussdSendAndWait(ctx context.Context, code string) (string, error) {
+	if err := checkOK(c.UssdCode(code)); err != nil {
+		return "", err
+	}
+
+	t := time.NewTicker(ussdPollInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-t.C:
+		}
+
+		s, err := c.UssdStatus()
+		if err != nil {
+			return "", err
+		}
+		if s != UssdStateActive {
+			break
+		}
+	}
+
+	return c.UssdContent()
+}
+
+// UssdSession is an interactive USSD session, for multi-step USSD menus,
+// for example:
+//
+//	s := &UssdSession{Client: client}
+//	defer s.Close()
+//	menu, err := s.Send(ctx, "*100#")
+//	// ...
+//	res, err := s.Send(ctx, "2")
+type UssdSession struct {
+	// Client is the client of the device.
+	Client *Client
+}
+
+// Send sends the USSD code, or the reply to the previous menu, returning the
+// network response (see UssdSendAndWait).
+func (s *UssdSession) Send(ctx context.Context, input string) (string, error) {
+	if s.Client == nil {
+		return "", ErrNilClient
+	}
+	return s.Client.UssdSendAndWait(ctx, input)
+}
+
+// Close releases the USSD session.
+func (s *UssdSession) Close() error {
+	if s.Client == nil {
+		return ErrNilClient
+	}
+	return checkOK(s.Client.UssdRelease())
+}
+
+// DecodeUssd decodes USSD content that some firmwares return as UCS-2 hex
+// (ie, "00420061006C" for "Bal"), returning other content unchanged.
+// Content is only decoded when it is all hex digits, is a whole number of
+// UCS-2 characters, and decodes to printable text. Content of only decimal
+// digits is decoded only when all of its characters are in the Latin-1
+// range (ie, "0031003200330034" for "1234"), as it is otherwise more likely
+// a plain number.
+func DecodeUssd(s string) string {
+	if len(s) == 0 || len(s)%4 != 0 {
+		return s
+	}
+	if strings.Trim(s, "0123456789") == "" {
+		for i := 0; i < len(s); i += 4 {
+			if s[i:i+2] != "00" {
+				return s
+			}
+		}
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return s
+	}
+
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	r := utf16.Decode(u)
+	for _, c := range r {
+		if c == unicode.ReplacementChar || !unicode.IsPrint(c) && !unicode.IsSpace(c) {
+			return s
+		}
+	}
+	return string(r)
+}
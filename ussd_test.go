@@ -0,0 +1,24 @@
+package hilink
+
+import (
+	"testing"
+)
+
+func TestDecodeUssd(t *testing.T) {
+	tests := []struct {
+		s, exp string
+	}{
+		{"Balance: 10.00", "Balance: 10.00"},
+		{"00420061006C", "Bal"},
+		{"0031003200330034", "1234"},
+		{"00420061006", "00420061006"},
+		{"12345678", "12345678"},
+		{"00070008", "00070008"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if s := DecodeUssd(test.s); s != test.exp {
+			t.Errorf("%q: expected %q, got: %q", test.s, test.exp, s)
+		}
+	}
+}
@@ -2,9 +2,15 @@ package hilink
 
 import (
 	"bytes"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/clbanning/mxj"
 )
@@ -30,18 +36,157 @@ var (
 
 	// ErrMessageTooLong is the message too long error.
 	ErrMessageTooLong = errors.New("message too long")
+
+	// ErrInvalidPhoneNumber is the invalid phone number error.
+	ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+	// ErrInvalidPin is the invalid PIN error.
+	ErrInvalidPin = errors.New("invalid PIN")
+
+	// ErrInvalidPuk is the invalid PUK error.
+	ErrInvalidPuk = errors.New("invalid PUK")
+
+	// ErrInvalidBand is the invalid LTE band (or band mask) error.
+	ErrInvalidBand = errors.New("invalid band")
+
+	// ErrInvalidPlmn is the invalid PLMN (operator MCC and MNC) error.
+	ErrInvalidPlmn = errors.New("invalid PLMN")
+
+	// ErrInvalidWifiPassword is the invalid WiFi password (WPA passphrase)
+	// error.
+	ErrInvalidWifiPassword = errors.New("invalid WiFi password")
+
+	// ErrInvalidMACAddress is the invalid MAC address error.
+	ErrInvalidMACAddress = errors.New("invalid MAC address")
+
+	// ErrInvalidIPAddress is the invalid IP address error.
+	ErrInvalidIPAddress = errors.New("invalid IP address")
+
+	// ErrInvalidProxyURL is the invalid proxy URL error.
+	ErrInvalidProxyURL = errors.New("invalid proxy URL")
+
+	// ErrUnsupportedTransport is the unsupported transport error.
+	ErrUnsupportedTransport = errors.New("unsupported transport: transport options require an *http.Transport")
+
+	// ErrNoInterfaceAddress is the no interface address error.
+	ErrNoInterfaceAddress = errors.New("no IPv4 address on interface")
+
+	// ErrNoDefaultGateway is the no default gateway error.
+	ErrNoDefaultGateway = errors.New("no default gateway")
+
+	// ErrDeviceUnreachable is the device unreachable error.
+	ErrDeviceUnreachable = errors.New("device unreachable")
+
+	// ErrInvalidServerSignature is the invalid server signature error,
+	// returned when the device fails to prove knowledge of the password
+	// during a SCRAM login.
+	ErrInvalidServerSignature = errors.New("invalid server signature")
+
+	// ErrInvalidPublicKey is the invalid public key error.
+	ErrInvalidPublicKey = errors.New("invalid public key")
+
+	// ErrConnectionFailed is the connection (dialup) failed error.
+	ErrConnectionFailed = errors.New("connection failed")
+
+	// ErrNoSession is the no session error.
+	ErrNoSession = errors.New("no session")
+
+	// ErrRequestFailed is the request failed error, returned when the device
+	// does not respond with OK.
+	ErrRequestFailed = errors.New("request failed")
+
+	// ErrNilClient is the nil client error.
+	ErrNilClient = errors.New("nil client")
+
+	// ErrNilManager is the nil manager error.
+	ErrNilManager = errors.New("nil manager")
+
+	// ErrNilWatcher is the nil watcher error.
+	ErrNilWatcher = errors.New("nil watcher")
+
+	// ErrRunnerStarted is the runner already started error.
+	ErrRunnerStarted = errors.New("runner already started")
+
+	// ErrWatcherPanic is the watcher panic error.
+	ErrWatcherPanic = errors.New("watcher panic")
+
+	// ErrPinLastAttempt is the last PIN attempt remaining error.
+	ErrPinLastAttempt = errors.New("only one PIN attempt remaining")
+
+	// ErrPukLastAttempt is the last PUK attempt remaining error.
+	ErrPukLastAttempt = errors.New("only one PUK attempt remaining")
+
+	// ErrInvalidCronSpec is the invalid cron spec error.
+	ErrInvalidCronSpec = errors.New("invalid cron spec")
+
+	// ErrResourceNotFound is the resource not found error.
+	ErrResourceNotFound = errors.New("resource not found")
+
+	// ErrResourceExists is the conflicting resource exists error.
+	ErrResourceExists = errors.New("conflicting resource exists")
+
+	// ErrResourceFull is the no free resource slot error.
+	ErrResourceFull = errors.New("no free resource slot")
+
+	// ErrATCommandsDisabled is the AT commands disabled error.
+	ErrATCommandsDisabled = errors.New("AT commands not enabled")
+
+	// ErrInvalidATCommand is the invalid AT command error.
+	ErrInvalidATCommand = errors.New("invalid AT command")
+
+	// ErrBrokenEndpoint is the endpoint known to be broken error.
+	ErrBrokenEndpoint = errors.New("endpoint known to be broken on device")
+
+	// ErrFirmwareUpdateFailed is the firmware update check or download failed
+	// error.
+	ErrFirmwareUpdateFailed = errors.New("firmware update failed")
 )
 
 // SmsBoxType represents the different inbox types available on a hilink device.
 type SmsBoxType uint
 
-// SmsBoxType values.
+// SmsBoxType values. The outbox is the box of sent messages. The SIM and
+// mixed (device and SIM) boxes are only available on some firmware.
 const (
 	SmsBoxTypeInbox SmsBoxType = iota + 1
 	SmsBoxTypeOutbox
 	SmsBoxTypeDraft
+	SmsBoxTypeTrash
+	SmsBoxTypeSimInbox
+	SmsBoxTypeSimOutbox
+	SmsBoxTypeSimDraft
+	SmsBoxTypeMixInbox
+	SmsBoxTypeMixOutbox
+	SmsBoxTypeMixDraft
 )
 
+// String satisfies the fmt.Stringer interface.
+func (t SmsBoxType) String() string {
+	switch t {
+	case SmsBoxTypeInbox:
+		return "inbox"
+	case SmsBoxTypeOutbox:
+		return "outbox"
+	case SmsBoxTypeDraft:
+		return "draft"
+	case SmsBoxTypeTrash:
+		return "trash"
+	case SmsBoxTypeSimInbox:
+		return "sim inbox"
+	case SmsBoxTypeSimOutbox:
+		return "sim outbox"
+	case SmsBoxTypeSimDraft:
+		return "sim draft"
+	case SmsBoxTypeMixInbox:
+		return "mix inbox"
+	case SmsBoxTypeMixOutbox:
+		return "mix outbox"
+	case SmsBoxTypeMixDraft:
+		return "mix draft"
+	}
+	return fmt.Sprintf("SmsBoxType(%d)", uint(t))
+}
+
 // PinType are the PIN types for a PIN command.
 type PinType int
 
@@ -64,24 +209,66 @@ const (
 	UssdStateWaiting
 )
 
+// NRMode represents the 5G NR modes (SA/NSA) available on 5G devices.
+type NRMode int
+
+// NRMode values.
+const (
+	NRModeAuto NRMode = iota
+	NRModeNSA
+	NRModeSA
+)
+
 // XMLData is a map of XML data to encode/decode.
 type XMLData mxj.Map
 
-// xmlPairs combines xml name/value pairs as a properly formatted XML buffer.
+// xmlPairs combines xml name/value pairs as a properly formatted XML buffer,
+// escaping the values.
 func xmlPairs(indent string, vals ...string) []byte {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	writeXMLPairs(buf, indent, vals...)
+	return append([]byte(nil), buf.Bytes()...)
+}
+
+// writeXMLPairs writes the xml name/value pairs to buf, escaping the values.
+func writeXMLPairs(buf *bytes.Buffer, indent string, vals ...string) {
 	// make sure we have pairs
 	if len(vals)%2 != 0 {
 		panic(fmt.Errorf("xmlPairs can only accept pairs of strings, length: %d", len(vals)))
 	}
 
-	var buf bytes.Buffer
-
-	// loop over pairs
 	for i := 0; i < len(vals); i += 2 {
-		buf.WriteString(fmt.Sprintf("%s<%s>%s</%s>\n", indent, vals[i], vals[i+1], vals[i]))
+		writeXMLPair(buf, indent, vals[i], vals[i+1], false)
 	}
+}
 
-	return buf.Bytes()
+// writeXMLPair writes the xml name/value pair to buf. The value is escaped,
+// unless it is an XML fragment.
+func writeXMLPair(buf *bytes.Buffer, indent, name, value string, fragment bool) {
+	buf.WriteString(indent)
+	buf.WriteByte('<')
+	buf.WriteString(name)
+	buf.WriteByte('>')
+	if fragment {
+		buf.WriteString(value)
+	} else {
+		escapeXMLText(buf, value)
+	}
+	buf.WriteString("</")
+	buf.WriteString(name)
+	buf.WriteString(">\n")
+}
+
+// escapeXMLText writes the escaped text s to buf.
+func escapeXMLText(buf *bytes.Buffer, s string) {
+	// most values need no escaping
+	if strings.IndexAny(s, "<>&'\"\t\r\n") == -1 && utf8.ValidString(s) {
+		buf.WriteString(s)
+		return
+	}
+	_ = xml.EscapeText(buf, []byte(s))
 }
 
 // xmlPairsString builds a string of XML pairs.
@@ -90,11 +277,41 @@ func xmlPairsString(indent string, vals ...string) string {
 }
 
 // xmlNvp (ie, name value pair) builds a <Name>name</Name><Value>value</Value> XML pair.
-func xmlNvp(name, value string) string {
-	return xmlPairsString("", "Name", name, "Value", value)
+func xmlNvp(name, value string) xmlFragment {
+	return xmlFragment(xmlPairsString("", "Name", name, "Value", value))
+}
+
+// xmlFragment is an encoded XML request value (ie, built with xmlPairs),
+// written as is by requestXML.
+type xmlFragment string
+
+// bufferPool is the pool of request buffers.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
 }
 
-// SimpleRequestXML creates an XML string from value pairs.
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns the buffer to the pool.
+func putBuffer(buf *bytes.Buffer) {
+	// do not retain large buffers (ie, file uploads)
+	if buf.Cap() <= 64<<10 {
+		bufferPool.Put(buf)
+	}
+}
+
+// SimpleRequestXML creates an XML string from value pairs. The values are
+// escaped, so that values already escaped (ie, "AT&amp;T") or XML fragments
+// (ie, "<Index>1</Index>") are escaped twice, and sent as text. Nested
+// elements can be sent with an XMLData of nested maps passed to Do, where the
+// element order is not kept.
 //
 // Unfortunately the XML parser (or whatever underyling code) included with the
 // WebUI on Hilink devices expects parameters in a specific order. This makes
@@ -103,21 +320,56 @@ func xmlNvp(name, value string) string {
 //
 // On another note, XML sucks.
 func SimpleRequestXML(vals ...string) []byte {
-	var buf bytes.Buffer
+	// pool buffers, as requests are built by pollers and gateways at high
+	// frequency
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	buf.WriteString(xmlHeader)
+	buf.WriteString(xmlRequestStart)
+	writeXMLPairs(buf, "  ", vals...)
+	buf.WriteString(xmlRequestEnd)
+
+	return append([]byte(nil), buf.Bytes()...)
+}
 
-	// write header
-	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
-	buf.WriteString("\n<request>\n")
+// requestXML creates an XML string from name/value pairs (see
+// SimpleRequestXML), where the values are either a string, escaped, or an
+// xmlFragment, written as is.
+func requestXML(vals ...interface{}) []byte {
+	// make sure we have pairs
+	if len(vals)%2 != 0 {
+		panic(fmt.Errorf("requestXML can only accept pairs, length: %d", len(vals)))
+	}
 
-	// add pairs
-	buf.Write(xmlPairs("  ", vals...))
+	buf := getBuffer()
+	defer putBuffer(buf)
 
-	// end string
-	buf.WriteString("</request>\n")
+	buf.WriteString(xmlHeader)
+	buf.WriteString(xmlRequestStart)
+	for i := 0; i < len(vals); i += 2 {
+		name := vals[i].(string)
+		switch v := vals[i+1].(type) {
+		case xmlFragment:
+			writeXMLPair(buf, "  ", name, string(v), true)
+		case string:
+			writeXMLPair(buf, "  ", name, v, false)
+		default:
+			panic(fmt.Errorf("requestXML unsupported value type %T", v))
+		}
+	}
+	buf.WriteString(xmlRequestEnd)
 
-	return buf.Bytes()
+	return append([]byte(nil), buf.Bytes()...)
 }
 
+// Request XML envelope.
+const (
+	xmlHeader       = `<?xml version="1.0" encoding="UTF-8"?>`
+	xmlRequestStart = "\n<request>\n"
+	xmlRequestEnd   = "</request>\n"
+)
+
 // boolToString converts a bool to a "0" or "1".
 func boolToString(b bool) string {
 	if b {
@@ -126,6 +378,37 @@ func boolToString(b bool) string {
 	return "0"
 }
 
+// checkOK converts the result of a request operation checking for OK (ie,
+// doReqCheckOK) into an error.
+func checkOK(ok bool, err error) error {
+	switch {
+	case err != nil:
+		return err
+	case !ok:
+		return ErrRequestFailed
+	}
+	return nil
+}
+
+// parseInt parses a decimal integer, returning 0 if s cannot be parsed.
+func parseInt(s string) int {
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+// parseUint parses a decimal unsigned integer, returning 0 if s cannot be
+// parsed.
+func parseUint(s string) uint64 {
+	i, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
 // ErrorCodeMessageMap contains the known message strings for Hilink devices.
 //
 // see: http://www.bez-kabli.pl/viewtopic.php?t=42168
@@ -148,7 +431,7 @@ var ErrorCodeMessageMap = map[string]string{
 	"111019": "no network response",
 	"111020": "network timeout",
 	"111022": "network not supported",
-	"113018": "system busy",
+	"113018": "SMS storage full",
 	"114001": "file already exists",
 	"114002": "file already exists",
 	"114003": "SD card currently in use",
@@ -160,6 +443,31 @@ var ErrorCodeMessageMap = map[string]string{
 	"117004": "incorrect WISPr password",
 	"120001": "voice busy",
 	"125001": "invalid token",
+	"125002": "invalid session",
+	"125003": "invalid session token",
+}
+
+// multipartFile is a multipart/form-data file upload request body.
+type multipartFile struct {
+	contentType string
+	data        []byte
+}
+
+// newMultipartFile builds a multipart/form-data request body uploading data
+// as the named file field.
+func newMultipartFile(field, filename string, data []byte) *multipartFile {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	// writes to a bytes.Buffer do not fail
+	f, _ := w.CreateFormFile(field, filename)
+	_, _ = f.Write(data)
+	_ = w.Close()
+
+	return &multipartFile{
+		contentType: w.FormDataContentType(),
+		data:        buf.Bytes(),
+	}
 }
 
 // encodeXML encodes a map to standard XML values.
@@ -172,9 +480,9 @@ func encodeXML(v interface{}) (io.Reader, error) {
 		buf = x
 
 	case XMLData:
-		// wrap in request element
+		// wrap in request element, escaping the values (mxj does not)
 		m := mxj.Map(map[string]interface{}{
-			"request": map[string]interface{}(x),
+			"request": escapeXMLValues(map[string]interface{}(x)),
 		})
 
 		// encode xml
@@ -190,6 +498,39 @@ func encodeXML(v interface{}) (io.Reader, error) {
 	return bytes.NewReader(buf), nil
 }
 
+// escapeXMLValues returns a copy of v with the string values escaped.
+func escapeXMLValues(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		buf := getBuffer()
+		defer putBuffer(buf)
+		escapeXMLText(buf, x)
+		return buf.String()
+
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(x))
+		for k, v := range x {
+			m[k] = escapeXMLValues(v)
+		}
+		return m
+
+	case []interface{}:
+		l := make([]interface{}, len(x))
+		for i, v := range x {
+			l[i] = escapeXMLValues(v)
+		}
+		return l
+
+	case []map[string]interface{}:
+		l := make([]map[string]interface{}, len(x))
+		for i, v := range x {
+			l[i] = escapeXMLValues(v).(map[string]interface{})
+		}
+		return l
+	}
+	return v
+}
+
 // decodeXML decodes buf into its simple xml values.
 func decodeXML(buf []byte, takeFirstEl bool) (interface{}, error) {
 	// decode xml
@@ -205,14 +546,9 @@ func decodeXML(buf []byte, takeFirstEl bool) (interface{}, error) {
 			return nil, ErrInvalidError
 		}
 
-		// grab message if not passed by the api
+		code, _ := z["code"].(string)
 		msg, _ := z["message"].(string)
-		if msg == "" {
-			c, _ := z["code"].(string)
-			msg = ErrorCodeMessageMap[c]
-		}
-
-		return nil, fmt.Errorf("hilink error %v: %s", z["code"], msg)
+		return nil, hilinkError(code, msg)
 	}
 
 	// check there is only one element
@@ -243,3 +579,21 @@ func decodeXML(buf []byte, takeFirstEl bool) (interface{}, error) {
 
 	return t, nil
 }
+
+// unmarshalXML decodes buf into v using encoding/xml, returning the api error
+// when buf contains an <error/> response.
+func unmarshalXML(buf []byte, v interface{}) error {
+	var e struct {
+		XMLName xml.Name
+		Code    string `xml:"code"`
+		Message string `xml:"message"`
+	}
+	if err := xml.Unmarshal(buf, &e); err != nil {
+		return err
+	}
+	if e.XMLName.Local == "error" {
+		return hilinkError(e.Code, e.Message)
+	}
+
+	return xml.Unmarshal(buf, v)
+}
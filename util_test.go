@@ -0,0 +1,118 @@
+package hilink
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/clbanning/mxj"
+)
+
+func TestSimpleRequestXML(t *testing.T) {
+	buf := SimpleRequestXML("Content", `AT&T <3 "x"`, "Length", "7")
+	exp := `<?xml version="1.0" encoding="UTF-8"?>
+<request>
+  <Content>AT&amp;T &lt;3 &#34;x&#34;</Content>
+  <Length>7</Length>
+</request>
+`
+	if string(buf) != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, buf)
+	}
+}
+
+func TestSimpleRequestXMLInjection(t *testing.T) {
+	buf := SimpleRequestXML("Content", "</Content><Reserved>0</Reserved><Content>")
+	m, err := mxj.NewMapXml(buf)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	req := m["request"].(map[string]interface{})
+	if _, ok := req["Reserved"]; ok {
+		t.Errorf("expected no injected element, got: %v", req)
+	}
+	if v := req["Content"]; v != "</Content><Reserved>0</Reserved><Content>" {
+		t.Errorf("expected content to round trip, got: %v", v)
+	}
+}
+
+func TestRequestXMLFragment(t *testing.T) {
+	buf := requestXML(
+		"Phones", xmlFragment("\n"+xmlPairsString("    ", "Phone", "<1>", "Phone", "2")),
+		"Content", "a&b",
+	)
+	exp := `<?xml version="1.0" encoding="UTF-8"?>
+<request>
+  <Phones>
+    <Phone>&lt;1&gt;</Phone>
+    <Phone>2</Phone>
+</Phones>
+  <Content>a&amp;b</Content>
+</request>
+`
+	if string(buf) != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, buf)
+	}
+}
+
+func TestEncodeXMLEscapes(t *testing.T) {
+	r, err := encodeXML(XMLData{
+		"WifiWpapsk": "pass</WifiWpapsk><WifiRestart>0",
+		"Profile":    map[string]interface{}{"Name": "a&b"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+
+	m, err := mxj.NewMapXml(buf.Bytes())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	req := m["request"].(map[string]interface{})
+	if v := req["WifiWpapsk"]; v != "pass</WifiWpapsk><WifiRestart>0" {
+		t.Errorf("expected password to round trip, got: %v", v)
+	}
+	if v := req["Profile"].(map[string]interface{})["Name"]; v != "a&b" {
+		t.Errorf("expected name to round trip, got: %v", v)
+	}
+}
+
+// simpleRequestXMLFmt is the fmt based (unpooled, unescaped) SimpleRequestXML
+// implementation, for comparison.
+func simpleRequestXMLFmt(vals ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	buf.WriteString("\n<request>\n")
+	var pairs bytes.Buffer
+	for i := 0; i < len(vals); i += 2 {
+		pairs.WriteString(fmt.Sprintf("%s<%s>%s</%s>\n", "  ", vals[i], vals[i+1], vals[i]))
+	}
+	buf.Write(pairs.Bytes())
+	buf.WriteString("</request>\n")
+	return buf.Bytes()
+}
+
+func BenchmarkSimpleRequestXML(b *testing.B) {
+	vals := []string{
+		"PageIndex", "1",
+		"ReadCount", "20",
+		"BoxType", "1",
+		"SortType", "0",
+		"Ascending", "0",
+		"UnreadPreferred", "0",
+	}
+	b.Run("fmt", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			simpleRequestXMLFmt(vals...)
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			SimpleRequestXML(vals...)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package hilink
+
+import (
+	"fmt"
+	"net"
+)
+
+// ValidatePhoneNumber checks that s is a plausible (E.164-ish) phone number:
+// an optional leading '+' followed by 3 to 15 digits. Short codes (ie, 3 or
+// 4 digit service numbers) are accepted.
+func ValidatePhoneNumber(s string) error {
+	d := s
+	if len(d) > 0 && d[0] == '+' {
+		d = d[1:]
+	}
+	if len(d) < 3 || len(d) > 15 || !isDigits(d) {
+		return fmt.Errorf("%w %q", ErrInvalidPhoneNumber, s)
+	}
+	return nil
+}
+
+// ValidatePin checks that pin is a valid SIM PIN (4 to 8 digits).
+func ValidatePin(pin string) error {
+	if len(pin) < 4 || len(pin) > 8 || !isDigits(pin) {
+		return fmt.Errorf("%w: must be 4 to 8 digits", ErrInvalidPin)
+	}
+	return nil
+}
+
+// ValidatePuk checks that puk is a valid SIM PUK (8 digits).
+func ValidatePuk(puk string) error {
+	if len(puk) != 8 || !isDigits(puk) {
+		return fmt.Errorf("%w: must be 8 digits", ErrInvalidPuk)
+	}
+	return nil
+}
+
+// ValidateMACAddress checks that addr is a 48-bit MAC address in the
+// colon-separated form expected by the WebUI (ie, 00:11:22:33:44:55).
+func ValidateMACAddress(addr string) error {
+	hw, err := net.ParseMAC(addr)
+	if err != nil || len(hw) != 6 || len(addr) != 17 || addr[2] != ':' {
+		return fmt.Errorf("%w %q", ErrInvalidMACAddress, addr)
+	}
+	return nil
+}
+
+// ValidateIPv4Address checks that addr is a dotted IPv4 address.
+func ValidateIPv4Address(addr string) error {
+	ip := net.ParseIP(addr)
+	if ip == nil || ip.To4() == nil {
+		return fmt.Errorf("%w %q", ErrInvalidIPAddress, addr)
+	}
+	return nil
+}
+
+// isDigits determines if s is made up entirely of ASCII digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
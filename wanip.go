@@ -0,0 +1,39 @@
+package hilink
+
+// WANIP are the WAN addresses of the mobile connection.
+type WANIP struct {
+	// IPv4 is the WAN IPv4 address, if any.
+	IPv4 string
+
+	// IPv6 is the WAN IPv6 address, if any.
+	IPv6 string
+}
+
+// WANIP retrieves the WAN addresses of the mobile connection from the device
+// status, falling back to the connection (dialup) settings on firmware that
+// reports them there. The addresses are empty when not connected.
+//
+// Note that the WAN address is only the public address when the carrier does
+// not use carrier-grade NAT.
+func (c *Client) WANIP() (*WANIP, error) {
+	s, err := c.Status()
+	if err != nil {
+		return nil, err
+	}
+	return c.wanIP(s), nil
+}
+
+// wanIP returns the WAN addresses of the device status, falling back to the
+// connection (dialup) settings when the status reports none.
+func (c *Client) wanIP(s *Status) *WANIP {
+	ip := &WANIP{IPv4: s.WanIPAddress, IPv6: s.WanIPv6Address}
+	if ip.IPv4 != "" || ip.IPv6 != "" {
+		return ip
+	}
+
+	x, err := c.dialupConnection()
+	if err != nil {
+		return ip
+	}
+	return &WANIP{IPv4: x.get("WanIPAddress"), IPv6: x.get("WanIPv6Address")}
+}
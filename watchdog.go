@@ -0,0 +1,223 @@
+package hilink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ErrNotConnected is the connection not established error, reported by
+// Watchdog checks.
+var ErrNotConnected = errors.New("not connected")
+
+// Watchdog monitors the connectivity of a device, and escalates through the
+// remediation steps while the connectivity is not restored, for example:
+//
+//	w := &Watchdog{
+//		Client: client,
+//		Target: "1.1.1.1:53",
+//		Logf:   log.Printf,
+//	}
+//	err := w.Run(ctx)
+//
+// A check fails when the device cannot be reached or is not connected, or
+// when the target cannot be reached. Errors reported by the device (see
+// APIError), ie while it is busy, are logged without failing the check.
+// After Failures consecutive failed checks, the next step is
+// performed (by default: redial, toggle the mobile data, and reboot the
+// device), and checks resume after the step's grace period. A successful
+// check resets the escalation to the first step.
+type Watchdog struct {
+	// Client is the client of the device.
+	Client *Client
+
+	// Interval is the check interval. Defaults to 30 seconds.
+	Interval time.Duration
+
+	// Scheduler, when set, polls the connection status along with the
+	// other consumers of the device (see Scheduler). The target is still
+	// dialed by each check.
+	Scheduler *Scheduler
+
+	// Target, when set, is the TCP address (ie, "1.1.1.1:53") that must be
+	// reachable through the connection.
+	Target string
+
+	// Dial dials the target. Defaults to a net.Dialer with a 10 second
+	// timeout. It can be used to route the check through the device's
+	// interface.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// Failures is the number of consecutive failed checks before a step is
+	// performed. Defaults to 3.
+	Failures int
+
+	// Steps are the escalation steps. Defaults to DefaultWatchdogSteps.
+	Steps []WatchdogStep
+
+	// Policy, when set, is evaluated after each check (see
+	// PolicyEngine.Evaluate), for rules beyond connectivity.
+	Policy *PolicyEngine
+
+	// OnStep is called after a step is performed, with its error, if any.
+	OnStep func(WatchdogStep, error)
+
+	// Logf is the audit logger (ie, log.Printf), recording failed checks,
+	// device errors and performed steps.
+	Logf func(string, ...interface{})
+
+	failures int
+	step     int
+}
+
+// WatchdogStep is a Watchdog escalation step.
+type WatchdogStep struct {
+	// Action is the performed action.
+	Action Action
+
+	// Grace is the duration after the action before checks resume, ie to let
+	// the device reconnect or reboot.
+	Grace time.Duration
+}
+
+// DefaultWatchdogSteps are the default Watchdog escalation steps: redial,
+// toggle the mobile data, and reboot the device.
+var DefaultWatchdogSteps = []WatchdogStep{
+	{Action: ActionReconnect, Grace: time.Minute},
+	{Action: ActionMobileDataToggle, Grace: time.Minute},
+	{Action: ActionReboot, Grace: 3 * time.Minute},
+}
+
+// Run runs the watchdog until the context is closed.
+func (w *Watchdog) Run(ctx context.Context) error {
+	if w.Client == nil {
+		return ErrNilClient
+	}
+
+	interval := w.Interval
+	if interval == 0 {
+		interval = 30 * time.Second
+	}
+
+	p := newPoller(w.Scheduler, w.Client, "api/monitoring/status", interval)
+	defer p.close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.C():
+		}
+
+		st, err := decodeStatus(p.fetch())
+		if grace := w.checkStatus(ctx, st, err); grace > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(grace):
+			}
+
+			// the status polled during the grace period is stale
+			p.reset()
+		}
+	}
+}
+
+// Check checks the connectivity once, performing the next step when the
+// failure threshold is reached, and returns the grace period of the
+// performed step (or zero).
+func (w *Watchdog) Check(ctx context.Context) time.Duration {
+	st, err := w.Client.Status()
+	return w.checkStatus(ctx, st, err)
+}
+
+// checkStatus checks the connectivity with the retrieved connection status,
+// as Check.
+func (w *Watchdog) checkStatus(ctx context.Context, st *Status, err error) time.Duration {
+	if w.Policy != nil {
+		defer w.Policy.Evaluate(ctx, time.Now())
+	}
+
+	err = w.check(ctx, st, err)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		w.logf("watchdog: status unavailable: %v", err)
+		return 0
+	}
+	if err == nil {
+		if w.failures != 0 {
+			w.logf("watchdog: connectivity restored")
+		}
+		w.failures, w.step = 0, 0
+		return 0
+	}
+
+	w.failures++
+	w.logf("watchdog: check failed (%d): %v", w.failures, err)
+
+	threshold := w.Failures
+	if threshold == 0 {
+		threshold = 3
+	}
+	if w.failures < threshold {
+		return 0
+	}
+
+	steps := w.Steps
+	if steps == nil {
+		steps = DefaultWatchdogSteps
+	}
+	if len(steps) == 0 {
+		return 0
+	}
+
+	// the last step is repeated
+	s := steps[w.step]
+	if w.step < len(steps)-1 {
+		w.step++
+	}
+	w.failures = 0
+
+	err = s.Action.Do(ctx, w.Client)
+	if err != nil {
+		w.logf("watchdog: %s failed: %v", s.Action.Name, err)
+	} else {
+		w.logf("watchdog: performed %s", s.Action.Name)
+	}
+	if w.OnStep != nil {
+		w.OnStep(s, err)
+	}
+	return s.Grace
+}
+
+// check checks the connection status, and the target.
+func (w *Watchdog) check(ctx context.Context, s *Status, err error) error {
+	if err != nil {
+		return err
+	}
+	if s.ConnectionStatus != ConnectionStatusConnected {
+		return fmt.Errorf("%w (%s)", ErrNotConnected, s.ConnectionStatus)
+	}
+
+	if w.Target == "" {
+		return nil
+	}
+	dial := w.Dial
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 10 * time.Second}).DialContext
+	}
+	conn, err := dial(ctx, "tcp", w.Target)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// logf writes to the audit log.
+func (w *Watchdog) logf(s string, v ...interface{}) {
+	if w.Logf != nil {
+		w.Logf(s, v...)
+	}
+}
@@ -0,0 +1,119 @@
+package hilink
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Status responses.
+const (
+	connectedStatus    = `<response><ConnectionStatus>901</ConnectionStatus></response>`
+	disconnectedStatus = `<response><ConnectionStatus>902</ConnectionStatus></response>`
+)
+
+// newTestWatchdog returns a watchdog of the client with the steps a and b,
+// recording the performed steps.
+func newTestWatchdog(c *Client, performed *[]string) *Watchdog {
+	step := func(name string) WatchdogStep {
+		return WatchdogStep{
+			Action: Action{Name: name, Do: func(context.Context, *Client) error {
+				*performed = append(*performed, name)
+				return nil
+			}},
+			Grace: time.Second,
+		}
+	}
+	return &Watchdog{
+		Client:   c,
+		Failures: 2,
+		Steps:    []WatchdogStep{step("a"), step("b")},
+	}
+}
+
+func TestWatchdogEscalation(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/monitoring/status"] = disconnectedStatus
+
+	var performed []string
+	w := newTestWatchdog(d.client(t), &performed)
+
+	// a step every 2 failed checks, the last step being repeated
+	var graces []time.Duration
+	for i := 0; i < 6; i++ {
+		graces = append(graces, w.Check(context.Background()))
+	}
+	if exp := []string{"a", "b", "b"}; !reflect.DeepEqual(performed, exp) {
+		t.Errorf("expected steps %v, got: %v", exp, performed)
+	}
+	if exp := []time.Duration{0, time.Second, 0, time.Second, 0, time.Second}; !reflect.DeepEqual(graces, exp) {
+		t.Errorf("expected graces %v, got: %v", exp, graces)
+	}
+}
+
+func TestWatchdogReset(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Sequences["/api/monitoring/status"] = []string{
+		disconnectedStatus,
+		disconnectedStatus,
+		connectedStatus,
+		disconnectedStatus,
+		disconnectedStatus,
+	}
+
+	var performed []string
+	w := newTestWatchdog(d.client(t), &performed)
+
+	// a successful check restarts the escalation at the first step
+	for i := 0; i < 5; i++ {
+		w.Check(context.Background())
+	}
+	if exp := []string{"a", "a"}; !reflect.DeepEqual(performed, exp) {
+		t.Errorf("expected steps %v, got: %v", exp, performed)
+	}
+}
+
+func TestWatchdogDeviceError(t *testing.T) {
+	d := newFakeDevice()
+	defer d.Close()
+	d.Responses["/api/monitoring/status"] = `<error><code>100003</code><message></message></error>`
+
+	var performed, logs []string
+	w := newTestWatchdog(d.client(t), &performed)
+	w.Logf = func(s string, v ...interface{}) {
+		logs = append(logs, fmt.Sprintf(s, v...))
+	}
+
+	// device errors are logged, without failing the checks
+	for i := 0; i < 4; i++ {
+		w.Check(context.Background())
+	}
+	if len(performed) != 0 {
+		t.Errorf("expected no steps, got: %v", performed)
+	}
+	if len(logs) != 4 || !strings.Contains(logs[0], "status unavailable") {
+		t.Errorf("expected status unavailable logs, got: %v", logs)
+	}
+}
+
+func TestWatchdogUnreachable(t *testing.T) {
+	d := newFakeDevice()
+	c := d.client(t)
+	d.Close()
+
+	var performed []string
+	w := newTestWatchdog(c, &performed)
+
+	// transport errors fail the checks
+	for i := 0; i < 2; i++ {
+		w.Check(context.Background())
+	}
+	if exp := []string{"a"}; !reflect.DeepEqual(performed, exp) {
+		t.Errorf("expected steps %v, got: %v", exp, performed)
+	}
+}
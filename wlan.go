@@ -0,0 +1,292 @@
+package hilink
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// WlanHandover represents the band preference (handover) settings of
+// dual-band devices.
+type WlanHandover int
+
+// WlanHandover values.
+const (
+	WlanHandoverBandSteering WlanHandover = iota
+	WlanHandover24GHz
+	WlanHandover5GHz
+)
+
+// String satisfies the fmt.Stringer interface.
+func (h WlanHandover) String() string {
+	switch h {
+	case WlanHandover24GHz:
+		return "2.4GHz"
+	case WlanHandover5GHz:
+		return "5GHz"
+	}
+	return "band steering"
+}
+
+// WlanHandover retrieves the band preference (handover) setting of dual-band
+// devices.
+func (c *Client) WlanHandover() (WlanHandover, error) {
+	s, err := c.doReqString("api/wlan/handover-setting", nil, "Handover")
+	if err != nil {
+		return WlanHandoverBandSteering, err
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return WlanHandoverBandSteering, ErrInvalidResponse
+	}
+
+	return WlanHandover(i), nil
+}
+
+// WlanHandoverSet sets the band preference (handover) setting of dual-band
+// devices.
+func (c *Client) WlanHandoverSet(h WlanHandover) (bool, error) {
+	return c.doReqCheckOK("api/wlan/handover-setting", SimpleRequestXML(
+		"Handover", strconv.Itoa(int(h)),
+	))
+}
+
+// GuestQuota is the session quota of guest WiFi networks.
+type GuestQuota struct {
+	// Duration is the session duration limit, with minute precision. Zero is
+	// unlimited.
+	Duration time.Duration
+
+	// DataLimit is the session data limit, in bytes, with megabyte
+	// precision. Zero is unlimited.
+	DataLimit uint64
+}
+
+// guestQuotaXML is the raw guest quota setting.
+type guestQuotaXML struct {
+	Time      string `xml:"guestwifitime"`
+	DataLimit string `xml:"guestwifidatalimit"`
+}
+
+// GuestQuota retrieves the session quota of guest WiFi networks, where
+// firmware supports it.
+func (c *Client) GuestQuota() (*GuestQuota, error) {
+	var x guestQuotaXML
+	if err := c.doReqXML("api/wlan/guesttime-setting", nil, &x); err != nil {
+		return nil, err
+	}
+	return &GuestQuota{
+		Duration:  time.Duration(parseUint(x.Time)) * time.Minute,
+		DataLimit: parseUint(x.DataLimit) << 20,
+	}, nil
+}
+
+// GuestQuotaSet sets the session quota of guest WiFi networks, where
+// firmware supports it. The duration and data limit are rounded up to the
+// next minute and megabyte, so that a quota below the unit is not set as
+// unlimited.
+func (c *Client) GuestQuotaSet(q GuestQuota) (bool, error) {
+	if q.Duration < 0 {
+		return false, fmt.Errorf("%w: negative guest quota duration", ErrInvalidValue)
+	}
+
+	minutes := (q.Duration + time.Minute - 1) / time.Minute
+	mb := q.DataLimit >> 20
+	if q.DataLimit&(1<<20-1) != 0 {
+		mb++
+	}
+	return c.doReqCheckOK("api/wlan/guesttime-setting", SimpleRequestXML(
+		"guestwifitime", strconv.FormatInt(int64(minutes), 10),
+		"guestwifidatalimit", strconv.FormatUint(mb, 10),
+	))
+}
+
+// WifiEnabledSet enables or disables the WiFi radio, retaining the other
+// basic WLAN settings.
+func (c *Client) WifiEnabledSet(enabled bool) (bool, error) {
+	d, err := c.WlanConfig()
+	if err != nil {
+		return false, err
+	}
+	d["WifiEnable"] = boolToString(enabled)
+
+	return c.doReqCheckOK("api/wlan/basic-settings", d)
+}
+
+// WlanAuthMode is the WiFi authentication (security) mode.
+type WlanAuthMode string
+
+// WlanAuthMode values.
+const (
+	WlanAuthModeOpen     WlanAuthMode = "OPEN"
+	WlanAuthModeShare    WlanAuthMode = "SHARE"
+	WlanAuthModeWPA      WlanAuthMode = "WPA-PSK"
+	WlanAuthModeWPA2     WlanAuthMode = "WPA2-PSK"
+	WlanAuthModeWPAWPA2  WlanAuthMode = "WPA/WPA2-PSK"
+	WlanAuthModeWPA3     WlanAuthMode = "WPA3-SAE"
+	WlanAuthModeWPA2WPA3 WlanAuthMode = "WPA2-PSK/WPA3-SAE"
+)
+
+// WlanBasicSettings are the basic WLAN settings.
+type WlanBasicSettings struct {
+	// SSID is the network name.
+	SSID string
+
+	// Hidden is the hide SSID (broadcast disabled) flag.
+	Hidden bool
+
+	// Channel is the radio channel. Zero is automatic.
+	Channel int
+
+	// Bandwidth is the channel bandwidth, in MHz (ie, 20 or 40). Zero is
+	// automatic.
+	Bandwidth int
+
+	// Mode is the 802.11 mode (ie, "b/g/n").
+	Mode string
+
+	// Enabled is the WiFi radio enabled flag.
+	Enabled bool
+}
+
+// wlanBasicSettingsXML is the raw basic WLAN settings.
+type wlanBasicSettingsXML struct {
+	SSID      string `xml:"WifiSsid"`
+	Hide      string `xml:"WifiHide"`
+	Channel   string `xml:"WifiChannel"`
+	Bandwidth string `xml:"wifibandwidth"`
+	Mode      string `xml:"WifiMode"`
+	Enable    string `xml:"WifiEnable"`
+}
+
+// WlanBasicSettings retrieves the basic WLAN settings.
+func (c *Client) WlanBasicSettings() (*WlanBasicSettings, error) {
+	var x wlanBasicSettingsXML
+	if err := c.doReqXML("api/wlan/basic-settings", nil, &x); err != nil {
+		return nil, err
+	}
+	return &WlanBasicSettings{
+		SSID:      x.SSID,
+		Hidden:    x.Hide == "1",
+		Channel:   int(parseUint(x.Channel)),
+		Bandwidth: int(parseUint(x.Bandwidth)),
+		Mode:      x.Mode,
+		Enabled:   x.Enable == "1",
+	}, nil
+}
+
+// WlanBasicSettingsSet sets the basic WLAN settings, retaining the settings
+// not represented by WlanBasicSettings. Devices generally restart the WiFi
+// radio, disconnecting clients.
+func (c *Client) WlanBasicSettingsSet(s WlanBasicSettings) (bool, error) {
+	d, err := c.WlanConfig()
+	if err != nil {
+		return false, err
+	}
+	d["WifiSsid"] = s.SSID
+	d["WifiHide"] = boolToString(s.Hidden)
+	d["WifiChannel"] = strconv.Itoa(s.Channel)
+	d["wifibandwidth"] = strconv.Itoa(s.Bandwidth)
+	if s.Mode != "" {
+		d["WifiMode"] = s.Mode
+	}
+	d["WifiEnable"] = boolToString(s.Enabled)
+	d["WifiRestart"] = "1"
+
+	return c.doReqCheckOK("api/wlan/basic-settings", d)
+}
+
+// WlanSecuritySettings are the WLAN security settings.
+type WlanSecuritySettings struct {
+	// AuthMode is the authentication mode.
+	AuthMode WlanAuthMode
+
+	// Encryption is the WPA encryption mode (ie, "AES", "TKIP" or "MIX").
+	Encryption string
+
+	// Password is the WPA passphrase.
+	Password string
+}
+
+// wlanSecuritySettingsXML is the raw WLAN security settings.
+type wlanSecuritySettingsXML struct {
+	AuthMode   string `xml:"WifiAuthmode"`
+	Encryption string `xml:"WifiWpaencryptionmodes"`
+	Password   string `xml:"WifiWpapsk"`
+}
+
+// WlanSecuritySettings retrieves the WLAN security settings.
+func (c *Client) WlanSecuritySettings() (*WlanSecuritySettings, error) {
+	var x wlanSecuritySettingsXML
+	if err := c.doReqXML("api/wlan/security-settings", nil, &x); err != nil {
+		return nil, err
+	}
+	return &WlanSecuritySettings{
+		AuthMode:   WlanAuthMode(x.AuthMode),
+		Encryption: x.Encryption,
+		Password:   x.Password,
+	}, nil
+}
+
+// WlanSecuritySettingsSet sets the WLAN security settings, retaining the
+// settings not represented by WlanSecuritySettings (ie, WEP keys and WPS).
+// Devices generally restart the WiFi radio, disconnecting clients.
+//
+// Firmware requiring RSA encrypted requests for the endpoint needs the
+// RSAEncryption option.
+func (c *Client) WlanSecuritySettingsSet(s WlanSecuritySettings) (bool, error) {
+	if s.AuthMode != WlanAuthModeOpen && s.AuthMode != WlanAuthModeShare && !validWifiPassword(s.Password) {
+		return false, ErrInvalidWifiPassword
+	}
+
+	d, err := c.Do("api/wlan/security-settings", nil)
+	if err != nil {
+		return false, err
+	}
+	d["WifiAuthmode"] = string(s.AuthMode)
+	if s.Encryption != "" {
+		d["WifiWpaencryptionmodes"] = s.Encryption
+	}
+	if s.AuthMode == WlanAuthModeOpen {
+		d["WifiBasicencryptionmodes"] = "NONE"
+	}
+	d["WifiWpapsk"] = s.Password
+	d["WifiRestart"] = "1"
+
+	return c.doReqCheckOK("api/wlan/security-settings", d)
+}
+
+// WifiPasswordSet sets the WPA passphrase of the WiFi network, retaining the
+// other WLAN security settings.
+func (c *Client) WifiPasswordSet(pw string) (bool, error) {
+	s, err := c.WlanSecuritySettings()
+	if err != nil {
+		return false, err
+	}
+	if s.AuthMode == WlanAuthModeOpen || s.AuthMode == WlanAuthModeShare {
+		s.AuthMode = WlanAuthModeWPA2
+	}
+	s.Password = pw
+
+	return c.WlanSecuritySettingsSet(*s)
+}
+
+// validWifiPassword determines if pw is a valid WPA passphrase: 8 to 63
+// printable ASCII characters, or 64 hex digits.
+func validWifiPassword(pw string) bool {
+	if len(pw) == 64 {
+		_, err := hex.DecodeString(pw)
+		return err == nil
+	}
+	if len(pw) < 8 || len(pw) > 63 {
+		return false
+	}
+	for i := 0; i < len(pw); i++ {
+		if pw[i] < 0x20 || pw[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package hilink
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuestQuotaSet(t *testing.T) {
+	tests := []struct {
+		quota            GuestQuota
+		minutes, limitMB string
+	}{
+		{GuestQuota{}, "0", "0"},
+		{GuestQuota{Duration: time.Hour, DataLimit: 100 << 20}, "60", "100"},
+		{GuestQuota{Duration: 30 * time.Second, DataLimit: 1000}, "1", "1"},
+		{GuestQuota{Duration: 90 * time.Second, DataLimit: 1<<20 + 1}, "2", "2"},
+	}
+	for _, test := range tests {
+		d := newFakeDevice()
+		if _, err := d.client(t).GuestQuotaSet(test.quota); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		req := d.request(t, "/api/wlan/guesttime-setting")
+		if req["guestwifitime"] != test.minutes || req["guestwifidatalimit"] != test.limitMB {
+			t.Errorf("%+v: expected %s minutes and %s MB, got: %v", test.quota, test.minutes, test.limitMB, req)
+		}
+		d.Close()
+	}
+
+	d := newFakeDevice()
+	defer d.Close()
+	if _, err := d.client(t).GuestQuotaSet(GuestQuota{Duration: -time.Minute}); err == nil {
+		t.Error("expected error")
+	}
+}